@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/model"
+)
+
+// listAbsences returns absences of the workspace which end on or after
+// "from" date
+func (api *ComedianAPI) listAbsences(c echo.Context) error {
+	absences, err := api.db.ListWorkspaceAbsences(c.Get("teamID").(string), c.QueryParam("from"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"absences": absences})
+}
+
+func (api *ComedianAPI) createAbsence(c echo.Context) error {
+	var absence model.Absence
+	if err := c.Bind(&absence); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	absence.ID = 0
+	absence.CreatedAt = api.clock.Now().Unix()
+	absence.WorkspaceID = c.Get("teamID").(string)
+	if absence.Source == "" {
+		absence.Source = model.AbsenceManual
+	}
+
+	absence, err := api.db.CreateAbsence(absence)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{"absence": absence})
+}
+
+func (api *ComedianAPI) deleteAbsence(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectID)
+	}
+
+	absence, err := api.db.GetAbsence(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	if absence.WorkspaceID != c.Get("teamID") {
+		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+
+	err = api.db.DeleteAbsence(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusNoContent, "")
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestAbsences(t *testing.T) {
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{}, db, i18n.NewBundle(language.English))
+	api.clock = clock.NewMock(time.Date(2019, 12, 20, 12, 0, 0, 0, time.UTC))
+
+	for _, ws := range []model.Workspace{
+		{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"},
+		{WorkspaceID: "T2", WorkspaceName: "second", BotAccessToken: "token-2", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"},
+	} {
+		_, err := db.CreateWorkspace(ws)
+		require.NoError(t, err)
+	}
+
+	call := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderAuthorization, token)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := call(http.MethodPost, "/v1/absences", "token-1", `{"user_id":"U1","start_date":"2019-12-23","end_date":"2020-01-03","reason":"skiing","workspace_id":"T2"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created struct {
+		Absence model.Absence `json:"absence"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "T1", created.Absence.WorkspaceID)
+	assert.Equal(t, model.AbsenceManual, created.Absence.Source)
+	assert.Equal(t, api.clock.Now().Unix(), created.Absence.CreatedAt)
+
+	rec = call(http.MethodPost, "/v1/absences", "token-1", `{"user_id":"U1","start_date":"2019-12-23","end_date":"2019-12-01"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = call(http.MethodPost, "/v1/absences", "token-1", `{"user_id":"U2","start_date":"2019-12-02","end_date":"2019-12-06"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	list := func(token, query string) []model.Absence {
+		rec := call(http.MethodGet, "/v1/absences"+query, token, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body struct {
+			Absences []model.Absence `json:"absences"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body.Absences
+	}
+
+	assert.Len(t, list("token-1", ""), 2)
+	assert.Empty(t, list("token-2", ""))
+	upcoming := list("token-1", "?from=2019-12-20")
+	require.Len(t, upcoming, 1)
+	assert.Equal(t, "skiing", upcoming[0].Reason)
+
+	path := fmt.Sprintf("/v1/absences/%v", created.Absence.ID)
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodDelete, path, "token-2", "").Code)
+	assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, path, "token-1", "").Code)
+	assert.Equal(t, http.StatusNotFound, call(http.MethodDelete, path, "token-1", "").Code)
+	assert.Len(t, list("token-1", ""), 1)
+}
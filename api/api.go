@@ -16,6 +16,7 @@ import (
 	"github.com/labstack/echo"
 	"github.com/labstack/echo/middleware"
 	"github.com/maddevsio/comedian/botuser"
+	"github.com/maddevsio/comedian/clock"
 	"github.com/maddevsio/comedian/config"
 	"github.com/maddevsio/comedian/model"
 	"github.com/maddevsio/comedian/storage"
@@ -27,10 +28,12 @@ import (
 // ComedianAPI struct used to handle slack requests (slash commands)
 type ComedianAPI struct {
 	echo   *echo.Echo
-	db     *storage.DB
+	db     storage.Store
 	config *config.Config
 	bundle *i18n.Bundle
-	bots   []*botuser.Bot
+	clock  clock.Clock
+	bots   *Registry
+	events chan int64
 }
 
 type swagger struct {
@@ -66,10 +69,10 @@ type teamMember struct {
 }
 
 var echoRouteRegex = regexp.MustCompile(`(?P<start>.*):(?P<param>[^\/]*)(?P<end>.*)`)
-var dbService *storage.DB
+var dbService storage.Store
 
 //New creates API instance
-func New(config *config.Config, db *storage.DB, bundle *i18n.Bundle) *ComedianAPI {
+func New(config *config.Config, db storage.Store, bundle *i18n.Bundle) *ComedianAPI {
 
 	echo := echo.New()
 	echo.Use(middleware.CORS())
@@ -84,25 +87,40 @@ func New(config *config.Config, db *storage.DB, bundle *i18n.Bundle) *ComedianAP
 		echo:   echo,
 		db:     db,
 		config: config,
-		bots:   []*botuser.Bot{},
 		bundle: bundle,
+		clock:  clock.New(),
 	}
+	api.bots = NewRegistry(api.newBot, api.lookupWorkspace)
+	api.events = make(chan int64, config.EventQueueSize)
 
 	echo.GET("/healthcheck", api.healthcheck)
 	echo.POST("/login", api.login)
-	echo.POST("/event", api.handleEvent)
+	echo.POST("/event", api.handleEvent, api.slackPreRequest)
 	echo.POST("/service-message", api.handleServiceMessage)
-	echo.POST("/commands", api.handleCommands)
-	echo.POST("/team-worklogs", api.showTeamWorklogs)
-	echo.POST("/user-commands", api.handleUsersCommands)
+	echo.POST("/commands", api.handleCommands, api.slackPreRequest)
+	echo.POST("/interactions", api.handleInteractions, api.slackPreRequest)
+	echo.POST("/team-worklogs", api.showTeamWorklogs, api.slackPreRequest)
+	echo.POST("/user-commands", api.handleUsersCommands, api.slackPreRequest)
 	echo.GET("/auth", api.auth)
 
+	echo.POST("/mattermost/install", api.installMattermost, api.adminPreRequest)
+	echo.POST("/mattermost/commands", api.handleMattermostCommands)
+	echo.POST("/mattermost/event", api.handleMattermostEvent)
+
+	echo.POST("/telegram/install", api.installTelegram, api.adminPreRequest)
+	echo.POST("/telegram/:workspace", api.handleTelegramUpdate)
+
+	echo.GET("/registry", api.listRegistry, api.adminPreRequest)
+
 	g := echo.Group("/v1")
 	g.Use(AuthPreRequest)
 
 	g.GET("/bots/:id", api.getBot)
 	g.PATCH("/bots/:id", api.updateBot)
 
+	g.GET("/events", api.listEvents)
+	g.GET("/outbox", api.listOutbox)
+
 	g.GET("/standups", api.listStandups)
 	g.GET("/standups/:id", api.getStandup)
 	g.PATCH("/standups/:id", api.updateStandup)
@@ -116,6 +134,24 @@ func New(config *config.Config, db *storage.DB, bundle *i18n.Bundle) *ComedianAP
 	g.PATCH("/standupers/:id", api.updateStanduper)
 	g.DELETE("/standupers/:id", api.deleteStanduper)
 
+	g.GET("/absences", api.listAbsences)
+	g.POST("/absences", api.createAbsence)
+	g.DELETE("/absences/:id", api.deleteAbsence)
+
+	g.GET("/calendars", api.listHolidayCalendars)
+	g.POST("/calendars", api.createHolidayCalendar)
+	g.PATCH("/calendars/:id", api.updateHolidayCalendar)
+	g.DELETE("/calendars/:id", api.deleteHolidayCalendar)
+	g.GET("/calendars/:id/holidays", api.listHolidays)
+	g.POST("/calendars/:id/holidays", api.createHoliday)
+	g.POST("/calendars/:id/import", api.importHolidays)
+	g.DELETE("/holidays/:id", api.deleteHoliday)
+
+	g.GET("/slots", api.listDeadlineSlots)
+	g.POST("/slots", api.createDeadlineSlot)
+	g.PATCH("/slots/:id", api.updateDeadlineSlot)
+	g.DELETE("/slots/:id", api.deleteDeadlineSlot)
+
 	return &api
 }
 
@@ -141,41 +177,26 @@ func AuthPreRequest(next echo.HandlerFunc) echo.HandlerFunc {
 
 //SelectBot returns bot by its team id or teamname if found
 func (api *ComedianAPI) SelectBot(team string) (*botuser.Bot, error) {
-	var bot botuser.Bot
-
-	for _, b := range api.bots {
-		if b.Suits(team) {
-			return b, nil
-		}
-	}
-
-	return &bot, errors.New("bot not found")
+	return api.bots.Get(team)
 }
 
-func (api *ComedianAPI) removeBot(team string) {
-	var index int
-	for i, b := range api.bots {
-		if b.Suits(team) {
-			index = i
-		}
-	}
-
-	api.bots = append(api.bots[:index], api.bots[index+1:]...)
+// newBot creates bot of the workspace which shares api clock
+func (api *ComedianAPI) newBot(settings model.Workspace) *botuser.Bot {
+	bot := botuser.New(api.config, api.bundle, settings, api.db)
+	bot.SetClock(api.clock)
+	return bot
 }
 
 // Start starts http server
 func (api *ComedianAPI) Start() error {
 
-	settings, err := api.db.GetAllWorkspaces()
+	err := api.bots.Sync(api.db.GetAllWorkspaces)
 	if err != nil {
 		return err
 	}
 
-	for _, bs := range settings {
-		bot := botuser.New(api.config, api.bundle, bs, api.db)
-		api.bots = append(api.bots, bot)
-		bot.Start()
-	}
+	api.startRegistrySync()
+	api.startEventWorkers()
 
 	return api.echo.Start(api.config.HTTPBindAddr)
 }
@@ -244,7 +265,7 @@ func (api *ComedianAPI) handleEvent(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if incomingEvent.Token != api.config.SlackVerificationToken {
+	if !api.slackVerified(c, incomingEvent.Token) {
 		return echo.NewHTTPError(http.StatusUnauthorized, "verification token does not match")
 	}
 
@@ -259,6 +280,10 @@ func (api *ComedianAPI) handleEvent(c echo.Context) error {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
 
+		if event.EventID != "" {
+			return api.acceptEvent(c, event, body)
+		}
+
 		err = api.HandleCallbackEvent(event)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -296,7 +321,7 @@ func (api *ComedianAPI) handleCommands(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if !slashCommand.ValidateToken(api.config.SlackVerificationToken) {
+	if !api.slackVerified(c, slashCommand.Token) {
 		return echo.NewHTTPError(http.StatusBadRequest, "wrong verification token")
 	}
 
@@ -321,7 +346,7 @@ func (api *ComedianAPI) handleUsersCommands(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if !slashCommand.ValidateToken(api.config.SlackVerificationToken) {
+	if !api.slackVerified(c, slashCommand.Token) {
 		return c.JSON(http.StatusBadRequest, "Invalid verification token")
 	}
 
@@ -330,7 +355,7 @@ func (api *ComedianAPI) handleUsersCommands(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	today := time.Now()
+	today := api.clock.Now()
 	dateFrom := fmt.Sprintf("%d-%02d-%02d", today.Year(), today.Month(), 1)
 	dateTo := fmt.Sprintf("%d-%02d-%02d", today.Year(), today.Month(), today.Day())
 	dataOnUser, err := bot.GetCollectorData("users", slashCommand.UserID, dateFrom, dateTo)
@@ -361,7 +386,7 @@ func (api *ComedianAPI) HandleEvent(incomingEvent model.ServiceEvent) error {
 func (api *ComedianAPI) HandleCallbackEvent(event slackevents.EventsAPICallbackEvent) error {
 	bot, err := api.SelectBot(event.TeamID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	ev := map[string]interface{}{}
@@ -391,9 +416,41 @@ func (api *ComedianAPI) HandleCallbackEvent(event slackevents.EventsAPICallbackE
 		}
 		_, err := bot.HandleJoin(join)
 		return err
+	case "member_left_channel":
+		left := &slack.MemberLeftChannelEvent{}
+		if err := json.Unmarshal(data, left); err != nil {
+			return err
+		}
+		return bot.HandleMemberLeft(left)
+	case "channel_rename", "group_rename":
+		rename := &slack.ChannelRenameEvent{}
+		if err := json.Unmarshal(data, rename); err != nil {
+			return err
+		}
+		return bot.HandleChannelRename(rename)
+	case "channel_archive", "group_archive", "channel_unarchive", "group_unarchive":
+		archive := &slack.ChannelInfoEvent{}
+		if err := json.Unmarshal(data, archive); err != nil {
+			return err
+		}
+		return bot.HandleChannelArchive(archive.Channel, archive.Type == "channel_archive" || archive.Type == "group_archive")
+	case "channel_left", "group_left":
+		left := &slack.ChannelInfoEvent{}
+		if err := json.Unmarshal(data, left); err != nil {
+			return err
+		}
+		return bot.HandleBotRemoved(left.Channel)
+	case "user_change":
+		change := &slack.UserChangeEvent{}
+		if err := json.Unmarshal(data, change); err != nil {
+			return err
+		}
+		return bot.HandleUserChange(change)
 	case "app_uninstalled":
-		bot.Stop()
-		api.removeBot(event.TeamID)
+		err := api.bots.Stop(bot.Settings().WorkspaceID)
+		if err != nil {
+			log.Error("app_uninstalled failed to stop bot: ", err)
+		}
 		return api.db.DeleteWorkspace(event.TeamID)
 	default:
 		log.WithFields(log.Fields{"event": string(data)}).Warning("unrecognized event!")
@@ -407,7 +464,7 @@ func (api *ComedianAPI) showTeamWorklogs(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if !slashCommand.ValidateToken(api.config.SlackVerificationToken) {
+	if !api.slackVerified(c, slashCommand.Token) {
 		return c.JSON(http.StatusBadRequest, "Invalid verification token")
 	}
 
@@ -427,23 +484,32 @@ func (api *ComedianAPI) showTeamWorklogs(c echo.Context) error {
 
 	channel := standupers[0].ChannelName
 
+	// dates are told in the time zone of the project
+	loc := time.UTC
+	project, err := api.db.SelectProject(slashCommand.ChannelID)
+	if err == nil {
+		if projectLoc, err := time.LoadLocation(project.TZ); err == nil {
+			loc = projectLoc
+		}
+	}
+
 	dates := strings.Split(slashCommand.Text, "-")
 	var from, to time.Time
 
 	if len(dates) == 2 {
 
-		from, err = dateparse.ParseIn(strings.TrimSpace(dates[0]), time.Local)
+		from, err = dateparse.ParseIn(strings.TrimSpace(dates[0]), loc)
 		if err != nil {
 			return c.JSON(http.StatusOK, err)
 		}
 
-		to, err = dateparse.ParseIn(strings.TrimSpace(dates[1]), time.Local)
+		to, err = dateparse.ParseIn(strings.TrimSpace(dates[1]), loc)
 		if err != nil {
 			return c.JSON(http.StatusOK, err)
 		}
 	} else {
-		today := time.Now()
-		from = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.Local)
+		today := api.clock.Now().In(loc)
+		from = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, loc)
 		to = today
 	}
 
@@ -500,18 +566,21 @@ func (api *ComedianAPI) auth(c echo.Context) error {
 	workspaceSettings, err := api.db.GetWorkspaceByWorkspaceID(resp.Team.ID)
 	if err != nil {
 		cp, err := api.db.CreateWorkspace(model.Workspace{
-			CreatedAt:              time.Now().Unix(),
+			CreatedAt:              api.clock.Now().Unix(),
 			BotUserID:              resp.BotUserID,
 			NotifierInterval:       30,
 			Language:               "en",
 			MaxReminders:           3,
 			ReminderOffset:         10,
+			BlockerEscalationDays:  3,
+			TZ:                     "Asia/Bishkek",
 			BotAccessToken:         resp.AccessToken,
 			WorkspaceID:            resp.Team.ID,
 			WorkspaceName:          resp.Team.Name,
 			ReportingChannel:       "",
 			ReportingTime:          "10am",
 			ProjectsReportsEnabled: false,
+			Platform:               model.PlatformSlack,
 		})
 
 		if err != nil {
@@ -519,11 +588,7 @@ func (api *ComedianAPI) auth(c echo.Context) error {
 			return err
 		}
 
-		bot := botuser.New(api.config, api.bundle, cp, api.db)
-
-		api.bots = append(api.bots, bot)
-
-		bot.Start()
+		api.bots.Start(cp)
 
 		return c.Redirect(http.StatusMovedPermanently, api.config.UIurl)
 	}
@@ -537,13 +602,7 @@ func (api *ComedianAPI) auth(c echo.Context) error {
 		return err
 	}
 
-	bot, err := api.SelectBot(resp.Team.ID)
-	if err != nil {
-		log.Error(err)
-		return err
-	}
-
-	bot.SetProperties(&settings)
+	api.bots.Start(settings)
 
 	return c.Redirect(http.StatusMovedPermanently, api.config.UIurl)
 
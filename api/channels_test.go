@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestChannelLifecycleEvents(t *testing.T) {
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{ReplicaID: "replica-1"}, db, i18n.NewBundle(language.English))
+	api.bots.Start(model.Workspace{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", BotUserID: "BOT"})
+	defer api.bots.Stop("T1")
+
+	_, err := db.CreateProject(model.Project{WorkspaceID: "T1", ChannelID: "C1", ChannelName: "general", TZ: "UTC"})
+	require.NoError(t, err)
+	for _, id := range []string{"U1", "U2"} {
+		_, err := db.CreateStanduper(model.Standuper{WorkspaceID: "T1", ChannelID: "C1", ChannelName: "general", UserID: id})
+		require.NoError(t, err)
+	}
+
+	handle := func(inner string) {
+		raw := json.RawMessage(inner)
+		require.NoError(t, api.HandleCallbackEvent(slackevents.EventsAPICallbackEvent{TeamID: "T1", InnerEvent: &raw}), inner)
+	}
+	project := func() model.Project {
+		p, err := db.SelectProject("C1")
+		require.NoError(t, err)
+		return p
+	}
+
+	handle(`{"type":"channel_rename","channel":{"id":"C1","name":"backend","created":1360782804}}`)
+	assert.Equal(t, "backend", project().ChannelName)
+	standupers, err := db.ListProjectStandupers("C1")
+	require.NoError(t, err)
+	for _, s := range standupers {
+		assert.Equal(t, "backend", s.ChannelName)
+	}
+	handle(`{"type":"group_rename","channel":{"id":"C1","name":"secret","created":1360782804}}`)
+	assert.Equal(t, "secret", project().ChannelName)
+
+	handle(`{"type":"member_left_channel","user":"U1","channel":"C1","channel_type":"C","team":"T1"}`)
+	standupers, err = db.ListProjectStandupers("C1")
+	require.NoError(t, err)
+	require.Len(t, standupers, 1)
+	assert.Equal(t, "U2", standupers[0].UserID)
+
+	testCases := []struct {
+		event    string
+		inactive bool
+	}{
+		{`{"type":"channel_archive","channel":"C1","user":"U2"}`, true},
+		{`{"type":"channel_unarchive","channel":"C1","user":"U2"}`, false},
+		{`{"type":"group_archive","channel":"C1"}`, true},
+		{`{"type":"group_unarchive","channel":"C1"}`, false},
+		{`{"type":"channel_left","channel":"C1"}`, true},
+		{`{"type":"member_joined_channel","user":"BOT","channel":"C1","team":"T1"}`, false},
+		{`{"type":"member_left_channel","user":"BOT","channel":"C1","team":"T1"}`, true},
+		{`{"type":"member_joined_channel","user":"BOT","channel":"C1","team":"T1"}`, false},
+		{`{"type":"group_left","channel":"C1"}`, true},
+		// unarchiving does not bring back the channel the bot was removed from
+		{`{"type":"group_archive","channel":"C1"}`, true},
+		{`{"type":"group_unarchive","channel":"C1"}`, true},
+		{`{"type":"member_joined_channel","user":"BOT","channel":"C1","team":"T1"}`, false},
+	}
+	for _, tc := range testCases {
+		handle(tc.event)
+		assert.Equal(t, tc.inactive, project().Inactive, tc.event)
+	}
+
+	// events about channels without standups are ignored
+	handle(`{"type":"channel_archive","channel":"C404"}`)
+	handle(`{"type":"channel_rename","channel":{"id":"C404","name":"random","created":1360782804}}`)
+}
+
+func TestChannelStandupTemplate(t *testing.T) {
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{}, db, i18n.NewBundle(language.English))
+
+	_, err := db.CreateWorkspace(model.Workspace{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"})
+	require.NoError(t, err)
+	channel, err := db.CreateProject(model.Project{WorkspaceID: "T1", ChannelID: "C1", ChannelName: "general", TZ: "UTC"})
+	require.NoError(t, err)
+
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderAuthorization, "token-1")
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		return rec
+	}
+	path := fmt.Sprintf("/v1/channels/%v", channel.ID)
+
+	rec := call(http.MethodPatch, path, `{"standup_template":[{"name":"Done","keywords":["done"],"required":true},{"name":"Blocked","pattern":"block(ed|er)"}]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	channel, err = db.GetProject(channel.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StandupTemplate{
+		{Name: "Done", Keywords: []string{"done"}, Required: true},
+		{Name: "Blocked", Pattern: "block(ed|er)"},
+	}, channel.StandupTemplate)
+
+	rec = call(http.MethodPatch, path, `{"standup_template":[{"name":"Done","pattern":"("}]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `section \"Done\" has invalid pattern`)
+	rec = call(http.MethodPatch, path, `{"standup_template":[{"name":"Done"}]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// other fields keep the template
+	rec = call(http.MethodPatch, path, `{"deadline":"10:00"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = call(http.MethodGet, "/v1/channels", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"standup_template":[{"name":"Done","keywords":["done"],"required":true},{"name":"Blocked","pattern":"block(ed|er)","required":false}]`)
+
+	rec = call(http.MethodPatch, path, `{"standup_template":[]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	channel, err = db.GetProject(channel.ID)
+	require.NoError(t, err)
+	assert.Empty(t, channel.StandupTemplate)
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack/slackevents"
+)
+
+const (
+	// eventClaimTime is how long a worker holds an event. Events of a
+	// crashed worker are picked up again after it
+	eventClaimTime = 5 * time.Minute
+	// eventRetryInterval is how often failed events are looked for
+	eventRetryInterval = 10 * time.Second
+	// eventRetryDelay is the delay before the first retry, it doubles with every attempt
+	eventRetryDelay = 10 * time.Second
+	// eventPurgeInterval is how often done events older than EVENT_RETENTION
+	// days are deleted
+	eventPurgeInterval = time.Hour
+	eventBatch         = 100
+	eventListLimit     = 100
+)
+
+const slackRetryHeader = "X-Slack-Retry-Num"
+
+// acceptEvent records Slack callback event and queues it for processing.
+// Redelivered events are acknowledged and dropped
+func (api *ComedianAPI) acceptEvent(c echo.Context, event slackevents.EventsAPICallbackEvent, body []byte) error {
+	var inner struct {
+		Type string `json:"type"`
+	}
+	if event.InnerEvent != nil {
+		json.Unmarshal(*event.InnerEvent, &inner)
+	}
+
+	payload, err := stripVerificationToken(body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	now := api.clock.Now().Unix()
+	e, created, err := api.db.RecordEvent(model.Event{
+		EventID:       event.EventID,
+		WorkspaceID:   event.TeamID,
+		Type:          inner.Type,
+		Payload:       string(payload),
+		Status:        model.EventPending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"event_id": event.EventID, "error": err}).Error("RecordEvent failed")
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if !created {
+		log.WithFields(log.Fields{
+			"event_id": event.EventID,
+			"retry":    c.Request().Header.Get(slackRetryHeader),
+			"status":   e.Status,
+		}).Info("duplicate event dropped")
+		return c.JSON(http.StatusOK, "Duplicate")
+	}
+
+	api.enqueueEvent(e.ID)
+	return c.JSON(http.StatusOK, "Success")
+}
+
+// stripVerificationToken drops the app-wide verification token from the
+// event envelope, so it is never stored along with the event
+func stripVerificationToken(body []byte) ([]byte, error) {
+	var envelope map[string]json.RawMessage
+	err := json.Unmarshal(body, &envelope)
+	if err != nil {
+		return nil, err
+	}
+	delete(envelope, "token")
+	return json.Marshal(envelope)
+}
+
+// enqueueEvent passes event to workers. When the queue is full the event
+// stays pending and is picked up by retryEvents
+func (api *ComedianAPI) enqueueEvent(id int64) {
+	select {
+	case api.events <- id:
+	default:
+		log.Warning("event queue is full, event ", id, " is postponed")
+	}
+}
+
+// startEventWorkers starts the pool of workers which process queued events
+// and the loop which queues events due for retry
+func (api *ComedianAPI) startEventWorkers() {
+	workers := api.config.EventWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for id := range api.events {
+				api.processEvent(id)
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(eventRetryInterval)
+		defer ticker.Stop()
+		purge := time.NewTicker(eventPurgeInterval)
+		defer purge.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				api.retryEvents()
+			case <-purge.C:
+				api.purgeEvents()
+			}
+		}
+	}()
+}
+
+// purgeEvents deletes done events older than EVENT_RETENTION days. Failed
+// events are kept for inspection
+func (api *ComedianAPI) purgeEvents() {
+	if api.config.EventRetention <= 0 {
+		return
+	}
+	before := api.clock.Now().Add(-time.Duration(api.config.EventRetention) * 24 * time.Hour).Unix()
+	err := api.db.DeleteDoneEvents(before)
+	if err != nil {
+		log.Error("DeleteDoneEvents failed: ", err)
+	}
+}
+
+// retryEvents queues events which failed, were postponed or were held by
+// a crashed worker
+func (api *ComedianAPI) retryEvents() {
+	events, err := api.db.ListDueEvents(api.clock.Now().Unix(), api.maxEventAttempts(), eventBatch)
+	if err != nil {
+		log.Error("ListDueEvents failed: ", err)
+		return
+	}
+	for _, e := range events {
+		api.enqueueEvent(e.ID)
+	}
+}
+
+// processEvent handles the event unless another worker has claimed it or
+// it is done already. Failed events are retried with growing delay until
+// they run out of attempts
+func (api *ComedianAPI) processEvent(id int64) {
+	now := api.clock.Now()
+	claimed, err := api.db.ClaimEvent(id, now.Unix(), now.Add(eventClaimTime).Unix())
+	if err != nil {
+		log.Error("ClaimEvent failed: ", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	e, err := api.db.GetEvent(id)
+	if err != nil {
+		log.Error("GetEvent failed: ", err)
+		return
+	}
+
+	var event slackevents.EventsAPICallbackEvent
+	err = json.Unmarshal([]byte(e.Payload), &event)
+	if err == nil {
+		err = api.HandleCallbackEvent(event)
+	}
+
+	if err != nil {
+		e.Status = model.EventFailed
+		e.Error = err.Error()
+		e.NextAttemptAt = api.clock.Now().Add(eventRetryDelay << uint(e.Attempts-1)).Unix()
+		log.WithFields(log.Fields{"event_id": e.EventID, "attempts": e.Attempts, "error": err}).Error("event processing failed")
+	} else {
+		e.Status = model.EventDone
+		e.Error = ""
+		e.NextAttemptAt = 0
+	}
+
+	_, err = api.db.UpdateEvent(e)
+	if err != nil {
+		log.Error("UpdateEvent failed: ", err)
+	}
+}
+
+func (api *ComedianAPI) maxEventAttempts() int {
+	if api.config.EventMaxAttempts < 1 {
+		return 1
+	}
+	return api.config.EventMaxAttempts
+}
+
+// listEvents shows latest events of the workspace, optionally filtered by status
+func (api *ComedianAPI) listEvents(c echo.Context) error {
+	events, err := api.db.ListWorkspaceEvents(c.Get("teamID").(string), c.QueryParam("status"), eventListLimit)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error":    err,
+			"fucntion": "api.db.ListWorkspaceEvents",
+			"data":     c.Get("teamID")},
+		).Error("listEvents failed")
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"events": events})
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestEvents(t *testing.T) {
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{SlackVerificationToken: "verification-token", EventQueueSize: 2, EventMaxAttempts: 3, EventWorkers: 2}, db, i18n.NewBundle(language.English))
+	clk := clock.NewMock(time.Date(2019, 11, 4, 10, 0, 0, 0, time.UTC))
+	api.clock = clk
+
+	_, err := db.CreateWorkspace(model.Workspace{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"})
+	require.NoError(t, err)
+	// only the first workspace is installed, events of the second one fail
+	api.bots.Start(model.Workspace{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", BotUserID: "BOT"})
+	defer api.bots.StopAll()
+
+	post := func(eventID, teamID string, retry int) string {
+		body := fmt.Sprintf(`{"token":"verification-token","type":"event_callback","event_id":%q,"team_id":%q,
+			"event":{"type":"message","channel":"C1","user":"U1","text":"hello","ts":"1.1"}}`, eventID, teamID)
+		req := httptest.NewRequest(http.MethodPost, "/event", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if retry > 0 {
+			req.Header.Set(slackRetryHeader, fmt.Sprint(retry))
+		}
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		return strings.TrimSpace(rec.Body.String())
+	}
+
+	event := func(eventID string) model.Event {
+		for _, team := range []string{"T1", "T2"} {
+			events, err := db.ListWorkspaceEvents(team, "", 100)
+			require.NoError(t, err)
+			for _, e := range events {
+				if e.EventID == eventID {
+					return e
+				}
+			}
+		}
+		return model.Event{}
+	}
+
+	// event is acknowledged before it is processed, retries are dropped
+	assert.Equal(t, `"Success"`, post("Ev1", "T1", 0))
+	assert.Equal(t, model.EventPending, event("Ev1").Status)
+	assert.Equal(t, `"Duplicate"`, post("Ev1", "T1", 1))
+	require.Len(t, api.events, 1)
+
+	api.processEvent(<-api.events)
+	assert.Equal(t, model.EventDone, event("Ev1").Status)
+	assert.Equal(t, 1, event("Ev1").Attempts)
+	// verification token is not stored with the event
+	assert.NotContains(t, event("Ev1").Payload, "verification-token")
+	assert.Contains(t, event("Ev1").Payload, `"event_id":"Ev1"`)
+
+	// done event is not processed again even if it is queued again
+	api.processEvent(event("Ev1").ID)
+	assert.Equal(t, 1, event("Ev1").Attempts)
+	assert.Equal(t, `"Duplicate"`, post("Ev1", "T1", 2))
+
+	// failed event is retried with growing delay until it runs out of attempts
+	post("Ev2", "T2", 0)
+	api.processEvent(<-api.events)
+	failed := event("Ev2")
+	assert.Equal(t, model.EventFailed, failed.Status)
+	assert.Equal(t, "bot not found", failed.Error)
+	assert.Equal(t, clk.Now().Add(10*time.Second).Unix(), failed.NextAttemptAt)
+
+	api.retryEvents()
+	assert.Empty(t, api.events, "retry is not due yet")
+
+	clk.Add(10 * time.Second)
+	api.retryEvents()
+	require.Len(t, api.events, 1)
+	api.processEvent(<-api.events)
+	assert.Equal(t, 2, event("Ev2").Attempts)
+	assert.Equal(t, clk.Now().Add(20*time.Second).Unix(), event("Ev2").NextAttemptAt)
+
+	clk.Add(20 * time.Second)
+	api.retryEvents()
+	api.processEvent(<-api.events)
+	assert.Equal(t, 3, event("Ev2").Attempts)
+
+	clk.Add(time.Hour)
+	api.retryEvents()
+	assert.Empty(t, api.events, "event ran out of attempts")
+	assert.Equal(t, model.EventFailed, event("Ev2").Status)
+
+	// events which do not fit into the queue are postponed, not lost
+	post("Ev3", "T1", 0)
+	post("Ev4", "T1", 0)
+	post("Ev5", "T1", 0)
+	assert.Len(t, api.events, 2)
+	assert.Equal(t, model.EventPending, event("Ev5").Status)
+
+	api.startEventWorkers()
+	done := func(eventIDs ...string) bool {
+		for i := 0; i < 100; i++ {
+			pending := 0
+			for _, id := range eventIDs {
+				if event(id).Status != model.EventDone {
+					pending++
+				}
+			}
+			if pending == 0 {
+				return true
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		return false
+	}
+	require.True(t, done("Ev3", "Ev4"))
+	api.retryEvents()
+	require.True(t, done("Ev5"))
+
+	// failed events can be inspected, the second workspace is installed meanwhile
+	_, err = db.CreateWorkspace(model.Workspace{WorkspaceID: "T2", WorkspaceName: "second", BotAccessToken: "token-2", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"})
+	require.NoError(t, err)
+	list := func(token, query string) []model.Event {
+		req := httptest.NewRequest(http.MethodGet, "/v1/events"+query, nil)
+		req.Header.Set(echo.HeaderAuthorization, token)
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		assert.NotContains(t, rec.Body.String(), "payload")
+
+		var body struct {
+			Events []model.Event `json:"events"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body.Events
+	}
+
+	assert.Len(t, list("token-1", ""), 4)
+	assert.Empty(t, list("token-1", "?status=failed"))
+	failedEvents := list("token-2", "?status=failed")
+	require.Len(t, failedEvents, 1)
+	assert.Equal(t, "Ev2", failedEvents[0].EventID)
+	assert.Equal(t, "bot not found", failedEvents[0].Error)
+
+	// done events are purged after EVENT_RETENTION days, failed ones are kept
+	api.config.EventRetention = 7
+	clk.Add(7*24*time.Hour + time.Hour)
+	api.purgeEvents()
+	assert.Empty(t, list("token-1", ""))
+	assert.Len(t, list("token-2", ""), 1)
+}
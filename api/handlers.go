@@ -5,6 +5,8 @@ import (
 	"strconv"
 
 	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/botuser"
+	"github.com/maddevsio/comedian/model"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -60,15 +62,28 @@ func (api *ComedianAPI) updateBot(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusForbidden, accessDenied)
 	}
 
-	if err := c.Bind(&settings); err != nil {
+	update := settings
+	if err := c.Bind(&update); err != nil {
 		log.WithFields(log.Fields{
 			"error":    err,
-			"fucntion": "c.Bind(&settings)",
-			"data":     settings},
+			"fucntion": "c.Bind(&update)",
+			"data":     update},
 		).Error("updateBot failed")
 		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
 	}
 
+	// identity, platform and credentials of the workspace are set on
+	// install only
+	settings.NotifierInterval = update.NotifierInterval
+	settings.Language = update.Language
+	settings.MaxReminders = update.MaxReminders
+	settings.ReminderOffset = update.ReminderOffset
+	settings.ReportingChannel = update.ReportingChannel
+	settings.ReportingTime = update.ReportingTime
+	settings.ProjectsReportsEnabled = update.ProjectsReportsEnabled
+	settings.BlockerEscalationDays = update.BlockerEscalationDays
+	settings.TZ = update.TZ
+
 	res, err := api.db.UpdateWorkspace(settings)
 	if err != nil {
 		log.WithFields(log.Fields{
@@ -79,20 +94,7 @@ func (api *ComedianAPI) updateBot(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	for _, b := range api.bots {
-		log.Info("Bot languages before update: ", b.Settings())
-	}
-
-	bot, err := api.SelectBot(settings.WorkspaceName)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
-	}
-
-	bot.SetProperties(&res)
-
-	for _, b := range api.bots {
-		log.Info("Bot languages after update: ", b.Settings())
-	}
+	api.bots.Start(res)
 
 	return c.JSON(http.StatusOK, map[string]interface{}{"bot": res})
 }
@@ -113,17 +115,48 @@ func (api *ComedianAPI) getStandup(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{"standup": standup})
+	answers, err := api.db.ListStandupAnswers(standup.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"standup": standupResponse{standup, answers}})
+}
+
+// standupResponse is a standup with answers to sections of the template,
+// the raw text is in its comment
+type standupResponse struct {
+	model.Standup
+	Answers []model.StandupAnswer `json:"answers"`
 }
 
 func (api *ComedianAPI) listStandups(c echo.Context) error {
+	teamID := c.Get("teamID").(string)
 
-	standups, err := api.db.ListTeamStandups(c.Get("teamID").(string))
+	standups, err := api.db.ListTeamStandups(teamID)
 	if err != nil {
 		echo.NewHTTPError(http.StatusUnauthorized, somethingWentWrong)
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{"standups": standups})
+	answers, err := api.db.ListTeamStandupAnswers(teamID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+	byStandup := map[int64][]model.StandupAnswer{}
+	for _, a := range answers {
+		byStandup[a.StandupID] = append(byStandup[a.StandupID], a)
+	}
+
+	items := make([]standupResponse, 0, len(standups))
+	for _, standup := range standups {
+		standupAnswers := byStandup[standup.ID]
+		if standupAnswers == nil {
+			standupAnswers = []model.StandupAnswer{}
+		}
+		items = append(items, standupResponse{standup, standupAnswers})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"standups": items})
 }
 
 func (api *ComedianAPI) updateStandup(c echo.Context) error {
@@ -136,6 +169,7 @@ func (api *ComedianAPI) updateStandup(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
 	}
+	comment := standup.Comment
 
 	if err := c.Bind(&standup); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
@@ -150,7 +184,44 @@ func (api *ComedianAPI) updateStandup(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{"standup": standup})
+	if standup.Comment != comment {
+		err = api.splitStandup(standup)
+		if err != nil {
+			log.WithFields(log.Fields{"standup": standup.ID, "error": err}).Error("splitStandup failed")
+			return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+		}
+	}
+
+	answers, err := api.db.ListStandupAnswers(standup.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"standup": standupResponse{standup, answers}})
+}
+
+// splitStandup replaces answers and blockers of the edited standup with
+// sections found in its new text by the channel template
+func (api *ComedianAPI) splitStandup(standup model.Standup) error {
+	template := model.DefaultStandupTemplate
+	channel, err := api.db.SelectProject(standup.ChannelID)
+	if err == nil && len(channel.StandupTemplate) > 0 {
+		template = channel.StandupTemplate
+	}
+
+	err = api.db.DeleteStandupAnswers(standup.ID)
+	if err != nil {
+		return err
+	}
+	answers := template.Split(standup.Comment)
+	for _, a := range answers {
+		a.StandupID = standup.ID
+		_, err = api.db.CreateStandupAnswer(a)
+		if err != nil {
+			return err
+		}
+	}
+	return botuser.SyncBlockers(api.db, template, standup, answers, api.clock.Now())
 }
 
 func (api *ComedianAPI) deleteStandup(c echo.Context) error {
@@ -197,14 +268,25 @@ func (api *ComedianAPI) updateChannel(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
 	}
 
+	// standup template is replaced as a whole, not merged section by section
+	template := channel.StandupTemplate
+	channel.StandupTemplate = nil
 	if err := c.Bind(&channel); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
 	}
+	if channel.StandupTemplate == nil {
+		channel.StandupTemplate = template
+	}
 
 	if channel.WorkspaceID != c.Get("teamID") {
 		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
 	}
 
+	channel.SubmissionDays, err = model.NormalizeWeekdays(channel.SubmissionDays)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
 	channel, err = api.db.UpdateProject(channel)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -265,6 +347,11 @@ func (api *ComedianAPI) updateStanduper(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
 	}
 
+	standuper.SubmissionDays, err = model.NormalizeWeekdays(standuper.SubmissionDays)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
 	standuper, err = api.db.UpdateStanduper(standuper)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
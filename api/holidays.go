@@ -0,0 +1,231 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/ical"
+	"github.com/maddevsio/comedian/model"
+)
+
+// maxCalendarSize limits size of imported iCalendar files
+const maxCalendarSize = 1 << 20
+
+// holidayImportYears is how many years ahead yearly events are imported for
+const holidayImportYears = 2
+
+func (api *ComedianAPI) listHolidayCalendars(c echo.Context) error {
+	calendars, err := api.db.ListWorkspaceHolidayCalendars(c.Get("teamID").(string))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"calendars": calendars})
+}
+
+func (api *ComedianAPI) createHolidayCalendar(c echo.Context) error {
+	var calendar model.HolidayCalendar
+	if err := c.Bind(&calendar); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	calendar.ID = 0
+	calendar.CreatedAt = api.clock.Now().Unix()
+	calendar.WorkspaceID = c.Get("teamID").(string)
+	if err := api.checkCalendarChannel(calendar); err != nil {
+		return err
+	}
+
+	calendar, err := api.db.CreateHolidayCalendar(calendar)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{"calendar": calendar})
+}
+
+// updateHolidayCalendar renames the calendar or attaches it to another
+// channel, empty channel_id attaches it to the whole workspace
+func (api *ComedianAPI) updateHolidayCalendar(c echo.Context) error {
+	calendar, err := api.getHolidayCalendar(c)
+	if err != nil {
+		return err
+	}
+
+	id, workspaceID, createdAt := calendar.ID, calendar.WorkspaceID, calendar.CreatedAt
+	if err := c.Bind(&calendar); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+	calendar.ID, calendar.WorkspaceID, calendar.CreatedAt = id, workspaceID, createdAt
+
+	if err := api.checkCalendarChannel(calendar); err != nil {
+		return err
+	}
+
+	calendar, err = api.db.UpdateHolidayCalendar(calendar)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"calendar": calendar})
+}
+
+func (api *ComedianAPI) deleteHolidayCalendar(c echo.Context) error {
+	calendar, err := api.getHolidayCalendar(c)
+	if err != nil {
+		return err
+	}
+
+	err = api.db.DeleteHolidayCalendar(calendar.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusNoContent, "")
+}
+
+func (api *ComedianAPI) listHolidays(c echo.Context) error {
+	calendar, err := api.getHolidayCalendar(c)
+	if err != nil {
+		return err
+	}
+
+	holidays, err := api.db.ListCalendarHolidays(calendar.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"holidays": holidays})
+}
+
+func (api *ComedianAPI) createHoliday(c echo.Context) error {
+	calendar, err := api.getHolidayCalendar(c)
+	if err != nil {
+		return err
+	}
+
+	var holiday model.Holiday
+	if err := c.Bind(&holiday); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+	holiday.ID = 0
+	holiday.CalendarID = calendar.ID
+
+	holiday, err = api.db.CreateHoliday(holiday)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{"holiday": holiday})
+}
+
+// importHolidays adds days of events from iCalendar file in request body
+// to the calendar. Days which are in the calendar already are left as they are
+func (api *ComedianAPI) importHolidays(c echo.Context) error {
+	calendar, err := api.getHolidayCalendar(c)
+	if err != nil {
+		return err
+	}
+
+	events, err := ical.Parse(io.LimitReader(c.Request().Body, maxCalendarSize))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	holidays, err := api.db.ListCalendarHolidays(calendar.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+	known := map[string]bool{}
+	for _, h := range holidays {
+		known[h.Date] = true
+	}
+
+	until := api.clock.Now().AddDate(holidayImportYears, 0, 0)
+	imported := []model.Holiday{}
+	for _, event := range events {
+		for _, day := range event.Days(until) {
+			date := day.Format(model.DateLayout)
+			if known[date] {
+				continue
+			}
+			known[date] = true
+
+			holiday, err := api.db.CreateHoliday(model.Holiday{CalendarID: calendar.ID, Date: date, Name: event.Summary})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+			}
+			imported = append(imported, holiday)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"holidays": imported})
+}
+
+func (api *ComedianAPI) deleteHoliday(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectID)
+	}
+
+	holiday, err := api.db.GetHoliday(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	calendar, err := api.db.GetHolidayCalendar(holiday.CalendarID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	if calendar.WorkspaceID != c.Get("teamID") {
+		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+
+	err = api.db.DeleteHoliday(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusNoContent, "")
+}
+
+// getHolidayCalendar returns calendar by id from the path if it belongs to
+// the workspace of the request
+func (api *ComedianAPI) getHolidayCalendar(c echo.Context) (model.HolidayCalendar, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		return model.HolidayCalendar{}, echo.NewHTTPError(http.StatusBadRequest, incorrectID)
+	}
+
+	calendar, err := api.db.GetHolidayCalendar(id)
+	if err != nil {
+		return calendar, echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	if calendar.WorkspaceID != c.Get("teamID") {
+		return calendar, echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+
+	return calendar, nil
+}
+
+// checkCalendarChannel makes sure calendar is attached to a channel of its workspace
+func (api *ComedianAPI) checkCalendarChannel(calendar model.HolidayCalendar) error {
+	if calendar.ChannelID == "" {
+		return nil
+	}
+
+	channel, err := api.db.SelectProject(calendar.ChannelID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Channel does not exist")
+	}
+
+	if channel.WorkspaceID != calendar.WorkspaceID {
+		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+const kgHolidays = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+DTSTART;VALUE=DATE:20200101
+DTEND;VALUE=DATE:20200103
+SUMMARY:New Year
+END:VEVENT
+BEGIN:VEVENT
+DTSTART;VALUE=DATE:20200107
+SUMMARY:Orthodox Christmas
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestHolidayCalendars(t *testing.T) {
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{}, db, i18n.NewBundle(language.English))
+	api.clock = clock.NewMock(time.Date(2019, 12, 20, 12, 0, 0, 0, time.UTC))
+
+	for _, ws := range []model.Workspace{
+		{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"},
+		{WorkspaceID: "T2", WorkspaceName: "second", BotAccessToken: "token-2", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"},
+	} {
+		_, err := db.CreateWorkspace(ws)
+		require.NoError(t, err)
+	}
+	for _, p := range []model.Project{
+		{WorkspaceID: "T1", ChannelID: "C1", ChannelName: "general", TZ: "UTC"},
+		{WorkspaceID: "T2", ChannelID: "C2", ChannelName: "random", TZ: "UTC"},
+	} {
+		_, err := db.CreateProject(p)
+		require.NoError(t, err)
+	}
+
+	call := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderAuthorization, token)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := call(http.MethodPost, "/v1/calendars", "token-1", `{"name":"Kyrgyzstan","workspace_id":"T2"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created struct {
+		Calendar model.HolidayCalendar `json:"calendar"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "T1", created.Calendar.WorkspaceID)
+
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, "/v1/calendars", "token-1", `{"name":""}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, "/v1/calendars", "token-1", `{"name":"Office","channel_id":"C404"}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodPost, "/v1/calendars", "token-1", `{"name":"Office","channel_id":"C2"}`).Code)
+
+	path := fmt.Sprintf("/v1/calendars/%v", created.Calendar.ID)
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodPatch, path, "token-2", `{"channel_id":"C2"}`).Code)
+	rec = call(http.MethodPatch, path, "token-1", `{"channel_id":"C1","workspace_id":"T2"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	calendar, err := db.GetHolidayCalendar(created.Calendar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.HolidayCalendar{ID: calendar.ID, CreatedAt: api.clock.Now().Unix(), WorkspaceID: "T1", ChannelID: "C1", Name: "Kyrgyzstan"}, calendar)
+
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, path+"/import", "token-1", "hello").Code)
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodPost, path+"/import", "token-2", kgHolidays).Code)
+
+	rec = call(http.MethodPost, path+"/holidays", "token-1", `{"date":"2020-01-02","name":"Day off"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = call(http.MethodPost, path+"/import", "token-1", kgHolidays)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	list := func(token string) []model.Holiday {
+		rec := call(http.MethodGet, path+"/holidays", token, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body struct {
+			Holidays []model.Holiday `json:"holidays"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body.Holidays
+	}
+
+	holidays := list("token-1")
+	require.Len(t, holidays, 3)
+	assert.Equal(t, "2020-01-01 New Year", holidays[0].Date+" "+holidays[0].Name)
+	assert.Equal(t, "2020-01-02 Day off", holidays[1].Date+" "+holidays[1].Name)
+	assert.Equal(t, "2020-01-07 Orthodox Christmas", holidays[2].Date+" "+holidays[2].Name)
+
+	// import of the same file adds nothing
+	rec = call(http.MethodPost, path+"/import", "token-1", kgHolidays)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, list("token-1"), 3)
+
+	holidayPath := fmt.Sprintf("/v1/holidays/%v", holidays[1].ID)
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodDelete, holidayPath, "token-2", "").Code)
+	assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, holidayPath, "token-1", "").Code)
+	assert.Len(t, list("token-1"), 2)
+
+	rec = call(http.MethodGet, "/v1/calendars", "token-2", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"calendars":[]}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, path, "token-1", "").Code)
+	assert.Equal(t, http.StatusNotFound, call(http.MethodGet, path+"/holidays", "token-1", "").Code)
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// handleInteractions receives interactions with Slack modals and message
+// buttons, like submission of the standup modal or resolve button of a
+// blocker. Slack sends them as payload form field
+func (api *ComedianAPI) handleInteractions(c echo.Context) error {
+	var callback slack.InteractionCallback
+	err := json.Unmarshal([]byte(c.FormValue("payload")), &callback)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if !api.slackVerified(c, callback.Token) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "verification token does not match")
+	}
+
+	bot, err := api.SelectBot(callback.Team.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	switch callback.Type {
+	case slack.InteractionTypeViewSubmission:
+		response, err := bot.HandleViewSubmission(&callback)
+		if err != nil {
+			log.WithFields(log.Fields{"team": callback.Team.ID, "user": callback.User.ID, "error": err}).Error("HandleViewSubmission failed")
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		if response != nil {
+			return c.JSON(http.StatusOK, response)
+		}
+	case slack.InteractionTypeInteractionMessage:
+		message, err := bot.HandleBlockerAction(&callback)
+		if err != nil {
+			log.WithFields(log.Fields{"team": callback.Team.ID, "user": callback.User.ID, "error": err}).Error("HandleBlockerAction failed")
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		if message != nil {
+			return c.JSON(http.StatusOK, message)
+		}
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestInteractions(t *testing.T) {
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{ReplicaID: "replica-1", SlackVerificationToken: "secret"}, db, i18n.NewBundle(language.English))
+	api.bots.Start(model.Workspace{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", BotUserID: "BOT", Language: "en"})
+	defer api.bots.Stop("T1")
+
+	call := func(payload string) *httptest.ResponseRecorder {
+		form := url.Values{"payload": {payload}}
+		req := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusBadRequest, call("not json").Code)
+	assert.Equal(t, http.StatusUnauthorized, call(`{"type":"view_submission","token":"wrong","team":{"id":"T1"}}`).Code)
+	assert.Equal(t, http.StatusInternalServerError, call(`{"type":"view_submission","token":"secret","team":{"id":"T404"}}`).Code)
+	assert.Equal(t, http.StatusOK, call(`{"type":"block_actions","token":"secret","team":{"id":"T1"}}`).Code)
+
+	_, err := db.CreateStanduper(model.Standuper{WorkspaceID: "T1", ChannelID: "C1", UserID: "U1"})
+	require.NoError(t, err)
+	rec := call(`{"type":"view_submission","token":"secret","team":{"id":"T1"},"user":{"id":"U1"},"view":{
+		"callback_id":"standup",
+		"private_metadata":"{\"channel_id\":\"C1\",\"questions\":[{\"name\":\"Done\",\"required\":true}]}",
+		"state":{"values":{"question_0":{"answer":{"type":"plain_text_input","value":""}}}}
+	}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"response_action":"errors","errors":{"question_0":"Please answer this question"}}`, rec.Body.String())
+
+	_, err = db.CreateStanduper(model.Standuper{WorkspaceID: "T1", ChannelID: "C1", UserID: "PM", Role: "pm"})
+	require.NoError(t, err)
+	blocker, err := db.CreateBlocker(model.Blocker{WorkspaceID: "T1", ChannelID: "C1", UserID: "U1", Text: "waiting for API keys", Status: model.BlockerOpen})
+	require.NoError(t, err)
+	rec = call(fmt.Sprintf(`{"type":"interactive_message","token":"secret","team":{"id":"T1"},"user":{"id":"PM"},"callback_id":"blocker",
+		"actions":[{"name":"resolve","value":"%v"}],
+		"original_message":{"text":"Open blockers","attachments":[{"id":1,"text":"waiting for API keys","actions":[{"name":"resolve","value":"%v"}]}]}
+	}`, blocker.ID, blocker.ID))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"footer":"Resolved by \u003c@PM\u003e"`)
+	assert.NotContains(t, rec.Body.String(), `"actions"`)
+	blocker, err = db.GetBlocker(blocker.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.BlockerResolved, blocker.Status)
+}
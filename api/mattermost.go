@@ -0,0 +1,220 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/botuser"
+	"github.com/maddevsio/comedian/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// MattermostWebhook is a payload Mattermost outgoing webhook sends when
+// a post matches its trigger word
+type MattermostWebhook struct {
+	Token       string `json:"token" form:"token"`
+	TeamID      string `json:"team_id" form:"team_id"`
+	TeamDomain  string `json:"team_domain" form:"team_domain"`
+	ChannelID   string `json:"channel_id" form:"channel_id"`
+	ChannelName string `json:"channel_name" form:"channel_name"`
+	Timestamp   int64  `json:"timestamp" form:"timestamp"`
+	UserID      string `json:"user_id" form:"user_id"`
+	UserName    string `json:"user_name" form:"user_name"`
+	PostID      string `json:"post_id" form:"post_id"`
+	Text        string `json:"text" form:"text"`
+	TriggerWord string `json:"trigger_word" form:"trigger_word"`
+}
+
+// MattermostInstall is a request to connect Comedian to Mattermost team
+type MattermostInstall struct {
+	ServerURL      string `json:"server_url"`
+	BotAccessToken string `json:"bot_access_token"`
+	TeamID         string `json:"team_id"`
+	Language       string `json:"language"`
+}
+
+// handleMattermostCommands handles Mattermost slash commands. Mattermost sends
+// them with the same form fields Slack does
+func (api *ComedianAPI) handleMattermostCommands(c echo.Context) error {
+	slashCommand, err := slack.SlashCommandParse(c.Request())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if !api.validMattermostToken(slashCommand.TeamID, slashCommand.Token) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "wrong verification token")
+	}
+
+	bot, err := api.SelectBot(slashCommand.TeamID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error":    err,
+			"fucntion": "select bot",
+			"data":     slashCommand},
+		).Error("handleMattermostCommands failed")
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	message := bot.ImplementCommands(slashCommand)
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"response_type": "ephemeral",
+		"text":          message,
+	})
+}
+
+// handleMattermostEvent handles posts sent by Mattermost outgoing webhook.
+// Trigger word plays the role of bot mention, so the post is turned into
+// Slack message event mentioning the bot
+func (api *ComedianAPI) handleMattermostEvent(c echo.Context) error {
+	var hook MattermostWebhook
+	if err := c.Bind(&hook); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	if !api.validMattermostToken(hook.TeamID, hook.Token) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "wrong verification token")
+	}
+
+	bot, err := api.SelectBot(hook.TeamID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	botUserID := bot.Settings().BotUserID
+	if hook.UserID == botUserID {
+		return c.JSON(http.StatusOK, map[string]string{})
+	}
+
+	text := hook.Text
+	mention := fmt.Sprintf("<@%v>", botUserID)
+	if hook.TriggerWord != "" && !strings.Contains(text, botUserID) {
+		text = strings.Replace(text, hook.TriggerWord, mention, 1)
+	}
+
+	message := &slack.MessageEvent{
+		Msg: slack.Msg{
+			Type:      "message",
+			Channel:   hook.ChannelID,
+			User:      hook.UserID,
+			Text:      text,
+			Timestamp: hook.PostID,
+		},
+	}
+
+	err = bot.HandleMessage(message)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{})
+}
+
+// installMattermost connects Comedian to Mattermost team using access token
+// of a bot account created in that team. Only servers listed in
+// MATTERMOST_SERVERS are called, and a workspace of another platform or
+// server is never taken over
+func (api *ComedianAPI) installMattermost(c echo.Context) error {
+	var install MattermostInstall
+	if err := c.Bind(&install); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	if install.ServerURL == "" || install.BotAccessToken == "" || install.TeamID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "server_url, bot_access_token and team_id are required")
+	}
+
+	install.ServerURL = strings.TrimRight(install.ServerURL, "/")
+	if !api.allowedMattermostServer(install.ServerURL) {
+		return echo.NewHTTPError(http.StatusForbidden, "server_url is not listed in MATTERMOST_SERVERS")
+	}
+
+	client := botuser.NewMattermostClient(install.ServerURL, install.BotAccessToken)
+
+	me, err := client.GetMe()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "server": install.ServerURL}).Error("installMattermost failed on GetMe")
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing or incorrect Bot Access Token")
+	}
+
+	team, err := client.GetTeam(install.TeamID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "team": install.TeamID}).Error("installMattermost failed on GetTeam")
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	settings, err := api.db.GetWorkspaceByWorkspaceID(team.ID)
+	if err != nil {
+		language := install.Language
+		if language == "" {
+			language = "en"
+		}
+		settings, err = api.db.CreateWorkspace(model.Workspace{
+			CreatedAt:              api.clock.Now().Unix(),
+			BotUserID:              me.ID,
+			NotifierInterval:       30,
+			Language:               language,
+			MaxReminders:           3,
+			ReminderOffset:         10,
+			BlockerEscalationDays:  3,
+			TZ:                     "Asia/Bishkek",
+			BotAccessToken:         install.BotAccessToken,
+			WorkspaceID:            team.ID,
+			WorkspaceName:          team.Name,
+			ReportingChannel:       "",
+			ReportingTime:          "10am",
+			ProjectsReportsEnabled: false,
+			Platform:               model.PlatformMattermost,
+			ServerURL:              install.ServerURL,
+		})
+		if err != nil {
+			log.WithFields(log.Fields{"team": team, "error": err}).Error("installMattermost failed on CreateWorkspace")
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	} else {
+		if settings.Platform != model.PlatformMattermost || settings.ServerURL != install.ServerURL {
+			log.WithFields(log.Fields{"team": team.ID, "platform": settings.Platform, "server": settings.ServerURL}).Error("installMattermost refused to take over workspace")
+			return echo.NewHTTPError(http.StatusConflict, "workspace is installed on another platform or server")
+		}
+		settings.BotUserID = me.ID
+		settings.BotAccessToken = install.BotAccessToken
+
+		settings, err = api.db.UpdateWorkspace(settings)
+		if err != nil {
+			log.WithFields(log.Fields{"team": team, "error": err}).Error("installMattermost failed on UpdateWorkspace")
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	api.bots.Start(settings)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"bot": settings})
+}
+
+// validMattermostToken checks the token against MATTERMOST_TOKENS, which
+// lists tokens of slash commands and outgoing webhooks as team_id:token, so
+// a token is only good for the team it was issued in
+func (api *ComedianAPI) validMattermostToken(teamID, token string) bool {
+	if teamID == "" || token == "" {
+		return false
+	}
+	for _, t := range api.config.MattermostTokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(teamID+":"+token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (api *ComedianAPI) allowedMattermostServer(serverURL string) bool {
+	for _, s := range api.config.MattermostServers {
+		if strings.TrimRight(s, "/") == serverURL {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// mockMattermost answers the few Mattermost API calls Comedian makes and
+// remembers posted paths
+type mockMattermost struct {
+	mu     sync.Mutex
+	posted []string
+}
+
+func (m *mockMattermost) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer bot-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"Invalid or expired session, please login again."}`))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost:
+		m.mu.Lock()
+		m.posted = append(m.posted, r.URL.Path)
+		m.mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"post1"}`))
+	case r.URL.Path == "/api/v4/users/me":
+		w.Write([]byte(`{"id":"botid","username":"comedian","is_bot":true}`))
+	case r.URL.Path == "/api/v4/teams/team1":
+		w.Write([]byte(`{"id":"team1","name":"devs","display_name":"Devs"}`))
+	case r.URL.Path == "/api/v4/channels/C1":
+		w.Write([]byte(`{"id":"C1","name":"general"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found"}`))
+	}
+}
+
+func (m *mockMattermost) flush() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	posted := m.posted
+	m.posted = nil
+	return posted
+}
+
+func TestMattermost(t *testing.T) {
+	mm := &mockMattermost{}
+	server := httptest.NewServer(mm)
+	defer server.Close()
+
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{
+		AdminToken:        "admin-token",
+		MattermostTokens:  []string{"team1:hook-token", "team2:other-token"},
+		MattermostServers: []string{server.URL + "/"},
+	}, db, i18n.NewBundle(language.English))
+
+	do := func(path, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		req.Header.Set(echo.HeaderAuthorization, "admin-token")
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	installTo := func(serverURL, token string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(MattermostInstall{ServerURL: serverURL, BotAccessToken: token, TeamID: "team1"})
+		return do("/mattermost/install", echo.MIMEApplicationJSON, string(body))
+	}
+	install := func(token string) *httptest.ResponseRecorder {
+		return installTo(server.URL, token)
+	}
+
+	// install is for admins only
+	req := httptest.NewRequest(http.MethodPost, "/mattermost/install", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	api.echo.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// servers out of MATTERMOST_SERVERS are never called
+	rec = installTo("http://169.254.169.254", "bot-token")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = install("wrong-token")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = install("bot-token")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	ws, err := db.GetWorkspaceByWorkspaceID("team1")
+	require.NoError(t, err)
+	assert.Equal(t, model.PlatformMattermost, ws.Platform)
+	assert.Equal(t, server.URL, ws.ServerURL)
+	assert.Equal(t, "botid", ws.BotUserID)
+	assert.Equal(t, "devs", ws.WorkspaceName)
+
+	// reinstall updates the workspace instead of creating another one
+	rec = install("bot-token")
+	require.Equal(t, http.StatusOK, rec.Code)
+	workspaces, err := db.GetAllWorkspaces()
+	require.NoError(t, err)
+	assert.Len(t, workspaces, 1)
+	assert.Len(t, api.bots.List(), 1)
+
+	// a workspace of another platform or server is not taken over
+	ws.Platform = model.PlatformSlack
+	ws.ServerURL = ""
+	_, err = db.UpdateWorkspace(ws)
+	require.NoError(t, err)
+	rec = install("bot-token")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	ws, err = db.GetWorkspaceByWorkspaceID("team1")
+	require.NoError(t, err)
+	assert.Equal(t, model.PlatformSlack, ws.Platform)
+	ws.Platform = model.PlatformMattermost
+	ws.ServerURL = server.URL
+	_, err = db.UpdateWorkspace(ws)
+	require.NoError(t, err)
+
+	command := url.Values{
+		"token":        {"hook-token"},
+		"team_id":      {"team1"},
+		"channel_id":   {"C1"},
+		"channel_name": {"general"},
+		"user_id":      {"U1"},
+		"user_name":    {"john"},
+		"command":      {"/start"},
+		"text":         {"developer"},
+	}
+
+	command.Set("token", "wrong")
+	rec = do("/mattermost/commands", echo.MIMEApplicationForm, command.Encode())
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	// token of another team is not accepted
+	command.Set("token", "other-token")
+	rec = do("/mattermost/commands", echo.MIMEApplicationForm, command.Encode())
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	command.Set("token", "hook-token")
+	rec = do("/mattermost/commands", echo.MIMEApplicationForm, command.Encode())
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"response_type":"ephemeral"`)
+
+	standupers, err := db.ListProjectStandupers("C1")
+	require.NoError(t, err)
+	require.Len(t, standupers, 1)
+	assert.Equal(t, "U1", standupers[0].UserID)
+
+	hook := MattermostWebhook{
+		Token:       "hook-token",
+		TeamID:      "team1",
+		ChannelID:   "C1",
+		UserID:      "U1",
+		PostID:      "post42",
+		Text:        "@comedian yesterday fixed bugs, today write tests, no issues",
+		TriggerWord: "@comedian",
+	}
+	body, _ := json.Marshal(hook)
+	rec = do("/mattermost/event", echo.MIMEApplicationJSON, string(body))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	standup, err := db.SelectStandupByMessageTS("team1", "C1", "post42")
+	require.NoError(t, err)
+	assert.Equal(t, "<@botid> yesterday fixed bugs, today write tests, no issues", standup.Comment)
+	assert.Contains(t, mm.flush(), "/api/v4/reactions")
+
+	hook.Token = "wrong"
+	body, _ = json.Marshal(hook)
+	rec = do("/mattermost/event", echo.MIMEApplicationJSON, string(body))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	log "github.com/sirupsen/logrus"
+)
+
+const outboxListLimit = 100
+
+// listOutbox shows delivery status of latest messages of the workspace
+func (api *ComedianAPI) listOutbox(c echo.Context) error {
+	messages, err := api.db.ListWorkspaceOutboxMessages(c.Get("teamID").(string), c.QueryParam("status"), outboxListLimit)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error":    err,
+			"function": "api.db.ListWorkspaceOutboxMessages",
+			"data":     c.Get("teamID")},
+		).Error("listOutbox failed")
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"outbox": messages})
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestListOutbox(t *testing.T) {
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{}, db, i18n.NewBundle(language.English))
+
+	for _, ws := range []model.Workspace{
+		{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"},
+		{WorkspaceID: "T2", WorkspaceName: "second", BotAccessToken: "token-2", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"},
+	} {
+		_, err := db.CreateWorkspace(ws)
+		require.NoError(t, err)
+	}
+
+	for _, m := range []model.OutboxMessage{
+		{WorkspaceID: "T1", Type: "message", ChannelID: "C1", Text: "sent", Status: model.OutboxSent, Attempts: 1},
+		{WorkspaceID: "T1", Type: "direct", UserID: "U1", Text: "lost", Status: model.OutboxFailed, Attempts: 5, Error: "user_not_found"},
+		{WorkspaceID: "T2", Type: "message", ChannelID: "C2", Text: "other", Status: model.OutboxPending},
+	} {
+		_, err := db.CreateOutboxMessage(m)
+		require.NoError(t, err)
+	}
+
+	list := func(token, query string) []model.OutboxMessage {
+		req := httptest.NewRequest(http.MethodGet, "/v1/outbox"+query, nil)
+		req.Header.Set(echo.HeaderAuthorization, token)
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var body struct {
+			Outbox []model.OutboxMessage `json:"outbox"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body.Outbox
+	}
+
+	assert.Len(t, list("token-1", ""), 2)
+	assert.Len(t, list("token-2", ""), 1)
+
+	failed := list("token-1", "?status=failed")
+	require.Len(t, failed, 1)
+	assert.Equal(t, "U1", failed[0].UserID)
+	assert.Equal(t, "user_not_found", failed[0].Error)
+}
@@ -0,0 +1,354 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/botuser"
+	"github.com/maddevsio/comedian/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// Registry lifecycle events passed to hooks
+const (
+	BotStarted      = "started"
+	BotReconfigured = "reconfigured"
+	BotStopped      = "stopped"
+)
+
+// registrySyncInterval is how often the registry is brought in line with
+// workspaces changed or deleted through other replicas
+const registrySyncInterval = 30 * time.Second
+
+// errBotNotFound is returned when no bot runs for the workspace
+var errBotNotFound = errors.New("bot not found")
+
+// BotHook is called after a bot of the registry is started, reconfigured or stopped
+type BotHook func(event string, bot *botuser.Bot)
+
+// Registry keeps running bots by workspace id and name. It is safe for
+// concurrent use: bots are started and stopped one at a time, so there are
+// never two bots of the same workspace, while lookups only wait for the
+// maps to be updated, not for a bot to finish its tick. Workspaces are
+// stored in the database, so the registry of every replica follows it: a
+// bot missing on lookup is loaded at once and Sync picks up changed and
+// deleted workspaces
+type Registry struct {
+	// ops serializes starting and stopping of bots
+	ops    sync.Mutex
+	mu     sync.RWMutex
+	byID   map[string]*botuser.Bot
+	byName map[string]*botuser.Bot
+	newBot func(model.Workspace) *botuser.Bot
+	lookup func(team string) (model.Workspace, error)
+	hooks  []BotHook
+}
+
+// NewRegistry creates empty registry which creates bots with newBot and
+// finds settings of workspaces missing in it with lookup
+func NewRegistry(newBot func(model.Workspace) *botuser.Bot, lookup func(team string) (model.Workspace, error)) *Registry {
+	return &Registry{
+		byID:   map[string]*botuser.Bot{},
+		byName: map[string]*botuser.Bot{},
+		newBot: newBot,
+		lookup: lookup,
+	}
+}
+
+// OnChange adds hook which is called on every change of the registry
+func (r *Registry) OnChange(hook BotHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, hook)
+}
+
+// Get returns bot by its workspace id or workspace name. A workspace
+// installed through another replica is looked up and its bot is started
+func (r *Registry) Get(team string) (*botuser.Bot, error) {
+	bot := r.find(team)
+	if bot != nil {
+		return bot, nil
+	}
+	if r.lookup == nil {
+		return nil, errBotNotFound
+	}
+
+	settings, err := r.lookup(team)
+	if err != nil {
+		return nil, errBotNotFound
+	}
+
+	r.ops.Lock()
+	bot = r.find(team)
+	if bot != nil {
+		r.ops.Unlock()
+		return bot, nil
+	}
+	bot, old := r.start(settings)
+	r.ops.Unlock()
+
+	r.notifyStart(bot, old)
+	return bot, nil
+}
+
+// find returns registered bot by its workspace id or workspace name
+func (r *Registry) find(team string) *botuser.Bot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	team = strings.ToLower(team)
+	if bot, ok := r.byID[team]; ok {
+		return bot
+	}
+	if bot, ok := r.byName[team]; ok {
+		return bot
+	}
+	return nil
+}
+
+// List returns running bots ordered by workspace id
+func (r *Registry) List() []*botuser.Bot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bots := make([]*botuser.Bot, 0, len(r.byID))
+	for _, bot := range r.byID {
+		bots = append(bots, bot)
+	}
+	sort.Slice(bots, func(i, j int) bool {
+		return bots[i].Settings().WorkspaceID < bots[j].Settings().WorkspaceID
+	})
+	return bots
+}
+
+// Start starts bot of the workspace. A bot already running for the workspace
+// is replaced, so that new settings and messenger credentials are picked up.
+// The old bot is stopped first, so the two never run at once, and serves
+// lookups until the new one is registered
+func (r *Registry) Start(settings model.Workspace) *botuser.Bot {
+	r.ops.Lock()
+	bot, old := r.start(settings)
+	r.ops.Unlock()
+
+	r.notifyStart(bot, old)
+	return bot
+}
+
+// Stop stops bot of the workspace and removes it from the registry
+func (r *Registry) Stop(workspaceID string) error {
+	r.ops.Lock()
+	bot := r.stop(workspaceID, nil)
+	r.ops.Unlock()
+
+	if bot == nil {
+		return errBotNotFound
+	}
+	r.notify(BotStopped, bot)
+	return nil
+}
+
+// Sync makes the registry follow workspaces load returns: bots of changed
+// workspaces are restarted with new settings, and bots of deleted ones are
+// stopped. Bots started while load runs are left alone
+func (r *Registry) Sync(load func() ([]model.Workspace, error)) error {
+	running := r.List()
+
+	workspaces, err := load()
+	if err != nil {
+		return err
+	}
+
+	stored := map[string]bool{}
+	for _, settings := range workspaces {
+		stored[strings.ToLower(settings.WorkspaceID)] = true
+		bot := r.find(settings.WorkspaceID)
+		if bot != nil && *bot.Settings() == settings {
+			continue
+		}
+		r.ops.Lock()
+		// the bot could be replaced while the lock was awaited
+		if r.find(settings.WorkspaceID) != bot {
+			r.ops.Unlock()
+			continue
+		}
+		bot, old := r.start(settings)
+		r.ops.Unlock()
+		r.notifyStart(bot, old)
+	}
+
+	for _, bot := range running {
+		if stored[strings.ToLower(bot.Settings().WorkspaceID)] {
+			continue
+		}
+		r.ops.Lock()
+		stopped := r.stop(bot.Settings().WorkspaceID, bot)
+		r.ops.Unlock()
+		if stopped != nil {
+			r.notify(BotStopped, stopped)
+		}
+	}
+	return nil
+}
+
+// StopAll stops all bots
+func (r *Registry) StopAll() {
+	for _, bot := range r.List() {
+		r.Stop(bot.Settings().WorkspaceID)
+	}
+}
+
+// start stops bot running for the workspace, then starts and registers a
+// new one. It returns the new bot and the replaced one. Must be called with
+// ops locked
+func (r *Registry) start(settings model.Workspace) (*botuser.Bot, *botuser.Bot) {
+	old := r.find(settings.WorkspaceID)
+	if old != nil {
+		old.Stop()
+	}
+
+	bot := r.newBot(settings)
+	bot.Start()
+
+	r.mu.Lock()
+	if old != nil {
+		r.remove(settings.WorkspaceID)
+	}
+	r.byID[strings.ToLower(settings.WorkspaceID)] = bot
+	r.byName[strings.ToLower(settings.WorkspaceName)] = bot
+	r.mu.Unlock()
+	return bot, old
+}
+
+// stop removes bot of the workspace from the registry and stops it. When
+// only is given, the bot is stopped only if it is still the registered
+// one. Must be called with ops locked
+func (r *Registry) stop(workspaceID string, only *botuser.Bot) *botuser.Bot {
+	r.mu.Lock()
+	bot := r.byID[strings.ToLower(workspaceID)]
+	if bot == nil || (only != nil && bot != only) {
+		r.mu.Unlock()
+		return nil
+	}
+	r.remove(workspaceID)
+	r.mu.Unlock()
+
+	bot.Stop()
+	return bot
+}
+
+// remove unregisters bot of the workspace and returns it. Must be called with mu locked
+func (r *Registry) remove(workspaceID string) *botuser.Bot {
+	bot, ok := r.byID[strings.ToLower(workspaceID)]
+	if !ok {
+		return nil
+	}
+	delete(r.byID, strings.ToLower(workspaceID))
+	name := strings.ToLower(bot.Settings().WorkspaceName)
+	if r.byName[name] == bot {
+		delete(r.byName, name)
+	}
+	return bot
+}
+
+func (r *Registry) notifyStart(bot, old *botuser.Bot) {
+	event := BotStarted
+	if old != nil {
+		event = BotReconfigured
+	}
+	r.notify(event, bot)
+}
+
+func (r *Registry) notify(event string, bot *botuser.Bot) {
+	r.mu.RLock()
+	hooks := r.hooks
+	r.mu.RUnlock()
+
+	log.WithFields(log.Fields{"workspace": bot.Settings().WorkspaceID, "event": event}).Info("bot registry changed")
+	for _, hook := range hooks {
+		hook(event, bot)
+	}
+}
+
+// startRegistrySync starts the loop which syncs the registry with stored workspaces
+func (api *ComedianAPI) startRegistrySync() {
+	go func() {
+		ticker := time.NewTicker(registrySyncInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			err := api.bots.Sync(api.db.GetAllWorkspaces)
+			if err != nil {
+				log.Error("registry sync failed: ", err)
+			}
+		}
+	}()
+}
+
+// lookupWorkspace finds stored workspace by its id or name
+func (api *ComedianAPI) lookupWorkspace(team string) (model.Workspace, error) {
+	settings, err := api.db.GetWorkspaceByWorkspaceID(team)
+	if err == nil {
+		return settings, nil
+	}
+
+	workspaces, err := api.db.GetAllWorkspaces()
+	if err != nil {
+		return model.Workspace{}, err
+	}
+	for _, settings := range workspaces {
+		if strings.EqualFold(settings.WorkspaceID, team) || strings.EqualFold(settings.WorkspaceName, team) {
+			return settings, nil
+		}
+	}
+	return model.Workspace{}, errBotNotFound
+}
+
+// RegistryEntry describes running bot for introspection
+type RegistryEntry struct {
+	WorkspaceID   string `json:"workspace_id"`
+	WorkspaceName string `json:"workspace_name"`
+	Platform      string `json:"platform"`
+	StartedAt     int64  `json:"started_at"`
+	Leader        bool   `json:"leader"`
+}
+
+// adminPreRequest lets through requests carrying ADMIN_TOKEN. Admin
+// endpoints are disabled while the token is not configured
+func (api *ComedianAPI) adminPreRequest(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Request().Header.Get(echo.HeaderAuthorization)
+		if api.config.AdminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(api.config.AdminToken)) != 1 {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Missing or incorrect Admin Token")
+		}
+		return next(c)
+	}
+}
+
+// listRegistry shows bots running in this replica
+func (api *ComedianAPI) listRegistry(c echo.Context) error {
+	entries := []RegistryEntry{}
+	for _, bot := range api.bots.List() {
+		settings := bot.Settings()
+		platform := settings.Platform
+		if platform == "" {
+			platform = model.PlatformSlack
+		}
+		entries = append(entries, RegistryEntry{
+			WorkspaceID:   settings.WorkspaceID,
+			WorkspaceName: settings.WorkspaceName,
+			Platform:      platform,
+			StartedAt:     bot.StartedAt().Unix(),
+			Leader:        bot.Leader(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"replica": api.config.ReplicaID,
+		"bots":    entries,
+	})
+}
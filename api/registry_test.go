@@ -0,0 +1,180 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/botuser"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestRegistry(t *testing.T) {
+	api := New(&config.Config{ReplicaID: "replica-1", AdminToken: "admin"}, storage.NewMemoryDB(), i18n.NewBundle(language.English))
+	registry := api.bots
+
+	var mu sync.Mutex
+	events := []string{}
+	registry.OnChange(func(event string, bot *botuser.Bot) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event+":"+bot.Settings().WorkspaceID)
+	})
+
+	first := registry.Start(model.Workspace{WorkspaceID: "T1", WorkspaceName: "First", BotAccessToken: "token-1"})
+	registry.Start(model.Workspace{WorkspaceID: "T2", WorkspaceName: "Second", BotAccessToken: "token-2"})
+
+	bot, err := registry.Get("t1")
+	require.NoError(t, err)
+	assert.Equal(t, first, bot)
+	bot, err = registry.Get("first")
+	require.NoError(t, err)
+	assert.Equal(t, first, bot)
+
+	// reinstall replaces the bot, old name is forgotten
+	renamed := registry.Start(model.Workspace{WorkspaceID: "T1", WorkspaceName: "Renamed", BotAccessToken: "token-3"})
+	assert.NotEqual(t, first, renamed)
+	bot, err = registry.Get("Renamed")
+	require.NoError(t, err)
+	assert.Equal(t, renamed, bot)
+	_, err = registry.Get("First")
+	assert.Error(t, err)
+	assert.Len(t, registry.List(), 2)
+
+	require.NoError(t, registry.Stop("T2"))
+	assert.Error(t, registry.Stop("T2"), "stopped bot is not stopped again")
+	assert.Error(t, registry.Stop("T3"), "missing bot does not remove other bots")
+	_, err = registry.Get("T2")
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"started:T1", "started:T2", "reconfigured:T1", "stopped:T2"}, events)
+
+	req := httptest.NewRequest(http.MethodGet, "/registry", nil)
+	rec := httptest.NewRecorder()
+	api.echo.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/registry", nil)
+	req.Header.Set(echo.HeaderAuthorization, "admin")
+	rec = httptest.NewRecorder()
+	api.echo.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Replica string          `json:"replica"`
+		Bots    []RegistryEntry `json:"bots"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "replica-1", body.Replica)
+	require.Len(t, body.Bots, 1)
+	assert.Equal(t, "T1", body.Bots[0].WorkspaceID)
+	assert.Equal(t, "Renamed", body.Bots[0].WorkspaceName)
+	assert.Equal(t, model.PlatformSlack, body.Bots[0].Platform)
+
+	// concurrent handlers and reinstalls always find exactly one bot
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			registry.Start(model.Workspace{WorkspaceID: "T1", WorkspaceName: "Renamed", BotAccessToken: fmt.Sprint(i)})
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := api.SelectBot("T1")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, registry.List(), 1)
+
+	registry.StopAll()
+	assert.Empty(t, registry.List())
+}
+
+func TestUpdateBot(t *testing.T) {
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{ReplicaID: "replica-1"}, db, i18n.NewBundle(language.English))
+	ws, err := db.CreateWorkspace(model.Workspace{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"})
+	require.NoError(t, err)
+
+	body := `{"reporting_channel":"CREP","blocker_escalation_days":5,"bot_access_token":"stolen","platform":"mattermost","server_url":"http://attacker","workspace_id":"T2","bot_user_id":"U1"}`
+	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/bots/%v", ws.ID), strings.NewReader(body))
+	req.Header.Set(echo.HeaderAuthorization, "token-1")
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	api.echo.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	defer api.bots.StopAll()
+
+	// settings are updated, identity and credentials are not
+	updated, err := db.GetWorkspace(ws.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "CREP", updated.ReportingChannel)
+	assert.Equal(t, 5, updated.BlockerEscalationDays)
+	assert.Equal(t, "token-1", updated.BotAccessToken)
+	assert.Equal(t, "", updated.Platform)
+	assert.Equal(t, "", updated.ServerURL)
+	assert.Equal(t, "T1", updated.WorkspaceID)
+	assert.Equal(t, "BOT", updated.BotUserID)
+
+	bot, err := api.SelectBot("T1")
+	require.NoError(t, err)
+	assert.Equal(t, "CREP", bot.Settings().ReportingChannel)
+}
+
+func TestRegistrySync(t *testing.T) {
+	db := storage.NewMemoryDB()
+	first := New(&config.Config{ReplicaID: "replica-1"}, db, i18n.NewBundle(language.English))
+	second := New(&config.Config{ReplicaID: "replica-2"}, db, i18n.NewBundle(language.English))
+	defer first.bots.StopAll()
+	defer second.bots.StopAll()
+
+	// workspace installed through the first replica is served by the second one at once
+	ws, err := db.CreateWorkspace(model.Workspace{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", Language: "en", ReminderOffset: 10, ReportingTime: "10am"})
+	require.NoError(t, err)
+	first.bots.Start(ws)
+
+	bot, err := second.SelectBot("T1")
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", bot.Settings().BotAccessToken)
+	same, err := second.SelectBot("first")
+	require.NoError(t, err)
+	assert.Equal(t, bot, same)
+	_, err = second.SelectBot("T2")
+	assert.Error(t, err)
+
+	// unchanged bots are kept running
+	require.NoError(t, second.bots.Sync(db.GetAllWorkspaces))
+	same, err = second.SelectBot("T1")
+	require.NoError(t, err)
+	assert.Equal(t, bot, same)
+
+	// changed settings are picked up
+	ws.ReportingChannel = "CREP"
+	ws, err = db.UpdateWorkspace(ws)
+	require.NoError(t, err)
+	require.NoError(t, second.bots.Sync(db.GetAllWorkspaces))
+	bot, err = second.SelectBot("T1")
+	require.NoError(t, err)
+	assert.Equal(t, "CREP", bot.Settings().ReportingChannel)
+	assert.Len(t, second.bots.List(), 1)
+
+	// uninstalled workspace is stopped
+	require.NoError(t, db.DeleteWorkspace("T1"))
+	require.NoError(t, second.bots.Sync(db.GetAllWorkspaces))
+	assert.Empty(t, second.bots.List())
+	_, err = second.SelectBot("T1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	slackSignatureHeader = "X-Slack-Signature"
+	slackTimestampHeader = "X-Slack-Request-Timestamp"
+	slackVerifiedKey     = "slackVerified"
+)
+
+// slackReplayWindow is how old a signed request may be. Older requests are
+// rejected, so a captured request can not be replayed later
+const slackReplayWindow = 5 * time.Minute
+
+// slackPreRequest verifies X-Slack-Signature of requests coming from Slack
+// with SLACK_SIGNING_SECRET. Requests without signature are passed to the
+// handler, which accepts them only with legacy verification token
+func (api *ComedianAPI) slackPreRequest(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		signature := c.Request().Header.Get(slackSignatureHeader)
+		if api.config.SlackSigningSecret == "" || signature == "" {
+			return next(c)
+		}
+
+		body, err := ioutil.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		c.Request().Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		err = api.verifySlackSignature(c.Request().Header.Get(slackTimestampHeader), signature, body)
+		if err != nil {
+			log.WithFields(log.Fields{"uri": c.Request().RequestURI, "error": err}).Warning("slack signature verification failed")
+			return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		}
+
+		c.Set(slackVerifiedKey, true)
+		return next(c)
+	}
+}
+
+func (api *ComedianAPI) verifySlackSignature(timestamp, signature string, body []byte) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %v", slackTimestampHeader)
+	}
+
+	age := api.clock.Now().Sub(time.Unix(ts, 0))
+	if age > slackReplayWindow || age < -slackReplayWindow {
+		return fmt.Errorf("request timestamp is out of %v window", slackReplayWindow)
+	}
+
+	mac := hmac.New(sha256.New, []byte(api.config.SlackSigningSecret))
+	fmt.Fprintf(mac, "v0:%v:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("%v does not match", slackSignatureHeader)
+	}
+	return nil
+}
+
+// slackVerified tells whether request was signed by Slack or carries legacy
+// verification token. Once signing secret is configured, the token is
+// accepted only while SLACK_VERIFICATION_TOKEN is still set
+func (api *ComedianAPI) slackVerified(c echo.Context, token string) bool {
+	if verified, _ := c.Get(slackVerifiedKey).(bool); verified {
+		return true
+	}
+	if api.config.SlackSigningSecret != "" && api.config.SlackVerificationToken == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(api.config.SlackVerificationToken)) == 1
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func sign(secret string, ts int64, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%v:%v", ts, body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestSlackSignature(t *testing.T) {
+	conf := &config.Config{SlackSigningSecret: "signing-secret"}
+	api := New(conf, storage.NewMemoryDB(), i18n.NewBundle(language.English))
+	now := time.Date(2019, 11, 4, 10, 0, 0, 0, time.UTC)
+	api.clock = clock.NewMock(now)
+
+	do := func(path, contentType, body string, ts int64, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		if signature != "" {
+			req.Header.Set(slackSignatureHeader, signature)
+			req.Header.Set(slackTimestampHeader, fmt.Sprint(ts))
+		}
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	challenge := `{"type":"url_verification","challenge":"abc"}`
+	event := func(ts int64, signature string) int {
+		return do("/event", echo.MIMEApplicationJSON, challenge, ts, signature)
+	}
+
+	ts := now.Unix()
+	assert.Equal(t, http.StatusOK, event(ts, sign("signing-secret", ts, challenge)))
+	assert.Equal(t, http.StatusUnauthorized, event(ts, sign("other-secret", ts, challenge)))
+	assert.Equal(t, http.StatusUnauthorized, event(ts+1, sign("signing-secret", ts, challenge)))
+	assert.Equal(t, http.StatusUnauthorized, event(0, "v0=abc"), "missing timestamp")
+
+	// replayed and forged from the future requests
+	old := now.Add(-6 * time.Minute).Unix()
+	assert.Equal(t, http.StatusUnauthorized, event(old, sign("signing-secret", old, challenge)))
+	future := now.Add(6 * time.Minute).Unix()
+	assert.Equal(t, http.StatusUnauthorized, event(future, sign("signing-secret", future, challenge)))
+	recent := now.Add(-4 * time.Minute).Unix()
+	assert.Equal(t, http.StatusOK, event(recent, sign("signing-secret", recent, challenge)))
+
+	// unsigned requests are accepted with verification token only during migration
+	withToken := `{"token":"legacy","type":"url_verification","challenge":"abc"}`
+	assert.Equal(t, http.StatusUnauthorized, do("/event", echo.MIMEApplicationJSON, withToken, 0, ""))
+	conf.SlackVerificationToken = "legacy"
+	assert.Equal(t, http.StatusOK, do("/event", echo.MIMEApplicationJSON, withToken, 0, ""))
+	assert.Equal(t, http.StatusUnauthorized, do("/event", echo.MIMEApplicationJSON, challenge, 0, ""))
+
+	// signed slash command reaches the bot
+	api.bots.Start(model.Workspace{WorkspaceID: "T1", WorkspaceName: "team", BotAccessToken: "token", Language: "en"})
+	defer api.bots.StopAll()
+	command := "team_id=T1&channel_id=C1&user_id=U1&command=%2Fshow"
+	for _, path := range []string{"/commands", "/user-commands", "/team-worklogs"} {
+		assert.Equal(t, http.StatusUnauthorized, do(path, echo.MIMEApplicationForm, command, ts, sign("other-secret", ts, command)), path)
+		assert.NotEqual(t, http.StatusUnauthorized, do(path, echo.MIMEApplicationForm, command, ts, sign("signing-secret", ts, command)), path)
+	}
+	assert.Equal(t, http.StatusOK, do("/commands", echo.MIMEApplicationForm, command, ts, sign("signing-secret", ts, command)))
+}
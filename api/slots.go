@@ -0,0 +1,134 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/model"
+)
+
+// listDeadlineSlots lists deadline slots of the workspace, or of one channel
+// given channel_id query parameter
+func (api *ComedianAPI) listDeadlineSlots(c echo.Context) error {
+	var slots []model.DeadlineSlot
+	var err error
+	if channelID := c.QueryParam("channel_id"); channelID != "" {
+		slots, err = api.db.ListChannelDeadlineSlots(channelID)
+	} else {
+		slots, err = api.db.ListWorkspaceDeadlineSlots(c.Get("teamID").(string))
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	workspaceSlots := []model.DeadlineSlot{}
+	for _, slot := range slots {
+		if slot.WorkspaceID == c.Get("teamID") {
+			workspaceSlots = append(workspaceSlots, slot)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"slots": workspaceSlots})
+}
+
+func (api *ComedianAPI) createDeadlineSlot(c echo.Context) error {
+	var slot model.DeadlineSlot
+	if err := c.Bind(&slot); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	slot.ID = 0
+	slot.CreatedAt = api.clock.Now().Unix()
+	slot.WorkspaceID = c.Get("teamID").(string)
+	if err := api.checkSlot(&slot); err != nil {
+		return err
+	}
+
+	slot, err := api.db.CreateDeadlineSlot(slot)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{"slot": slot})
+}
+
+// updateDeadlineSlot changes name, days or deadline of the slot, the slot
+// cannot be moved to another channel
+func (api *ComedianAPI) updateDeadlineSlot(c echo.Context) error {
+	slot, err := api.getDeadlineSlot(c)
+	if err != nil {
+		return err
+	}
+
+	id, workspaceID, channelID, createdAt := slot.ID, slot.WorkspaceID, slot.ChannelID, slot.CreatedAt
+	if err := c.Bind(&slot); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+	slot.ID, slot.WorkspaceID, slot.ChannelID, slot.CreatedAt = id, workspaceID, channelID, createdAt
+
+	if err := api.checkSlot(&slot); err != nil {
+		return err
+	}
+
+	slot, err = api.db.UpdateDeadlineSlot(slot)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"slot": slot})
+}
+
+func (api *ComedianAPI) deleteDeadlineSlot(c echo.Context) error {
+	slot, err := api.getDeadlineSlot(c)
+	if err != nil {
+		return err
+	}
+
+	err = api.db.DeleteDeadlineSlot(slot.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, somethingWentWrong)
+	}
+
+	return c.JSON(http.StatusNoContent, "")
+}
+
+// getDeadlineSlot returns slot by id from the path if it belongs to the
+// workspace of the request
+func (api *ComedianAPI) getDeadlineSlot(c echo.Context) (model.DeadlineSlot, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		return model.DeadlineSlot{}, echo.NewHTTPError(http.StatusBadRequest, incorrectID)
+	}
+
+	slot, err := api.db.GetDeadlineSlot(id)
+	if err != nil {
+		return slot, echo.NewHTTPError(http.StatusNotFound, doesNotExist)
+	}
+
+	if slot.WorkspaceID != c.Get("teamID") {
+		return slot, echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+
+	return slot, nil
+}
+
+// checkSlot makes sure slot belongs to a channel of its workspace and
+// normalizes its days
+func (api *ComedianAPI) checkSlot(slot *model.DeadlineSlot) error {
+	channel, err := api.db.SelectProject(slot.ChannelID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Channel does not exist")
+	}
+
+	if channel.WorkspaceID != slot.WorkspaceID {
+		return echo.NewHTTPError(http.StatusUnauthorized, accessDenied)
+	}
+
+	slot.Days, err = model.NormalizeWeekdays(slot.Days)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
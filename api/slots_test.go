@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestDeadlineSlots(t *testing.T) {
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{}, db, i18n.NewBundle(language.English))
+	api.clock = clock.NewMock(time.Date(2019, 12, 20, 12, 0, 0, 0, time.UTC))
+
+	for _, ws := range []model.Workspace{
+		{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"},
+		{WorkspaceID: "T2", WorkspaceName: "second", BotAccessToken: "token-2", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"},
+	} {
+		_, err := db.CreateWorkspace(ws)
+		require.NoError(t, err)
+	}
+	for _, p := range []model.Project{
+		{WorkspaceID: "T1", ChannelID: "C1", ChannelName: "general", TZ: "UTC"},
+		{WorkspaceID: "T2", ChannelID: "C2", ChannelName: "random", TZ: "UTC"},
+	} {
+		_, err := db.CreateProject(p)
+		require.NoError(t, err)
+	}
+
+	call := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderAuthorization, token)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := call(http.MethodPost, "/v1/slots", "token-1", `{"channel_id":"C1","days":"Mon","deadline":"12:00","workspace_id":"T2"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created struct {
+		Slot model.DeadlineSlot `json:"slot"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "T1", created.Slot.WorkspaceID)
+	assert.Equal(t, "monday", created.Slot.Days)
+
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, "/v1/slots", "token-1", `{"channel_id":"C1","deadline":"noon"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, "/v1/slots", "token-1", `{"channel_id":"C1","days":"someday","deadline":"10:00"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, "/v1/slots", "token-1", `{"channel_id":"C404","deadline":"10:00"}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodPost, "/v1/slots", "token-1", `{"channel_id":"C2","deadline":"10:00"}`).Code)
+
+	path := fmt.Sprintf("/v1/slots/%v", created.Slot.ID)
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodPatch, path, "token-2", `{"deadline":"13:00"}`).Code)
+	rec = call(http.MethodPatch, path, "token-1", `{"name":"late","deadline":"13:00","channel_id":"C2"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = call(http.MethodGet, "/v1/slots?channel_id=C1", "token-1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listed struct {
+		Slots []model.DeadlineSlot `json:"slots"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed.Slots, 1)
+	assert.Equal(t, "C1", listed.Slots[0].ChannelID)
+	assert.Equal(t, "late", listed.Slots[0].Name)
+	assert.Equal(t, "13:00", listed.Slots[0].Deadline)
+
+	rec = call(http.MethodGet, "/v1/slots?channel_id=C1", "token-2", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	assert.Empty(t, listed.Slots)
+
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodDelete, path, "token-2", "").Code)
+	assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, path, "token-1", "").Code)
+	assert.Equal(t, http.StatusNotFound, call(http.MethodDelete, path, "token-1", "").Code)
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestStandupAnswers(t *testing.T) {
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{}, db, i18n.NewBundle(language.English))
+
+	_, err := db.CreateWorkspace(model.Workspace{WorkspaceID: "T1", WorkspaceName: "first", BotAccessToken: "token-1", BotUserID: "BOT", Language: "en", ReminderOffset: 10, ReportingTime: "10am"})
+	require.NoError(t, err)
+	_, err = db.CreateProject(model.Project{
+		WorkspaceID: "T1",
+		ChannelID:   "C1",
+		ChannelName: "general",
+		TZ:          "UTC",
+		StandupTemplate: model.StandupTemplate{
+			{Name: "Done", Keywords: []string{"done"}},
+			{Name: "Doing", Keywords: []string{"doing"}},
+		},
+	})
+	require.NoError(t, err)
+
+	first, err := db.CreateStandup(model.Standup{WorkspaceID: "T1", ChannelID: "C1", UserID: "U1", Comment: "Done: bugs", MessageTS: "1.1"})
+	require.NoError(t, err)
+	_, err = db.CreateStandupAnswer(model.StandupAnswer{StandupID: first.ID, Question: "Done", Answer: "bugs"})
+	require.NoError(t, err)
+	second, err := db.CreateStandup(model.Standup{WorkspaceID: "T1", ChannelID: "C1", UserID: "U2", Comment: "free text", MessageTS: "1.2"})
+	require.NoError(t, err)
+
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderAuthorization, "token-1")
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	type standup struct {
+		ID      int64                 `json:"id"`
+		Comment string                `json:"comment"`
+		Answers []model.StandupAnswer `json:"answers"`
+	}
+
+	rec := call(http.MethodGet, "/v1/standups", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listed struct {
+		Standups []standup `json:"standups"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed.Standups, 2)
+	assert.Equal(t, second.ID, listed.Standups[0].ID)
+	assert.Equal(t, []model.StandupAnswer{}, listed.Standups[0].Answers)
+	assert.Equal(t, "Done: bugs", listed.Standups[1].Comment)
+	require.Len(t, listed.Standups[1].Answers, 1)
+	assert.Equal(t, "bugs", listed.Standups[1].Answers[0].Answer)
+
+	// edited text is split into answers again
+	rec = call(http.MethodPatch, fmt.Sprintf("/v1/standups/%v", second.ID), `{"comment":"Doing: tests\nDone: docs"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = call(http.MethodGet, fmt.Sprintf("/v1/standups/%v", second.ID), "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got struct {
+		Standup standup `json:"standup"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "Doing: tests\nDone: docs", got.Standup.Comment)
+	for i := range got.Standup.Answers {
+		got.Standup.Answers[i].ID = 0
+	}
+	assert.Equal(t, []model.StandupAnswer{
+		{StandupID: second.ID, Position: 0, Question: "Done", Answer: "docs"},
+		{StandupID: second.ID, Position: 1, Question: "Doing", Answer: "tests"},
+	}, got.Standup.Answers)
+}
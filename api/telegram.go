@@ -0,0 +1,281 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/botuser"
+	"github.com/maddevsio/comedian/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// telegramSecretHeader carries secret token given to setWebhook
+const telegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// TelegramInstall is a request to connect Telegram bot to Comedian
+type TelegramInstall struct {
+	BotAccessToken string `json:"bot_access_token"`
+	Language       string `json:"language"`
+}
+
+// installTelegram saves Telegram bot as a workspace and points its webhook
+// to Comedian. Bot username is used as BotUserID, so mentions of the bot
+// are recognized the same way Slack mentions are. A workspace of another
+// platform or Bot API server is never taken over
+func (api *ComedianAPI) installTelegram(c echo.Context) error {
+	var install TelegramInstall
+	if err := c.Bind(&install); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	if install.BotAccessToken == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot_access_token is required")
+	}
+
+	if api.config.TelegramWebhookURL == "" || api.config.TelegramSecretToken == "" {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Telegram webhook is not configured")
+	}
+
+	client := botuser.NewTelegramClient(api.config.TelegramAPIURL, install.BotAccessToken)
+
+	me, err := client.GetMe()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("installTelegram failed on GetMe")
+		return echo.NewHTTPError(http.StatusUnauthorized, "Missing or incorrect Bot Access Token")
+	}
+
+	workspaceID := strconv.FormatInt(me.ID, 10)
+
+	settings, err := api.db.GetWorkspaceByWorkspaceID(workspaceID)
+	if err != nil {
+		language := install.Language
+		if language == "" {
+			language = "en"
+		}
+		settings, err = api.db.CreateWorkspace(model.Workspace{
+			CreatedAt:              api.clock.Now().Unix(),
+			BotUserID:              me.Username,
+			NotifierInterval:       30,
+			Language:               language,
+			MaxReminders:           3,
+			ReminderOffset:         10,
+			BlockerEscalationDays:  3,
+			TZ:                     "Asia/Bishkek",
+			BotAccessToken:         install.BotAccessToken,
+			WorkspaceID:            workspaceID,
+			WorkspaceName:          me.Username,
+			ReportingChannel:       "",
+			ReportingTime:          "10am",
+			ProjectsReportsEnabled: false,
+			Platform:               model.PlatformTelegram,
+			ServerURL:              api.config.TelegramAPIURL,
+		})
+		if err != nil {
+			log.WithFields(log.Fields{"bot": me, "error": err}).Error("installTelegram failed on CreateWorkspace")
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	} else {
+		if settings.Platform != model.PlatformTelegram || settings.ServerURL != api.config.TelegramAPIURL {
+			log.WithFields(log.Fields{"bot": me.ID, "platform": settings.Platform, "server": settings.ServerURL}).Error("installTelegram refused to take over workspace")
+			return echo.NewHTTPError(http.StatusConflict, "workspace is installed on another platform or server")
+		}
+		settings.BotUserID = me.Username
+		settings.BotAccessToken = install.BotAccessToken
+
+		settings, err = api.db.UpdateWorkspace(settings)
+		if err != nil {
+			log.WithFields(log.Fields{"bot": me, "error": err}).Error("installTelegram failed on UpdateWorkspace")
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	webhookURL := fmt.Sprintf("%v/telegram/%v", strings.TrimRight(api.config.TelegramWebhookURL, "/"), workspaceID)
+	err = client.SetWebhook(webhookURL, api.config.TelegramSecretToken)
+	if err != nil {
+		log.WithFields(log.Fields{"url": webhookURL, "error": err}).Error("installTelegram failed on SetWebhook")
+		return echo.NewHTTPError(http.StatusBadGateway, "Telegram refused to set the webhook")
+	}
+
+	api.bots.Start(settings)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"bot": settings})
+}
+
+// handleTelegramUpdate handles updates Telegram posts to the webhook. Errors of
+// handling are only logged: Telegram would redeliver the update otherwise
+func (api *ComedianAPI) handleTelegramUpdate(c echo.Context) error {
+	secret := c.Request().Header.Get(telegramSecretHeader)
+	if api.config.TelegramSecretToken == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(api.config.TelegramSecretToken)) != 1 {
+		return echo.NewHTTPError(http.StatusUnauthorized, "wrong secret token")
+	}
+
+	var update botuser.TelegramUpdate
+	if err := c.Bind(&update); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, incorrectDataFormat)
+	}
+
+	bot, err := api.SelectBot(c.Param("workspace"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+
+	err = api.HandleTelegramUpdate(bot, update)
+	if err != nil {
+		log.WithFields(log.Fields{"update": update, "error": err}).Error("HandleTelegramUpdate failed")
+	}
+
+	return c.JSON(http.StatusOK, "Success")
+}
+
+// HandleTelegramUpdate turns Telegram update into Slack event or slash
+// command and passes it to the bot. Only group chats are tracked as projects
+func (api *ComedianAPI) HandleTelegramUpdate(bot *botuser.Bot, update botuser.TelegramUpdate) error {
+	settings := bot.Settings()
+
+	switch {
+	case update.MyChatMember != nil:
+		member := update.MyChatMember
+		if !isTelegramGroup(member.Chat) || member.NewChatMember.User.Username != settings.BotUserID {
+			return nil
+		}
+		if member.NewChatMember.Status == "member" || member.NewChatMember.Status == "administrator" {
+			return api.ensureTelegramProject(bot, member.Chat)
+		}
+		return nil
+
+	case update.EditedMessage != nil:
+		msg := update.EditedMessage
+		if !isTelegramGroup(msg.Chat) || msg.From == nil {
+			return nil
+		}
+		text := telegramText(msg)
+		return bot.HandleMessage(&slack.MessageEvent{
+			Msg: slack.Msg{
+				Type:    "message",
+				SubType: "message_changed",
+				Channel: telegramID(msg.Chat.ID),
+				Text:    text,
+			},
+			SubMessage: &slack.Msg{
+				User:      telegramID(msg.From.ID),
+				Text:      text,
+				Timestamp: telegramID(msg.MessageID),
+			},
+		})
+
+	case update.Message != nil:
+		msg := update.Message
+		if !isTelegramGroup(msg.Chat) || msg.From == nil {
+			return nil
+		}
+
+		err := api.ensureTelegramProject(bot, msg.Chat)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range msg.NewChatMembers {
+			if user.IsBot {
+				continue
+			}
+			_, err := bot.HandleJoin(&slack.MemberJoinedChannelEvent{
+				User:    telegramID(user.ID),
+				Channel: telegramID(msg.Chat.ID),
+				Team:    settings.WorkspaceID,
+			})
+			if err != nil {
+				log.Error("HandleJoin failed: ", err)
+			}
+		}
+
+		text := telegramText(msg)
+		if strings.HasPrefix(text, "/") {
+			return api.handleTelegramCommand(bot, msg, text)
+		}
+
+		return bot.HandleMessage(&slack.MessageEvent{
+			Msg: slack.Msg{
+				Type:      "message",
+				Channel:   telegramID(msg.Chat.ID),
+				User:      telegramID(msg.From.ID),
+				Text:      text,
+				Timestamp: telegramID(msg.MessageID),
+			},
+		})
+	}
+
+	return nil
+}
+
+// handleTelegramCommand runs bot command like "/deadline@comedian_bot 10am"
+// and replies to the chat
+func (api *ComedianAPI) handleTelegramCommand(bot *botuser.Bot, msg *botuser.TelegramMessage, text string) error {
+	parts := strings.SplitN(text, " ", 2)
+	command := parts[0]
+	if i := strings.Index(command, "@"); i >= 0 {
+		if command[i+1:] != bot.Settings().BotUserID {
+			// command addressed to another bot in the chat
+			return nil
+		}
+		command = command[:i]
+	}
+
+	var args string
+	if len(parts) == 2 {
+		args = strings.TrimSpace(parts[1])
+	}
+
+	userName := msg.From.Username
+	if userName == "" {
+		userName = strings.TrimSpace(msg.From.FirstName + " " + msg.From.LastName)
+	}
+
+	message := bot.ImplementCommands(slack.SlashCommand{
+		TeamID:      bot.Settings().WorkspaceID,
+		ChannelID:   telegramID(msg.Chat.ID),
+		ChannelName: msg.Chat.Title,
+		UserID:      telegramID(msg.From.ID),
+		UserName:    userName,
+		Command:     command,
+		Text:        args,
+	})
+	if message == "" {
+		return nil
+	}
+
+	return bot.SendMessage(telegramID(msg.Chat.ID), message, nil)
+}
+
+func (api *ComedianAPI) ensureTelegramProject(bot *botuser.Bot, chat botuser.TelegramChat) error {
+	_, err := api.db.SelectProject(telegramID(chat.ID))
+	if err == nil {
+		return nil
+	}
+
+	_, err = bot.HandleJoin(&slack.MemberJoinedChannelEvent{
+		User:    bot.Settings().BotUserID,
+		Channel: telegramID(chat.ID),
+		Team:    bot.Settings().WorkspaceID,
+	})
+	return err
+}
+
+func isTelegramGroup(chat botuser.TelegramChat) bool {
+	return chat.Type == "group" || chat.Type == "supergroup"
+}
+
+func telegramText(msg *botuser.TelegramMessage) string {
+	if msg.Text != "" {
+		return msg.Text
+	}
+	return msg.Caption
+}
+
+func telegramID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// mockTelegram answers Telegram Bot API methods Comedian calls and
+// remembers them with their params
+type mockTelegram struct {
+	mu    sync.Mutex
+	calls []map[string]interface{}
+}
+
+func (m *mockTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/botbot-token/") {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"ok":false,"error_code":401,"description":"Unauthorized"}`))
+		return
+	}
+
+	params := map[string]interface{}{}
+	json.NewDecoder(r.Body).Decode(&params)
+	params["method"] = strings.TrimPrefix(r.URL.Path, "/botbot-token/")
+	m.mu.Lock()
+	m.calls = append(m.calls, params)
+	m.mu.Unlock()
+
+	switch params["method"] {
+	case "getMe":
+		w.Write([]byte(`{"ok":true,"result":{"id":777,"is_bot":true,"first_name":"Comedian","username":"comedian_bot"}}`))
+	case "getChat":
+		w.Write([]byte(`{"ok":true,"result":{"id":-100,"type":"supergroup","title":"backend"}}`))
+	case "sendMessage":
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1000,"chat":{"id":-100}}}`))
+	default:
+		w.Write([]byte(`{"ok":true,"result":true}`))
+	}
+}
+
+func (m *mockTelegram) flush() []map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls := m.calls
+	m.calls = nil
+	return calls
+}
+
+func TestTelegram(t *testing.T) {
+	tg := &mockTelegram{}
+	server := httptest.NewServer(tg)
+	defer server.Close()
+
+	db := storage.NewMemoryDB()
+	api := New(&config.Config{
+		TelegramAPIURL:      server.URL,
+		TelegramWebhookURL:  "https://comedian.example.com/",
+		TelegramSecretToken: "secret",
+		AdminToken:          "admin-token",
+	}, db, i18n.NewBundle(language.English))
+
+	do := func(path, secret, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if secret != "" {
+			req.Header.Set(telegramSecretHeader, secret)
+		}
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	install := func(adminToken, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/telegram/install", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderAuthorization, adminToken)
+		rec := httptest.NewRecorder()
+		api.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// install is for admins only
+	rec := install("", `{"bot_access_token":"bot-token"}`)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, tg.flush())
+
+	rec = install("admin-token", `{"bot_access_token":"wrong"}`)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = install("admin-token", `{"bot_access_token":"bot-token"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	ws, err := db.GetWorkspaceByWorkspaceID("777")
+	require.NoError(t, err)
+	assert.Equal(t, model.PlatformTelegram, ws.Platform)
+	assert.Equal(t, "comedian_bot", ws.BotUserID)
+	assert.Equal(t, server.URL, ws.ServerURL)
+
+	calls := tg.flush()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "setWebhook", calls[1]["method"])
+	assert.Equal(t, "https://comedian.example.com/telegram/777", calls[1]["url"])
+	assert.Equal(t, "secret", calls[1]["secret_token"])
+
+	// a workspace of another platform is not taken over
+	ws.Platform = model.PlatformSlack
+	_, err = db.UpdateWorkspace(ws)
+	require.NoError(t, err)
+	rec = install("admin-token", `{"bot_access_token":"bot-token"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	ws, err = db.GetWorkspaceByWorkspaceID("777")
+	require.NoError(t, err)
+	assert.Equal(t, model.PlatformSlack, ws.Platform)
+	ws.Platform = model.PlatformTelegram
+	_, err = db.UpdateWorkspace(ws)
+	require.NoError(t, err)
+	tg.flush()
+
+	update := func(body string) {
+		rec := do("/telegram/777", "secret", body)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	rec = do("/telegram/777", "wrong", `{"update_id":1}`)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// bot is added to the group
+	update(`{"update_id":1,"my_chat_member":{"chat":{"id":-100,"type":"supergroup","title":"backend"},"from":{"id":42},
+		"new_chat_member":{"user":{"id":777,"is_bot":true,"username":"comedian_bot"},"status":"member"}}}`)
+
+	project, err := db.SelectProject("-100")
+	require.NoError(t, err)
+	assert.Equal(t, "backend", project.ChannelName)
+	assert.Equal(t, "777", project.WorkspaceID)
+	tg.flush()
+
+	// commands addressed to other bots are ignored
+	update(`{"update_id":2,"message":{"message_id":10,"from":{"id":42,"first_name":"John","username":"john"},
+		"chat":{"id":-100,"type":"supergroup","title":"backend"},"text":"/start@other_bot developer"}}`)
+	assert.Empty(t, tg.flush())
+
+	update(`{"update_id":3,"message":{"message_id":11,"from":{"id":42,"first_name":"John","username":"john"},
+		"chat":{"id":-100,"type":"supergroup","title":"backend"},"text":"/start@comedian_bot developer"}}`)
+
+	standuper, err := db.FindStansuperByUserID("42", "-100")
+	require.NoError(t, err)
+	assert.Equal(t, "developer", standuper.Role)
+
+	calls = tg.flush()
+	require.NotEmpty(t, calls)
+	reply := calls[len(calls)-1]
+	assert.Equal(t, "sendMessage", reply["method"])
+	assert.Equal(t, "-100", reply["chat_id"])
+	assert.Equal(t, "Welcome to the standup team, no standup deadline has been setup yet", reply["text"])
+
+	update(`{"update_id":4,"message":{"message_id":12,"from":{"id":42,"first_name":"John"},
+		"chat":{"id":-100,"type":"supergroup","title":"backend"},"text":"@comedian_bot yesterday fixed bugs, today write tests, no issues"}}`)
+
+	standup, err := db.SelectStandupByMessageTS("777", "-100", "12")
+	require.NoError(t, err)
+	assert.Equal(t, "42", standup.UserID)
+	assert.Equal(t, "-100", standup.ChannelID)
+	assert.Equal(t, "777", standup.WorkspaceID)
+
+	calls = tg.flush()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "setMessageReaction", calls[0]["method"])
+	assert.Equal(t, float64(12), calls[0]["message_id"])
+
+	update(`{"update_id":5,"edited_message":{"message_id":12,"from":{"id":42,"first_name":"John"},
+		"chat":{"id":-100,"type":"supergroup","title":"backend"},"text":"@comedian_bot yesterday fixed bugs, today write more tests, no issues"}}`)
+
+	standup, err = db.SelectStandupByMessageTS("777", "-100", "12")
+	require.NoError(t, err)
+	assert.Equal(t, "@comedian_bot yesterday fixed bugs, today write more tests, no issues", standup.Comment)
+	tg.flush()
+
+	// message ids are only unique inside one chat
+	update(`{"update_id":6,"message":{"message_id":12,"from":{"id":43,"first_name":"Jane"},
+		"chat":{"id":-200,"type":"supergroup","title":"frontend"},"text":"@comedian_bot yesterday made layout, today fix styles, no issues"}}`)
+	update(`{"update_id":7,"edited_message":{"message_id":12,"from":{"id":43,"first_name":"Jane"},
+		"chat":{"id":-200,"type":"supergroup","title":"frontend"},"text":"@comedian_bot yesterday made layout, today fix more styles, no issues"}}`)
+
+	other, err := db.SelectStandupByMessageTS("777", "-200", "12")
+	require.NoError(t, err)
+	assert.NotEqual(t, standup.ID, other.ID)
+	assert.Equal(t, "@comedian_bot yesterday made layout, today fix more styles, no issues", other.Comment)
+
+	standup, err = db.SelectStandupByMessageTS("777", "-100", "12")
+	require.NoError(t, err)
+	assert.Equal(t, "@comedian_bot yesterday fixed bugs, today write more tests, no issues", standup.Comment)
+
+	// private chats are not projects
+	update(`{"update_id":8,"message":{"message_id":1,"from":{"id":42,"first_name":"John"},
+		"chat":{"id":42,"type":"private"},"text":"@comedian_bot yesterday fixed bugs, today write tests, no issues"}}`)
+	_, err = db.SelectProject("42")
+	assert.Error(t, err)
+}
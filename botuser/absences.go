@@ -0,0 +1,239 @@
+package botuser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// absentBetween tells if the user has absence on record overlapping the days
+func (bot *Bot) absentBetween(userID string, from, to time.Time) bool {
+	absences, err := bot.db.ListUserAbsences(bot.workspace.WorkspaceID, userID, from.Format(model.DateLayout), to.Format(model.DateLayout))
+	if err != nil {
+		log.Error("ListUserAbsences failed: ", err)
+		return false
+	}
+	return len(absences) > 0
+}
+
+// absentToday tells if the user is away today in the time zone. Chat status
+// with one of AbsenceStatusEmojis is recorded as absence for the day, so
+// the report about the day knows about it after the status is cleared
+func (bot *Bot) absentToday(userID string, loc *time.Location) bool {
+	now := bot.clock.Now().In(loc)
+
+	if bot.absentBetween(userID, now, now) {
+		return true
+	}
+
+	emoji, away := bot.awayByStatus(userID, now)
+	if !away {
+		return false
+	}
+
+	today := now.Format(model.DateLayout)
+	_, err := bot.db.CreateAbsence(model.Absence{
+		CreatedAt:   now.Unix(),
+		WorkspaceID: bot.workspace.WorkspaceID,
+		UserID:      userID,
+		StartDate:   today,
+		EndDate:     today,
+		Reason:      emoji,
+		Source:      model.AbsenceChatStatus,
+	})
+	if err != nil {
+		log.Error("CreateAbsence failed: ", err)
+	}
+	return true
+}
+
+// awayByStatus checks chat status of the user against AbsenceStatusEmojis
+func (bot *Bot) awayByStatus(userID string, now time.Time) (string, bool) {
+	if len(bot.conf.AbsenceStatusEmojis) == 0 {
+		return "", false
+	}
+
+	u, err := bot.user(userID)
+	if err != nil {
+		log.Error("awayByStatus user failed: ", err)
+		return "", false
+	}
+	if u.StatusEmoji == "" || (u.StatusExpiration != 0 && u.StatusExpiration <= now.Unix()) {
+		return "", false
+	}
+
+	for _, emoji := range bot.conf.AbsenceStatusEmojis {
+		if strings.Trim(emoji, ": ") == strings.Trim(u.StatusEmoji, ":") {
+			return u.StatusEmoji, true
+		}
+	}
+	return "", false
+}
+
+// manageAbsences records absence of the user, e.g. "/vacation 2019-12-23 2020-01-03 skiing"
+// or "/vacation @john 2019-12-20 sick". "/vacation cancel" removes current and
+// upcoming absences, empty text lists them
+func (bot *Bot) manageAbsences(command slack.SlashCommand) string {
+	userID := command.UserID
+	fields := strings.Fields(command.Text)
+	if len(fields) > 0 {
+		if m := mentionPattern.FindStringSubmatch(fields[0]); m != nil {
+			userID = m[1]
+			fields = fields[1:]
+		}
+	}
+
+	now := bot.clock.Now().In(bot.userLocation(userID, command.ChannelID))
+	today := now.Format(model.DateLayout)
+
+	if len(fields) == 0 {
+		return bot.listAbsences(userID, today)
+	}
+
+	if strings.ToLower(fields[0]) == "cancel" {
+		absences, err := bot.upcomingAbsences(userID, today)
+		if err != nil {
+			log.Error("ListWorkspaceAbsences failed: ", err)
+			return bot.absenceNotSet()
+		}
+		for _, a := range absences {
+			err := bot.db.DeleteAbsence(a.ID)
+			if err != nil {
+				log.Error("DeleteAbsence failed: ", err)
+				return bot.absenceNotSet()
+			}
+		}
+
+		absencesCancelled, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "absencesCancelled",
+				Other: "Absences of <@{{.User}}> are cancelled",
+			},
+			TemplateData: map[string]interface{}{"User": userID},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return absencesCancelled
+	}
+
+	start := fields[0]
+	end := start
+	fields = fields[1:]
+	if len(fields) > 0 && fields[0] == "-" {
+		fields = fields[1:]
+	}
+	if len(fields) > 0 {
+		if _, err := time.Parse(model.DateLayout, fields[0]); err == nil {
+			end = fields[0]
+			fields = fields[1:]
+		}
+	}
+
+	absence, err := bot.db.CreateAbsence(model.Absence{
+		CreatedAt:   now.Unix(),
+		WorkspaceID: bot.workspace.WorkspaceID,
+		UserID:      userID,
+		StartDate:   start,
+		EndDate:     end,
+		Reason:      strings.Join(fields, " "),
+		Source:      model.AbsenceManual,
+	})
+	if err != nil {
+		log.Error("CreateAbsence failed: ", err)
+		vacationUsage, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "vacationUsage",
+				Other: "Use /vacation [@user] 2019-12-23 2020-01-03 [reason] to tell when you are away, /vacation [@user] cancel to cancel it",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return vacationUsage
+	}
+
+	absenceAdded, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "absenceAdded",
+			Other: "<@{{.User}}> is away from {{.From}} to {{.To}}, no standups expected",
+		},
+		TemplateData: map[string]interface{}{"User": userID, "From": absence.StartDate, "To": absence.EndDate},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return absenceAdded
+}
+
+func (bot *Bot) listAbsences(userID, today string) string {
+	absences, err := bot.upcomingAbsences(userID, today)
+	if err != nil {
+		log.Error("ListWorkspaceAbsences failed: ", err)
+		return bot.absenceNotSet()
+	}
+
+	if len(absences) == 0 {
+		noAbsences, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "noAbsences",
+				Other: "<@{{.User}}> has no upcoming absences",
+			},
+			TemplateData: map[string]interface{}{"User": userID},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return noAbsences
+	}
+
+	var list []string
+	for _, a := range absences {
+		list = append(list, strings.TrimSpace(fmt.Sprintf("%v - %v %v", a.StartDate, a.EndDate, a.Reason)))
+	}
+
+	listAbsences, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "listAbsences",
+			Other: "<@{{.User}}> is away:\n{{.Absences}}",
+		},
+		TemplateData: map[string]interface{}{"User": userID, "Absences": strings.Join(list, "\n")},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return listAbsences
+}
+
+func (bot *Bot) upcomingAbsences(userID, today string) ([]model.Absence, error) {
+	var absences []model.Absence
+
+	all, err := bot.db.ListWorkspaceAbsences(bot.workspace.WorkspaceID, today)
+	if err != nil {
+		return absences, err
+	}
+	for _, a := range all {
+		if a.UserID == userID {
+			absences = append(absences, a)
+		}
+	}
+	return absences, nil
+}
+
+func (bot *Bot) absenceNotSet() string {
+	absenceNotSet, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "absenceNotSet",
+			Other: "Could not change absences",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return absenceNotSet
+}
@@ -0,0 +1,154 @@
+package botuser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManageAbsences(t *testing.T) {
+	bot, _ := newTestBot()
+	bot.SetClock(clock.NewMock(time.Date(2019, 12, 20, 12, 0, 0, 0, time.UTC)))
+
+	vacation := func(text string) string {
+		return bot.ImplementCommands(slack.SlashCommand{
+			Command:   "/vacation",
+			Text:      text,
+			TeamID:    "testTeam",
+			ChannelID: "CHAN1",
+			UserID:    "U1",
+		})
+	}
+
+	usage := "Use /vacation [@user] 2019-12-23 2020-01-03 [reason] to tell when you are away, /vacation [@user] cancel to cancel it"
+
+	testCases := []struct {
+		text     string
+		expected string
+	}{
+		{"", "<@U1> has no upcoming absences"},
+		{"2019-12-23 - 2020-01-03 skiing", "<@U1> is away from 2019-12-23 to 2020-01-03, no standups expected"},
+		{"2019-12-20", "<@U1> is away from 2019-12-20 to 2019-12-20, no standups expected"},
+		{"2019-12-10 2019-12-12", "<@U1> is away from 2019-12-10 to 2019-12-12, no standups expected"},
+		{"next week", usage},
+		{"2020-01-03 2019-12-23", usage},
+		{"", "<@U1> is away:\n2019-12-20 - 2019-12-20\n2019-12-23 - 2020-01-03 skiing"},
+		{"<@U2|john> 2019-12-24 sick", "<@U2> is away from 2019-12-24 to 2019-12-24, no standups expected"},
+		{"cancel", "Absences of <@U1> are cancelled"},
+		{"", "<@U1> has no upcoming absences"},
+		{"<@U2|john>", "<@U2> is away:\n2019-12-24 - 2019-12-24 sick"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, vacation(tc.text), tc.text)
+	}
+
+	// past absences are kept
+	absences, err := bot.db.ListWorkspaceAbsences("testTeam", "")
+	require.NoError(t, err)
+	require.Len(t, absences, 2)
+	assert.Equal(t, "2019-12-10", absences[0].StartDate)
+	assert.Equal(t, model.AbsenceManual, absences[0].Source)
+}
+
+func TestAbsentStandupers(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.conf.AbsenceStatusEmojis = []string{"palm_tree", ":face_with_thermometer:"}
+	now := time.Date(2019, 12, 23, 9, 50, 0, 0, time.UTC)
+	bot.SetClock(clock.NewMock(now))
+
+	channel, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "UTC",
+		SubmissionDays: "monday",
+	})
+	require.NoError(t, err)
+
+	for _, id := range []string{"U1", "U2", "U3", "U4"} {
+		messenger.users[id] = User{ID: id, TZ: "UTC"}
+		_, err := bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "CHAN1", UserID: id})
+		require.NoError(t, err)
+	}
+	// U2 is on vacation, U3 is sick by chat status, status of U4 is expired
+	_, err = bot.db.CreateAbsence(model.Absence{WorkspaceID: "testTeam", UserID: "U2", StartDate: "2019-12-23", EndDate: "2020-01-03", Source: model.AbsenceManual})
+	require.NoError(t, err)
+	messenger.users["U3"] = User{ID: "U3", TZ: "UTC", StatusEmoji: ":face_with_thermometer:"}
+	messenger.users["U4"] = User{ID: "U4", TZ: "UTC", StatusEmoji: ":palm_tree:", StatusExpiration: now.Add(-time.Hour).Unix()}
+
+	deadline := time.Date(2019, 12, 23, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, bot.warn(channel, "", 0, deadline))
+	messages := messenger.flush()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "<@U1>, <@U4> you may miss the deadline in 10 minutes", messages[0].Text)
+
+	absences, err := bot.db.ListUserAbsences("testTeam", "U3", "2019-12-23", "2019-12-23")
+	require.NoError(t, err)
+	require.Len(t, absences, 1)
+	assert.Equal(t, model.AbsenceChatStatus, absences[0].Source)
+	assert.Equal(t, ":face_with_thermometer:", absences[0].Reason)
+
+	remindAt, err := bot.alarm(channel, "", 0, deadline)
+	require.NoError(t, err)
+	messages = messenger.flush()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "<@U1>, <@U4> you have missed standup deadlines, shame!", messages[0].Text)
+
+	// U4 leaves after the alarm and is not reminded anymore
+	_, err = bot.db.CreateAbsence(model.Absence{WorkspaceID: "testTeam", UserID: "U4", StartDate: "2019-12-23", EndDate: "2019-12-23", Source: model.AbsenceManual})
+	require.NoError(t, err)
+
+	_, err = bot.remind(channel, "", 0, remindAt)
+	require.NoError(t, err)
+	messages = messenger.flush()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "<@U1>, you still haven't written a standup! Write a standup!", messages[0].Text)
+}
+
+func TestAbsentInReport(t *testing.T) {
+	bot, messenger := newTestBot(
+		&i18n.Message{ID: "reportHeader", Other: "Daily report"},
+		&i18n.Message{ID: "noStandup", Other: "no standup"},
+		&i18n.Message{ID: "tagStanduper", Other: "<@{{.user}}> in #{{.channel}}"},
+	)
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		TZ:             "Local",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday, saturday, sunday",
+	})
+	require.NoError(t, err)
+	_, err = bot.db.CreateProject(model.Project{
+		WorkspaceID: "testTeam",
+		ChannelID:   "CREP",
+		ChannelName: "reports",
+		TZ:          "Local",
+	})
+	require.NoError(t, err)
+	_, err = bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "CHAN1", UserID: "U1"})
+	require.NoError(t, err)
+
+	_, err = bot.displayYesterdayTeamReport()
+	require.NoError(t, err)
+	messages := messenger.flush()
+	require.Len(t, messages, 1)
+	require.Len(t, messages[0].Attachments, 1)
+	assert.Equal(t, "<@U1> in #general", messages[0].Attachments[0].Text)
+
+	yesterday := time.Now().AddDate(0, 0, -1).Format(model.DateLayout)
+	_, err = bot.db.CreateAbsence(model.Absence{WorkspaceID: "testTeam", UserID: "U1", StartDate: yesterday, EndDate: yesterday, Source: model.AbsenceManual})
+	require.NoError(t, err)
+
+	_, err = bot.displayYesterdayTeamReport()
+	require.NoError(t, err)
+	assert.Empty(t, messenger.flush())
+}
@@ -0,0 +1,353 @@
+package botuser
+
+import (
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+const (
+	// blockerCallbackID marks clicks on resolve buttons of blockers
+	blockerCallbackID    = "blocker"
+	resolveBlockerName   = "resolve"
+	blockerBulletTrimSet = " \t-–—*•·"
+)
+
+// noBlockers matches answers like "none" or "нет проблем" which mean there
+// is nothing blocking the standuper
+var noBlockers = regexp.MustCompile(`(?i)^(no|none|nothing|nope|n/?a|-+|нет|ничего|не мешает)( (blockers?|issues?|problems?|проблем|мешает))?[.!]*$`)
+
+// blockerItems returns blockers listed in the answer, one per line
+func blockerItems(answer string) []string {
+	items := []string{}
+	for _, line := range strings.Split(answer, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, blockerBulletTrimSet))
+		if line == "" || noBlockers.MatchString(line) {
+			continue
+		}
+		items = append(items, line)
+	}
+	return items
+}
+
+// SyncBlockers brings blockers of the standup in line with its answers to
+// the blockers section of the template. A blocker the user already has open
+// in the channel keeps its age and moves to the newer standup mentioning it,
+// blockers removed from the edited standup are deleted unless somebody
+// resolved them
+func SyncBlockers(db storage.Store, template model.StandupTemplate, standup model.Standup, answers []model.StandupAnswer, now time.Time) error {
+	texts := []string{}
+	for _, a := range answers {
+		for _, section := range template {
+			if section.Blockers && strings.EqualFold(section.Name, a.Question) {
+				texts = append(texts, blockerItems(a.Answer)...)
+			}
+		}
+	}
+
+	blockers, err := db.ListWorkspaceBlockers(standup.WorkspaceID, model.BlockerOpen)
+	if err != nil {
+		return err
+	}
+	open := map[string]bool{}
+	for _, b := range blockers {
+		if b.ChannelID != standup.ChannelID || b.UserID != standup.UserID {
+			continue
+		}
+		mentioned := containsFold(texts, b.Text)
+		if b.StandupID == standup.ID && !mentioned {
+			err = db.DeleteBlocker(b.ID)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if mentioned && b.StandupID < standup.ID {
+			b.StandupID = standup.ID
+			_, err = db.UpdateBlocker(b)
+			if err != nil {
+				return err
+			}
+		}
+		open[strings.ToLower(b.Text)] = true
+	}
+
+	for _, text := range texts {
+		if open[strings.ToLower(text)] {
+			continue
+		}
+		open[strings.ToLower(text)] = true
+		_, err = db.CreateBlocker(model.Blocker{
+			CreatedAt:   now.Unix(),
+			WorkspaceID: standup.WorkspaceID,
+			ChannelID:   standup.ChannelID,
+			UserID:      standup.UserID,
+			StandupID:   standup.ID,
+			Text:        text,
+			Status:      model.BlockerOpen,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsFold(items []string, text string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// saveBlockers tracks blockers of the standup with the channel template
+func (bot *Bot) saveBlockers(standup model.Standup, answers []model.StandupAnswer) error {
+	template, _ := bot.standupTemplate(standup.ChannelID)
+	return SyncBlockers(bot.db, template, standup, answers, bot.clock.Now())
+}
+
+// sendBlockersDigest sends open blockers of every project to its PMs and
+// reports blockers open for BlockerEscalationDays to the reporting channel
+func (bot *Bot) sendBlockersDigest(now time.Time) error {
+	blockers, err := bot.db.ListWorkspaceBlockers(bot.workspace.WorkspaceID, model.BlockerOpen)
+	if err != nil {
+		return err
+	}
+
+	byChannel := map[string][]model.Blocker{}
+	channelIDs := []string{}
+	for _, b := range blockers {
+		if len(byChannel[b.ChannelID]) == 0 {
+			channelIDs = append(channelIDs, b.ChannelID)
+		}
+		byChannel[b.ChannelID] = append(byChannel[b.ChannelID], b)
+	}
+
+	channels := map[string]model.Project{}
+	for _, channelID := range channelIDs {
+		channel, err := bot.db.SelectProject(channelID)
+		if err != nil || channel.Inactive {
+			continue
+		}
+		channels[channelID] = channel
+
+		standupers, err := bot.db.ListProjectStandupers(channelID)
+		if err != nil {
+			log.Errorf("ListProjectStandupers failed for channel %v: %v", channel.ChannelName, err)
+			continue
+		}
+		attachments := []slack.Attachment{}
+		for _, b := range byChannel[channelID] {
+			attachments = append(attachments, bot.blockerAttachment(b, channel, now))
+		}
+		header, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "blockersDigest",
+				Other: "Open blockers in #{{.Channel}}:",
+			},
+			TemplateData: map[string]interface{}{"Channel": channel.ChannelName},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		for _, standuper := range standupers {
+			if standuper.Role != "pm" {
+				continue
+			}
+			err = bot.send(&Message{Type: "direct", User: standuper.UserID, Text: header, Attachments: attachments})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if bot.workspace.BlockerEscalationDays == 0 || bot.workspace.ReportingChannel == "" {
+		return nil
+	}
+	overdue := []model.Blocker{}
+	attachments := []slack.Attachment{}
+	for _, b := range blockers {
+		channel, ok := channels[b.ChannelID]
+		if !ok || b.EscalatedAt != 0 || b.Age(now) < bot.workspace.BlockerEscalationDays {
+			continue
+		}
+		overdue = append(overdue, b)
+		attachments = append(attachments, bot.blockerAttachment(b, channel, now))
+	}
+	if len(overdue) == 0 {
+		return nil
+	}
+
+	header, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "blockersEscalated",
+			One:   "Blockers open for {{.Days}} day or more:",
+			Other: "Blockers open for {{.Days}} days or more:",
+		},
+		PluralCount:  bot.workspace.BlockerEscalationDays,
+		TemplateData: map[string]interface{}{"Days": bot.workspace.BlockerEscalationDays},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	err = bot.send(&Message{Type: "message", Channel: bot.workspace.ReportingChannel, Text: header, Attachments: attachments})
+	if err != nil {
+		return err
+	}
+	// escalated blockers are reported once
+	for _, b := range overdue {
+		b.EscalatedAt = now.Unix()
+		_, err = bot.db.UpdateBlocker(b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockerAttachment shows the blocker with its owner and age and, in Slack,
+// a button which resolves it
+func (bot *Bot) blockerAttachment(b model.Blocker, channel model.Project, now time.Time) slack.Attachment {
+	text, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "blockerLine",
+			One:   "<@{{.User}}> in #{{.Channel}}, open for {{.Days}} day: {{.Text}}",
+			Other: "<@{{.User}}> in #{{.Channel}}, open for {{.Days}} days: {{.Text}}",
+		},
+		PluralCount: b.Age(now),
+		TemplateData: map[string]interface{}{
+			"User":    b.UserID,
+			"Channel": channel.ChannelName,
+			"Days":    b.Age(now),
+			"Text":    b.Text,
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	attachment := slack.Attachment{
+		CallbackID: blockerCallbackID,
+		Fallback:   text,
+		Text:       text,
+	}
+	// only Slack sends button clicks to /interactions
+	if bot.workspace.Platform == "" || bot.workspace.Platform == model.PlatformSlack {
+		resolve, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "resolveBlocker",
+				Other: "Resolve",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		attachment.Actions = []slack.AttachmentAction{{
+			Name:  resolveBlockerName,
+			Text:  resolve,
+			Type:  "button",
+			Style: "primary",
+			Value: strconv.FormatInt(b.ID, 10),
+		}}
+	}
+	return attachment
+}
+
+// HandleBlockerAction resolves the blocker whose button was clicked and
+// returns the message with the button replaced by who resolved it. Users
+// other than the owner and PMs of the project get an ephemeral refusal
+// instead. Nil means the interaction is not about blockers
+func (bot *Bot) HandleBlockerAction(callback *slack.InteractionCallback) (*slack.Message, error) {
+	if callback.CallbackID != blockerCallbackID || len(callback.ActionCallback.AttachmentActions) == 0 {
+		return nil, nil
+	}
+	action := callback.ActionCallback.AttachmentActions[0]
+	if action.Name != resolveBlockerName {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseInt(action.Value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	blocker, err := bot.db.GetBlocker(id)
+	if err != nil {
+		return nil, err
+	}
+	if blocker.WorkspaceID != bot.workspace.WorkspaceID {
+		return nil, sql.ErrNoRows
+	}
+	if !bot.canResolveBlocker(callback.User.ID, blocker) {
+		denied, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "blockerResolveDenied",
+				Other: "Only the owner of the blocker or PMs of the project can resolve it",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return &slack.Message{Msg: slack.Msg{ResponseType: slack.ResponseTypeEphemeral, Text: denied}}, nil
+	}
+	if blocker.Status == model.BlockerOpen {
+		blocker.Status = model.BlockerResolved
+		blocker.ResolvedAt = bot.clock.Now().Unix()
+		blocker.ResolvedBy = callback.User.ID
+		blocker, err = bot.db.UpdateBlocker(blocker)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resolved, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "blockerResolved",
+			Other: "Resolved by <@{{.User}}>",
+		},
+		TemplateData: map[string]interface{}{"User": blocker.ResolvedBy},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	message := callback.OriginalMessage
+	message.Attachments = append([]slack.Attachment{}, message.Attachments...)
+	for i, attachment := range message.Attachments {
+		for _, a := range attachment.Actions {
+			if a.Name == resolveBlockerName && a.Value == action.Value {
+				message.Attachments[i].Actions = nil
+				message.Attachments[i].Footer = resolved
+			}
+		}
+	}
+	message.ReplaceOriginal = true
+	return &message, nil
+}
+
+// canResolveBlocker tells if the user owns the blocker or is a PM of its
+// project
+func (bot *Bot) canResolveBlocker(userID string, b model.Blocker) bool {
+	if userID == b.UserID {
+		return true
+	}
+	standupers, err := bot.db.ListProjectStandupers(b.ChannelID)
+	if err != nil {
+		log.Error("ListProjectStandupers failed: ", err)
+		return false
+	}
+	for _, standuper := range standupers {
+		if standuper.UserID == userID && standuper.Role == "pm" {
+			return true
+		}
+	}
+	return false
+}
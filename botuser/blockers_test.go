@@ -0,0 +1,145 @@
+package botuser
+
+import (
+	"database/sql"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/model"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockerItems(t *testing.T) {
+	assert.Equal(t, []string{"waiting for API keys", "CI is red"}, blockerItems("- waiting for API keys\n\n* CI is red"))
+	assert.Empty(t, blockerItems("none"))
+	assert.Empty(t, blockerItems("No blockers."))
+	assert.Empty(t, blockerItems("нет проблем"))
+	assert.Empty(t, blockerItems("-"))
+}
+
+func TestBlockers(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.workspace.BlockerEscalationDays = 2
+	clk := clock.NewMock(time.Date(2019, 11, 4, 9, 0, 0, 0, time.UTC))
+	bot.SetClock(clk)
+
+	_, err := bot.db.CreateProject(model.Project{WorkspaceID: "testTeam", ChannelID: "CHAN1", ChannelName: "general", TZ: "UTC"})
+	require.NoError(t, err)
+	for _, s := range []model.Standuper{{UserID: "U1"}, {UserID: "PM", Role: "pm"}} {
+		s.WorkspaceID = "testTeam"
+		s.ChannelID = "CHAN1"
+		_, err := bot.db.CreateStanduper(s)
+		require.NoError(t, err)
+	}
+
+	post := func(ts, text string) {
+		require.NoError(t, bot.HandleMessage(&slack.MessageEvent{Msg: slack.Msg{Channel: "CHAN1", User: "U1", Text: "<@BOT> " + text, Timestamp: ts}}))
+	}
+	open := func() []string {
+		blockers, err := bot.db.ListWorkspaceBlockers("testTeam", model.BlockerOpen)
+		require.NoError(t, err)
+		texts := []string{}
+		for _, b := range blockers {
+			texts = append(texts, b.Text)
+		}
+		return texts
+	}
+
+	post("1.1", "Yesterday: fixed bugs\nToday: tests\nIssues:\n- waiting for API keys\n- CI is red")
+	assert.Equal(t, []string{"waiting for API keys", "CI is red"}, open())
+	// edited standup drops the blocker which is gone
+	require.NoError(t, bot.HandleMessage(&slack.MessageEvent{
+		Msg:        slack.Msg{Channel: "CHAN1", User: "U1", SubType: typeEditMessage, Text: "<@BOT>"},
+		SubMessage: &slack.Msg{User: "U1", Text: "<@BOT> Yesterday: fixed bugs\nToday: tests\nIssues: waiting for API keys", Timestamp: "1.1"},
+	}))
+	assert.Equal(t, []string{"waiting for API keys"}, open())
+
+	// the same blocker on the next day keeps its age
+	clk.Add(24 * time.Hour)
+	post("2.1", "Yesterday: tests\nToday: release\nIssues: Waiting for API keys\nno access to staging")
+	assert.Equal(t, []string{"waiting for API keys", "no access to staging"}, open())
+	messenger.flush()
+
+	// the blocker moves to the latest standup, so deleting the first one keeps it
+	first, err := bot.db.SelectStandupByMessageTS("testTeam", "CHAN1", "1.1")
+	require.NoError(t, err)
+	second, err := bot.db.SelectStandupByMessageTS("testTeam", "CHAN1", "2.1")
+	require.NoError(t, err)
+	blockers, err := bot.db.ListWorkspaceBlockers("testTeam", model.BlockerOpen)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, blockers[0].StandupID)
+	require.NoError(t, bot.db.DeleteStandup(first.ID))
+	assert.Equal(t, []string{"waiting for API keys", "no access to staging"}, open())
+
+	clk.Add(24 * time.Hour)
+	require.NoError(t, bot.sendBlockersDigest(clk.Now()))
+	messages := messenger.flush()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "DPM", messages[0].Channel)
+	assert.Equal(t, "Open blockers in #general:", messages[0].Text)
+	require.Len(t, messages[0].Attachments, 2)
+	assert.Equal(t, "<@U1> in #general, open for 2 days: waiting for API keys", messages[0].Attachments[0].Text)
+	assert.Equal(t, "<@U1> in #general, open for 1 day: no access to staging", messages[0].Attachments[1].Text)
+	assert.Equal(t, "reports", messages[1].Channel)
+	assert.Equal(t, "Blockers open for 2 days or more:", messages[1].Text)
+	require.Len(t, messages[1].Attachments, 1)
+
+	// blockers are escalated once
+	require.NoError(t, bot.sendBlockersDigest(clk.Now()))
+	messages = messenger.flush()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "DPM", messages[0].Channel)
+
+	callback := &slack.InteractionCallback{
+		CallbackID:      blockerCallbackID,
+		User:            slack.User{ID: "PM"},
+		OriginalMessage: slack.Message{Msg: slack.Msg{Text: messages[0].Text, Attachments: messages[0].Attachments}},
+		ActionCallback:  slack.ActionCallbacks{AttachmentActions: []*slack.AttachmentAction{&messages[0].Attachments[1].Actions[0]}},
+	}
+	// only the owner and PMs resolve blockers
+	callback.User.ID = "U2"
+	message, err := bot.HandleBlockerAction(callback)
+	require.NoError(t, err)
+	assert.False(t, message.ReplaceOriginal)
+	assert.Equal(t, slack.ResponseTypeEphemeral, message.ResponseType)
+	assert.Equal(t, "Only the owner of the blocker or PMs of the project can resolve it", message.Text)
+	assert.Len(t, open(), 2)
+
+	callback.User.ID = "PM"
+	message, err = bot.HandleBlockerAction(callback)
+	require.NoError(t, err)
+	assert.True(t, message.ReplaceOriginal)
+	assert.NotEmpty(t, message.Attachments[0].Actions)
+	assert.Empty(t, message.Attachments[1].Actions)
+	assert.Equal(t, "Resolved by <@PM>", message.Attachments[1].Footer)
+	// the original message is kept as it is
+	assert.NotEmpty(t, messages[0].Attachments[1].Actions)
+	assert.Equal(t, []string{"waiting for API keys"}, open())
+
+	blockers, err = bot.db.ListWorkspaceBlockers("testTeam", model.BlockerResolved)
+	require.NoError(t, err)
+	require.Len(t, blockers, 1)
+	assert.Equal(t, "PM", blockers[0].ResolvedBy)
+	assert.Equal(t, clk.Now().Unix(), blockers[0].ResolvedAt)
+
+	// blockers of other workspaces are not resolved
+	other, err := bot.db.CreateBlocker(model.Blocker{WorkspaceID: "otherTeam", ChannelID: "CHAN9", UserID: "U9", Text: "blocked", Status: model.BlockerOpen})
+	require.NoError(t, err)
+	callback.ActionCallback.AttachmentActions = []*slack.AttachmentAction{{Name: resolveBlockerName, Value: strconv.FormatInt(other.ID, 10)}}
+	_, err = bot.HandleBlockerAction(callback)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	message, err = bot.HandleBlockerAction(&slack.InteractionCallback{CallbackID: "standup"})
+	assert.NoError(t, err)
+	assert.Nil(t, message)
+
+	// other chats do not send button clicks, so there is no button
+	bot.workspace.Platform = model.PlatformTelegram
+	channel, err := bot.db.SelectProject("CHAN1")
+	require.NoError(t, err)
+	assert.Empty(t, bot.blockerAttachment(blockers[0], channel, clk.Now()).Actions)
+}
@@ -3,9 +3,10 @@ package botuser
 import (
 	"fmt"
 	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/maddevsio/comedian/clock"
 	"github.com/maddevsio/comedian/config"
 	"github.com/maddevsio/comedian/model"
 	"github.com/maddevsio/comedian/storage"
@@ -20,10 +21,6 @@ var (
 	typeDeleteMessage = "message_deleted"
 )
 
-var problemKeys = []string{"issue", "мешает"}
-var todayPlansKeys = []string{"today", "сегодня"}
-var yesterdayWorkKeys = []string{"yesterday", "friday", "вчера", "пятниц"}
-
 //Message represent any message that can be send to Slack or any other destination
 type Message struct {
 	Type        string
@@ -36,94 +33,140 @@ type Message struct {
 // Bot struct used for storing and communicating with slack api
 type Bot struct {
 	conf      *config.Config
-	db        *storage.DB
+	db        storage.Store
 	localizer *i18n.Localizer
 	workspace *model.Workspace
-	slack     *slack.Client
+	messenger Messenger
 	bundle    *i18n.Bundle
+	clock     clock.Clock
+	startedAt time.Time
+	leader    int32
+	users     userDirectory
 	quitChan  chan struct{}
+	// done is closed when the loop started by Start exits
+	done chan struct{}
 }
 
 //New creates new Bot instance
-func New(config *config.Config, bundle *i18n.Bundle, settings model.Workspace, db *storage.DB) *Bot {
+func New(config *config.Config, bundle *i18n.Bundle, settings model.Workspace, db storage.Store) *Bot {
 	bot := &Bot{
 		conf:      config,
 		db:        db,
-		slack:     slack.New(settings.BotAccessToken),
+		messenger: NewMessenger(settings),
 		workspace: &settings,
 		bundle:    bundle,
 		localizer: i18n.NewLocalizer(bundle, settings.Language),
+		clock:     clock.New(),
 	}
 	bot.quitChan = make(chan struct{})
 	return bot
 }
 
-//Start updates Users list and launches notifications
-func (bot *Bot) Start() {
-	var wg sync.WaitGroup
+//SetClock makes bot tell time by c
+func (bot *Bot) SetClock(c clock.Clock) {
+	bot.clock = c
+}
 
+//Start launches bot scheduler and outbox delivery
+func (bot *Bot) Start() {
 	log.Info("Bot started for ", bot.workspace.WorkspaceName)
+	bot.startedAt = bot.clock.Now()
+	bot.done = make(chan struct{})
 
-	wg.Add(1)
 	go func() {
-		ticker := time.NewTicker(time.Second * 60).C
+		defer close(bot.done)
+		ticker := time.NewTicker(schedulerTick)
+		defer ticker.Stop()
+		outbox := time.NewTicker(outboxTick)
+		defer outbox.Stop()
+		purge := time.NewTicker(outboxPurgeInterval)
+		defer purge.Stop()
 		for {
 			select {
-			case <-ticker:
-				err := bot.notifyChannels()
-				if err != nil {
-					log.Error("notifyChannels failed: ", err)
+			case <-ticker.C:
+				bot.tick()
+			case <-outbox.C:
+				if bot.Leader() {
+					bot.deliverOutbox(bot.clock.Now())
 				}
-				err = bot.CallDisplayYesterdayTeamReport()
-				if err != nil {
-					log.Error("CallDisplayYesterdayTeamReport failed: ", err)
+			case <-purge.C:
+				if bot.Leader() {
+					bot.purgeOutbox(bot.clock.Now())
 				}
-				err = bot.CallDisplayWeeklyTeamReport()
-				if err != nil {
-					log.Error("CallDisplayWeeklyTeamReport failed: ", err)
-				}
-				err = bot.remindAboutWorklogs()
+			case <-bot.quitChan:
+				err := bot.db.ReleaseLease(bot.schedulerLease(), bot.conf.ReplicaID)
 				if err != nil {
-					log.Error("remindAboutWorklogs failed: ", err)
+					log.Error("ReleaseLease failed: ", err)
 				}
-			case <-bot.quitChan:
-				wg.Done()
 				return
 			}
 		}
 	}()
 }
 
-func (bot *Bot) send(msg *Message) error {
-	if msg.Type == "message" {
-		err := bot.SendMessage(msg.Channel, msg.Text, msg.Attachments)
-		if err != nil {
-			return err
+// tick runs the scheduler if this replica is the leader of the workspace.
+// Leadership is a lease which is renewed every tick and is taken over by
+// another replica once the leader stops renewing it
+func (bot *Bot) tick() {
+	now := bot.clock.Now()
+
+	leader, err := bot.db.AcquireLease(bot.schedulerLease(), bot.conf.ReplicaID, now.Unix(), now.Unix()+bot.conf.LeaseTTL)
+	if err != nil {
+		log.Error("AcquireLease failed: ", err)
+		return
+	}
+	if leader != bot.Leader() {
+		log.WithFields(log.Fields{"workspace": bot.workspace.WorkspaceName, "replica": bot.conf.ReplicaID, "leader": leader}).Info("scheduler leadership changed")
+		var flag int32
+		if leader {
+			flag = 1
 		}
+		atomic.StoreInt32(&bot.leader, flag)
 	}
-	if msg.Type == "ephemeral" {
-		err := bot.SendEphemeralMessage(msg.Channel, msg.User, msg.Text)
-		if err != nil {
-			return err
-		}
+	if !leader {
+		return
 	}
-	if msg.Type == "direct" {
-		err := bot.SendUserMessage(msg.User, msg.Text)
-		if err != nil {
-			return err
-		}
+
+	err = bot.runScheduler(now)
+	if err != nil {
+		log.Error("runScheduler failed: ", err)
 	}
+}
 
-	return nil
+//Leader tells whether this replica runs the scheduler of the workspace
+func (bot *Bot) Leader() bool {
+	return atomic.LoadInt32(&bot.leader) == 1
+}
+
+//StartedAt returns time the bot was started at
+func (bot *Bot) StartedAt() time.Time {
+	return bot.startedAt
+}
+
+func (bot *Bot) schedulerLease() string {
+	return "scheduler:" + bot.workspace.WorkspaceID
 }
 
-//Stop closes bot quitChan making bot goroutine to exit
+//Stop closes bot quitChan making bot goroutine to exit and waits until it
+//finishes the current tick and releases the lease, so a bot started for the
+//same workspace afterwards never overlaps with it
 func (bot *Bot) Stop() {
 	close(bot.quitChan)
+	if bot.done != nil {
+		<-bot.done
+	}
 }
 
 //HandleMessage handles slack message event
 func (bot *Bot) HandleMessage(msg *slack.MessageEvent) error {
+	// standups are answered in direct messages without mentioning the bot
+	if msg.SubType == typeMessage && msg.BotID == "" && msg.User != bot.workspace.BotUserID && bot.directChannel(msg) {
+		err := bot.handleDirectMessage(msg)
+		if err != nil {
+			log.Error("DIRECT MESSAGE FAILED: ", err)
+		}
+		return err
+	}
 	if !strings.Contains(msg.Msg.Text, bot.workspace.BotUserID) {
 		return nil
 	}
@@ -153,7 +196,7 @@ func (bot *Bot) HandleMessage(msg *slack.MessageEvent) error {
 
 func (bot *Bot) handleNewMessage(msg *slack.MessageEvent) (string, error) {
 
-	problem := bot.analizeStandup(msg.Msg.Text)
+	problem := bot.analizeStandup(msg.Channel, msg.Msg.Text)
 	if problem != "" {
 		err := bot.send(&Message{
 			Type:    "ephemeral",
@@ -164,8 +207,8 @@ func (bot *Bot) handleNewMessage(msg *slack.MessageEvent) (string, error) {
 		return problem, err
 	}
 
-	_, err := bot.db.CreateStandup(model.Standup{
-		CreatedAt:   time.Now().Unix(),
+	standup, err := bot.db.CreateStandup(model.Standup{
+		CreatedAt:   bot.clock.Now().Unix(),
 		WorkspaceID: msg.Team,
 		ChannelID:   msg.Channel,
 		UserID:      msg.User,
@@ -175,13 +218,11 @@ func (bot *Bot) handleNewMessage(msg *slack.MessageEvent) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	item := slack.ItemRef{
-		Channel:   msg.Channel,
-		Timestamp: msg.Msg.Timestamp,
-		File:      "",
-		Comment:   "",
+	err = bot.saveAnswers(standup)
+	if err != nil {
+		log.Error("saveAnswers failed: ", err)
 	}
-	err = bot.slack.AddReaction("heavy_check_mark", item)
+	err = bot.messenger.AddReaction("heavy_check_mark", msg.Channel, msg.Msg.Timestamp)
 	if err != nil {
 		return "", err
 	}
@@ -189,7 +230,7 @@ func (bot *Bot) handleNewMessage(msg *slack.MessageEvent) (string, error) {
 }
 
 func (bot *Bot) handleEditMessage(msg *slack.MessageEvent) (string, error) {
-	problem := bot.analizeStandup(msg.SubMessage.Text)
+	problem := bot.analizeStandup(msg.Channel, msg.SubMessage.Text)
 	if problem != "" {
 		err := bot.send(&Message{
 			Type:    "ephemeral",
@@ -200,18 +241,22 @@ func (bot *Bot) handleEditMessage(msg *slack.MessageEvent) (string, error) {
 		return problem, err
 	}
 
-	standup, err := bot.db.SelectStandupByMessageTS(msg.SubMessage.Timestamp)
+	standup, err := bot.db.SelectStandupByMessageTS(msg.Team, msg.Channel, msg.SubMessage.Timestamp)
 	if err == nil {
 		standup.Comment = msg.SubMessage.Text
-		_, err := bot.db.UpdateStandup(standup)
+		standup, err := bot.db.UpdateStandup(standup)
 		if err != nil {
 			return "", err
 		}
+		err = bot.saveAnswers(standup)
+		if err != nil {
+			log.Error("saveAnswers failed: ", err)
+		}
 		return "standup updated", nil
 	}
 
 	standup, err = bot.db.CreateStandup(model.Standup{
-		CreatedAt:   time.Now().Unix(),
+		CreatedAt:   bot.clock.Now().Unix(),
 		WorkspaceID: msg.Team,
 		ChannelID:   msg.Channel,
 		UserID:      msg.SubMessage.User,
@@ -221,14 +266,12 @@ func (bot *Bot) handleEditMessage(msg *slack.MessageEvent) (string, error) {
 	if err != nil {
 		return "", err
 	}
-
-	item := slack.ItemRef{
-		Channel:   msg.Channel,
-		Timestamp: msg.SubMessage.Timestamp,
-		File:      "",
-		Comment:   "",
+	err = bot.saveAnswers(standup)
+	if err != nil {
+		log.Error("saveAnswers failed: ", err)
 	}
-	err = bot.slack.AddReaction("heavy_check_mark", item)
+
+	err = bot.messenger.AddReaction("heavy_check_mark", msg.Channel, msg.SubMessage.Timestamp)
 	if err != nil {
 		return "", err
 	}
@@ -237,7 +280,7 @@ func (bot *Bot) handleEditMessage(msg *slack.MessageEvent) (string, error) {
 }
 
 func (bot *Bot) handleDeleteMessage(msg *slack.MessageEvent) (string, error) {
-	standup, err := bot.db.SelectStandupByMessageTS(msg.DeletedTimestamp)
+	standup, err := bot.db.SelectStandupByMessageTS(msg.Team, msg.Channel, msg.DeletedTimestamp)
 	if err != nil {
 		return "", nil
 	}
@@ -250,124 +293,50 @@ func (bot *Bot) handleDeleteMessage(msg *slack.MessageEvent) (string, error) {
 	return "standup deleted", nil
 }
 
+// submittedStandupToday tells if the user has submitted standup in the
+// channel today in their time zone
 func (bot *Bot) submittedStandupToday(userID, channelID string) bool {
 	standup, err := bot.db.SelectLatestStandupByUser(userID, channelID)
 	if err != nil {
 		return false
 	}
 
-	userProfile, err := bot.slack.GetUserInfo(userID)
-	if err != nil {
-		log.Error(err)
-		return false
-	}
-
-	loc := time.FixedZone(userProfile.TZ, userProfile.TZOffset)
+	loc := bot.userLocation(userID, channelID)
 
-	if time.Unix(standup.CreatedAt, 0).Day() == time.Now().UTC().In(loc).Day() {
+	submitted := time.Unix(standup.CreatedAt, 0).In(loc)
+	now := bot.clock.Now().In(loc)
+	if submitted.YearDay() == now.YearDay() && submitted.Year() == now.Year() {
 		log.Info("not non reporter: ", userID)
 		return true
 	}
 	return false
 }
 
-func (bot *Bot) analizeStandup(message string) string {
-	errors := []string{}
-	message = strings.ToLower(message)
-
-	var mentionsYesterdayWork, mentionsTodayPlans, mentionsProblem bool
-
-	for _, work := range yesterdayWorkKeys {
-		if strings.Contains(message, work) {
-			mentionsYesterdayWork = true
-		}
-	}
-
-	for _, plan := range todayPlansKeys {
-		if strings.Contains(message, plan) {
-			mentionsTodayPlans = true
-		}
-	}
-
-	for _, problem := range problemKeys {
-		if strings.Contains(message, problem) {
-			mentionsProblem = true
-		}
-	}
-
-	if !mentionsYesterdayWork {
-		warnings, err := bot.localizer.Localize(&i18n.LocalizeConfig{
-			DefaultMessage: &i18n.Message{
-				ID:    "noYesterdayMention",
-				Other: "- no 'yesterday' keywords detected: {{.Keywords}}",
-			},
-			TemplateData: map[string]interface{}{
-				"Keywords": strings.Join(yesterdayWorkKeys, ", "),
-			},
-		})
-		if err != nil {
-			log.Error(err)
-		}
-		errors = append(errors, warnings)
-	}
-	if !mentionsTodayPlans {
-		warnings, err := bot.localizer.Localize(&i18n.LocalizeConfig{
-			DefaultMessage: &i18n.Message{
-				ID:    "noTodayMention",
-				Other: "- no 'today' keywords detected: {{.Keywords}}",
-			},
-			TemplateData: map[string]interface{}{
-				"Keywords": strings.Join(todayPlansKeys, ", "),
-			},
-		})
-		if err != nil {
-			log.Error(err)
-		}
-		errors = append(errors, warnings)
-	}
-	if !mentionsProblem {
-		warnings, err := bot.localizer.Localize(&i18n.LocalizeConfig{
-			DefaultMessage: &i18n.Message{
-				ID:    "noProblemsMention",
-				Other: "- no 'problems' keywords detected: {{.Keywords}}",
-			},
-			TemplateData: map[string]interface{}{
-				"Keywords": strings.Join(problemKeys, ", "),
-			},
-		})
-		if err != nil {
-			log.Error(err)
-		}
-		errors = append(errors, warnings)
-	}
-	return strings.Join(errors, ", ")
-}
-
 // SendMessage posts a message in a specified channel visible for everyone
 func (bot *Bot) SendMessage(channel, message string, attachments []slack.Attachment) error {
-	_, _, err := bot.slack.PostMessage(channel, slack.MsgOptionText(message,true), slack.MsgOptionAttachments(attachments...))
-	return err
+	return bot.send(&Message{Type: "message", Channel: channel, Text: message, Attachments: attachments})
 }
 
 // SendEphemeralMessage posts a message in a specified channel which is visible only for selected user
 func (bot *Bot) SendEphemeralMessage(channel, user, message string) error {
-	_, err := bot.slack.PostEphemeral(channel, user, slack.MsgOptionText(message, true))
-	return err
+	return bot.send(&Message{Type: "ephemeral", Channel: channel, User: user, Text: message})
 }
 
 // SendUserMessage Direct Message specific user
 func (bot *Bot) SendUserMessage(userID, message string) error {
-	_, _, channelID, err := bot.slack.OpenIMChannel(userID)
-	if err != nil {
-		return err
-	}
-	return bot.SendMessage(channelID, message, nil)
+	return bot.send(&Message{Type: "direct", User: userID, Text: message})
 }
 
 //HandleJoin handles comedian joining channel
 func (bot *Bot) HandleJoin(joinEvent *slack.MemberJoinedChannelEvent) (model.Project, error) {
 	newChannel := model.Project{}
 	newChannel, err := bot.db.SelectProject(joinEvent.Channel)
+	if err == nil && joinEvent.User == bot.workspace.BotUserID {
+		// bot is back to the channel it was removed from
+		newChannel.BotRemoved = false
+		newChannel.Inactive = newChannel.Archived
+		return newChannel, bot.db.SetProjectBotRemoved(newChannel.ChannelID, false)
+	}
 	if err == nil {
 		err := bot.SendUserMessage(joinEvent.User, newChannel.OnbordingMessage)
 		if err != nil {
@@ -376,12 +345,12 @@ func (bot *Bot) HandleJoin(joinEvent *slack.MemberJoinedChannelEvent) (model.Pro
 		return newChannel, nil
 	}
 
-	channel, err := bot.slack.GetConversationInfo(joinEvent.Channel, true)
+	channel, err := bot.messenger.GetConversationInfo(joinEvent.Channel)
 	if err != nil {
 		return newChannel, err
 	}
 	newChannel, err = bot.db.CreateProject(model.Project{
-		CreatedAt:        time.Now().Unix(),
+		CreatedAt:        bot.clock.Now().Unix(),
 		WorkspaceID:      joinEvent.Team,
 		ChannelName:      channel.Name,
 		ChannelID:        channel.ID,
@@ -413,6 +382,16 @@ func (bot *Bot) ImplementCommands(command slack.SlashCommand) string {
 		return bot.modifyTZ(command)
 	case "/submittion_days":
 		return bot.modifySubmittionDays(command)
+	case "/schedule":
+		return bot.modifySchedule(command)
+	case "/slots":
+		return bot.manageSlots(command)
+	case "/template":
+		return bot.manageTemplate(command)
+	case "/standup":
+		return bot.standupCommand(command)
+	case "/vacation":
+		return bot.manageAbsences(command)
 	case "/onbording_message":
 		return bot.modifyOnbordingMessage(command)
 	default:
@@ -430,27 +409,15 @@ func (bot *Bot) Settings() *model.Workspace {
 	return bot.workspace
 }
 
-//SetProperties updates bot settings
-func (bot *Bot) SetProperties(settings *model.Workspace) *model.Workspace {
-	bot.workspace = settings
-	bot.localizer = i18n.NewLocalizer(bot.bundle, settings.Language)
-	return bot.workspace
-}
-
 func (bot *Bot) remindAboutWorklogs() error {
-	if time.Now().AddDate(0, 0, 1).Day() != 1 {
-		return nil
-	}
-
-	if time.Now().Hour() != 10 || time.Now().Minute() != 0 {
-		return nil
-	}
-
-	users, err := bot.slack.GetUsers()
+	users, err := bot.listUsers()
 	if err != nil {
 		return err
 	}
 
+	now := bot.clock.Now().In(bot.workspaceLocation())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
 	for _, user := range users {
 		if user.TeamID != bot.workspace.WorkspaceID {
 			continue
@@ -466,7 +433,7 @@ func (bot *Bot) remindAboutWorklogs() error {
 			continue
 		}
 
-		_, _, err = bot.GetCollectorDataOnMember(standupers[0], time.Date(time.Now().Year(), time.Now().Month(), 1, 0, 0, 0, 0, time.Local), time.Now())
+		_, _, err = bot.GetCollectorDataOnMember(standupers[0], monthStart, now)
 		if err != nil {
 			log.Error(err)
 			continue
@@ -476,7 +443,7 @@ func (bot *Bot) remindAboutWorklogs() error {
 		var total int
 
 		for _, member := range standupers {
-			user, userInProject, err := bot.GetCollectorDataOnMember(member, time.Date(time.Now().Year(), time.Now().Month(), 1, 0, 0, 0, 0, time.Local), time.Now())
+			user, userInProject, err := bot.GetCollectorDataOnMember(member, monthStart, now)
 			if err != nil {
 				log.Error(err)
 				continue
@@ -7,7 +7,9 @@ import (
 	"github.com/maddevsio/comedian/model"
 	"github.com/maddevsio/comedian/storage"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/text/language"
 )
 
@@ -21,10 +23,7 @@ func setupBot() *Bot {
 		return nil
 	}
 
-	db, err := storage.New(config.DatabaseURL, "../migrations")
-	if err != nil {
-		return nil
-	}
+	db := storage.NewMemoryDB()
 
 	settings := model.Workspace{
 		WorkspaceID:    "testTeam",
@@ -53,9 +52,55 @@ func setupBot() *Bot {
 
 func TestAnalizeStandup(t *testing.T) {
 
-	errors := bot.analizeStandup("yesterday, today, issues")
+	errors := bot.analizeStandup("", "yesterday, today, issues")
 	assert.Equal(t, "", errors)
 
-	errors = bot.analizeStandup("wrong standup")
+	errors = bot.analizeStandup("", "wrong standup")
 	assert.Equal(t, "- no 'yesterday' keywords detected: yesterday, friday, вчера, пятниц, - no 'today' keywords detected: today, сегодня, - no 'problems' keywords detected: issue, мешает", errors)
 }
+
+func TestHandleNewMessage(t *testing.T) {
+	bot, messenger := newTestBot()
+
+	err := bot.HandleMessage(&slack.MessageEvent{Msg: slack.Msg{
+		Channel:   "CHAN1",
+		User:      "U1",
+		Text:      "<@BOT> yesterday fixed bugs, today write tests, no issues",
+		Timestamp: "1.1",
+	}})
+	require.NoError(t, err)
+
+	standup, err := bot.db.SelectStandupByMessageTS("testTeam", "CHAN1", "1.1")
+	require.NoError(t, err)
+	assert.Equal(t, "U1", standup.UserID)
+	assert.Equal(t, "testTeam", standup.WorkspaceID)
+	assert.Equal(t, []string{"heavy_check_mark:CHAN1:1.1"}, messenger.reactions)
+	assert.Empty(t, messenger.flush())
+
+	err = bot.HandleMessage(&slack.MessageEvent{Msg: slack.Msg{
+		Channel:   "CHAN1",
+		User:      "U1",
+		Text:      "<@BOT> yesterday fixed bugs",
+		Timestamp: "1.2",
+	}})
+	require.NoError(t, err)
+
+	_, err = bot.db.SelectStandupByMessageTS("testTeam", "CHAN1", "1.2")
+	assert.Error(t, err)
+
+	messages := messenger.flush()
+	require.Equal(t, 1, len(messages))
+	assert.Equal(t, "ephemeral", messages[0].Type)
+	assert.Equal(t, "U1", messages[0].User)
+	assert.Contains(t, messages[0].Text, "no 'today' keywords detected")
+
+	err = bot.HandleMessage(&slack.MessageEvent{Msg: slack.Msg{
+		Channel:   "CHAN1",
+		User:      "U1",
+		Text:      "not a standup",
+		Timestamp: "1.3",
+	}})
+	require.NoError(t, err)
+	assert.Empty(t, messenger.flush())
+	assert.Equal(t, 1, len(messenger.reactions))
+}
@@ -0,0 +1,51 @@
+package botuser
+
+import (
+	"database/sql"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// HandleChannelRename keeps channel name of the project and its standupers
+// in sync with the chat, Collector looks standupers up by it
+func (bot *Bot) HandleChannelRename(event *slack.ChannelRenameEvent) error {
+	_, err := bot.db.SelectProject(event.Channel.ID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return bot.db.RenameProject(event.Channel.ID, event.Channel.Name)
+}
+
+// HandleChannelArchive stops notifications in the archived channel, or
+// resumes them when the channel is unarchived and the bot is still in it
+func (bot *Bot) HandleChannelArchive(channelID string, archived bool) error {
+	return bot.db.SetProjectArchived(channelID, archived)
+}
+
+// HandleBotRemoved stops notifications in the channel the bot was removed
+// from. They are resumed when the bot joins the channel again
+func (bot *Bot) HandleBotRemoved(channelID string) error {
+	return bot.db.SetProjectBotRemoved(channelID, true)
+}
+
+// HandleMemberLeft removes the user who left the channel from its standup
+// team. The bot leaving the channel is the same as being removed from it
+func (bot *Bot) HandleMemberLeft(event *slack.MemberLeftChannelEvent) error {
+	if event.User == bot.workspace.BotUserID {
+		return bot.HandleBotRemoved(event.Channel)
+	}
+
+	standuper, err := bot.db.FindStansuperByUserID(event.User, event.Channel)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	log.Infof("%v left %v, removing them from the standup team", event.User, standuper.ChannelName)
+	return bot.db.DeleteStanduper(standuper.ID)
+}
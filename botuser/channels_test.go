@@ -0,0 +1,73 @@
+package botuser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInactiveChannel(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.workspace.ReportingTime = ""
+	bot.workspace.BotUserID = "BOT"
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "UTC",
+		SubmissionDays: "daily",
+	})
+	require.NoError(t, err)
+	_, err = bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "CHAN1", UserID: "U1"})
+	require.NoError(t, err)
+
+	alarm := func() bool {
+		jobs, err := bot.db.ListWorkspaceJobs("testTeam")
+		require.NoError(t, err)
+		for _, j := range jobs {
+			if j.Name == "alarm:CHAN1" {
+				return true
+			}
+		}
+		return false
+	}
+	tick := func(day int) {
+		require.NoError(t, bot.runScheduler(time.Date(2019, 11, day, 9, 0, 0, 0, time.UTC)))
+	}
+
+	tick(4)
+	assert.True(t, alarm())
+
+	require.NoError(t, bot.HandleChannelArchive("CHAN1", true))
+	tick(5)
+	assert.False(t, alarm(), "archived channel is not notified")
+
+	require.NoError(t, bot.HandleChannelArchive("CHAN1", false))
+	tick(6)
+	assert.True(t, alarm())
+
+	require.NoError(t, bot.HandleMemberLeft(&slack.MemberLeftChannelEvent{User: "BOT", Channel: "CHAN1"}))
+	tick(7)
+	assert.False(t, alarm(), "channel without the bot is not notified")
+
+	require.NoError(t, bot.HandleChannelArchive("CHAN1", true))
+	require.NoError(t, bot.HandleChannelArchive("CHAN1", false))
+	tick(7)
+	assert.False(t, alarm(), "unarchived channel without the bot is not notified")
+
+	// the bot comes back silently, the standup team is kept
+	_, err = bot.HandleJoin(&slack.MemberJoinedChannelEvent{User: "BOT", Channel: "CHAN1", Team: "testTeam"})
+	require.NoError(t, err)
+	tick(8)
+	assert.True(t, alarm())
+	assert.Empty(t, messenger.flush())
+	standupers, err := bot.db.ListProjectStandupers("CHAN1")
+	require.NoError(t, err)
+	assert.Len(t, standupers, 1)
+}
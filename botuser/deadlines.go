@@ -1,8 +1,6 @@
 package botuser
 
 import (
-	"time"
-
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/slack-go/slack"
 	"github.com/olebedev/when"
@@ -31,7 +29,7 @@ func (bot *Bot) modifyDeadline(command slack.SlashCommand) string {
 		log.Error(err)
 	}
 
-	r, err := w.Parse(command.Text, time.Now())
+	r, err := w.Parse(command.Text, bot.clock.Now())
 	if err != nil {
 		return wrongDeadlineFormat
 	}
@@ -0,0 +1,336 @@
+package botuser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+const (
+	// dmStandupTTL is how long bot waits for answers in direct messages,
+	// older standups are dropped
+	dmStandupTTL = 12 * time.Hour
+	skipWord     = "skip"
+	cancelWord   = "cancel"
+)
+
+// directChannel tells if the message is posted in direct messages with the
+// bot. Every chat tells them in its own way, so the messenger is asked
+func (bot *Bot) directChannel(msg *slack.MessageEvent) bool {
+	direct, err := bot.messenger.IsDirectChannel(msg.Channel)
+	if err != nil {
+		log.Error("IsDirectChannel failed: ", err)
+		return false
+	}
+	return direct
+}
+
+// startDMStandup asks the user standup questions of the channel in direct
+// messages. If the user is answering another standup, questions of the
+// channel are asked after it
+func (bot *Bot) startDMStandup(channel model.Project, userID string) error {
+	if bot.submittedStandupToday(userID, channel.ChannelID) || bot.absentToday(userID, bot.userLocation(userID, channel.ChannelID)) {
+		return nil
+	}
+
+	standups, err := bot.dmStandups(userID)
+	if err != nil {
+		return err
+	}
+	for _, d := range standups {
+		if d.ChannelID == channel.ChannelID {
+			return nil
+		}
+	}
+
+	d, err := bot.db.CreateDMStandup(bot.newDMStandup(channel, userID))
+	if err != nil {
+		return err
+	}
+	if len(standups) > 0 {
+		return nil
+	}
+	return bot.askDMQuestion(d)
+}
+
+// handleDirectMessage takes the message as answer to the current question
+// of the user standup. Message without a standup in progress starts one
+func (bot *Bot) handleDirectMessage(msg *slack.MessageEvent) error {
+	text := strings.TrimSpace(strings.Replace(msg.Msg.Text, "<@"+bot.workspace.BotUserID+">", "", -1))
+
+	standups, err := bot.dmStandups(msg.User)
+	if err != nil {
+		return err
+	}
+	if len(standups) == 0 {
+		return bot.beginDMStandup(msg.User)
+	}
+
+	d := standups[0]
+	switch {
+	case strings.EqualFold(text, cancelWord):
+		err = bot.db.DeleteDMStandup(d.ID)
+		if err != nil {
+			return err
+		}
+		err = bot.sendDM(d.UserID, &i18n.Message{
+			ID:    "dmStandupCancelled",
+			Other: "Standup is cancelled",
+		}, nil)
+		if err != nil {
+			return err
+		}
+		return bot.askNextDMStandup(standups[1:])
+	case d.ChannelID == "":
+		return bot.pickDMStandupProject(d, text)
+	case d.Position < len(d.Questions):
+		return bot.answerDMStandup(d, text, standups[1:])
+	default:
+		// saving failed last time
+		return bot.finishDMStandup(d, standups[1:])
+	}
+}
+
+// beginDMStandup starts standup the user asked for. User in several
+// projects picks one of them first
+func (bot *Bot) beginDMStandup(userID string) error {
+	channels := bot.userProjects(userID)
+	switch len(channels) {
+	case 0:
+		return bot.sendDM(userID, &i18n.Message{
+			ID:    "dmStandupNoProjects",
+			Other: "You do not submit standups in any channel",
+		}, nil)
+	case 1:
+		d, err := bot.db.CreateDMStandup(bot.newDMStandup(channels[0], userID))
+		if err != nil {
+			return err
+		}
+		return bot.askDMQuestion(d)
+	}
+
+	_, err := bot.db.CreateDMStandup(model.DMStandup{
+		CreatedAt:   bot.clock.Now().Unix(),
+		WorkspaceID: bot.workspace.WorkspaceID,
+		UserID:      userID,
+	})
+	if err != nil {
+		return err
+	}
+	return bot.askDMStandupProject(userID, channels)
+}
+
+func (bot *Bot) askDMStandupProject(userID string, channels []model.Project) error {
+	list := make([]string, 0, len(channels))
+	for i, channel := range channels {
+		list = append(list, fmt.Sprintf("%v. #%v", i+1, channel.ChannelName))
+	}
+	return bot.sendDM(userID, &i18n.Message{
+		ID:    "dmStandupPickProject",
+		Other: "Which project is your standup for? Reply with the number or the channel name:\n{{.Projects}}",
+	}, map[string]interface{}{"Projects": strings.Join(list, "\n")})
+}
+
+func (bot *Bot) pickDMStandupProject(d model.DMStandup, text string) error {
+	channels := bot.userProjects(d.UserID)
+	name := strings.TrimPrefix(text, "#")
+	n, err := strconv.Atoi(text)
+	for i, channel := range channels {
+		if (err == nil && n == i+1) || strings.EqualFold(name, channel.ChannelName) {
+			picked := bot.newDMStandup(channel, d.UserID)
+			d.ChannelID = picked.ChannelID
+			d.Questions = picked.Questions
+			saved, err := bot.db.UpdateDMStandup(d, "", d.Position)
+			if err != nil || !saved {
+				// not saved means another message has picked the project
+				return err
+			}
+			return bot.askDMQuestion(d)
+		}
+	}
+	return bot.askDMStandupProject(d.UserID, channels)
+}
+
+func (bot *Bot) answerDMStandup(d model.DMStandup, text string, queued []model.DMStandup) error {
+	question := d.Questions[d.Position]
+	if text == "" || strings.EqualFold(text, skipWord) {
+		if question.Required {
+			return bot.sendDM(d.UserID, &i18n.Message{
+				ID:    "dmStandupRequired",
+				Other: "This question is required, please answer it",
+			}, nil)
+		}
+	} else {
+		d.Answers = append(d.Answers, model.StandupAnswer{Position: d.Position, Question: question.Name, Answer: text})
+	}
+	d.Position++
+
+	saved, err := bot.db.UpdateDMStandup(d, d.ChannelID, d.Position-1)
+	if err != nil || !saved {
+		// not saved means another message has answered the question
+		return err
+	}
+	if d.Position < len(d.Questions) {
+		return bot.askDMQuestion(d)
+	}
+	return bot.finishDMStandup(d, queued)
+}
+
+// finishDMStandup posts the answers to the project channel on behalf of
+// the user and moves on to the next standup of the user
+func (bot *Bot) finishDMStandup(d model.DMStandup, queued []model.DMStandup) error {
+	channel, err := bot.db.SelectProject(d.ChannelID)
+	if err != nil || len(d.Answers) == 0 {
+		err = bot.db.DeleteDMStandup(d.ID)
+		if err != nil {
+			return err
+		}
+		err = bot.sendDM(d.UserID, &i18n.Message{
+			ID:    "dmStandupNotSaved",
+			Other: "There is nothing to post, standup is not saved",
+		}, nil)
+		if err != nil {
+			return err
+		}
+		return bot.askNextDMStandup(queued)
+	}
+
+	_, err = bot.saveStandup(channel.ChannelID, d.UserID, fmt.Sprintf("dm:%v", d.ID), d.Answers)
+	if err != nil {
+		log.Error("saveStandup failed: ", err)
+		return bot.sendDM(d.UserID, &i18n.Message{
+			ID:    "dmStandupNotPosted",
+			Other: "Could not post the standup to #{{.Channel}}, send me any message to try again",
+		}, map[string]interface{}{"Channel": channel.ChannelName})
+	}
+
+	// the standup is saved, it must not be saved again whatever happens next
+	err = bot.db.DeleteDMStandup(d.ID)
+	if err != nil {
+		return err
+	}
+	err = bot.postStandup(channel.ChannelID, d.UserID, d.Answers)
+	if err != nil {
+		log.Error("postStandup failed: ", err)
+	}
+
+	err = bot.sendDM(d.UserID, &i18n.Message{
+		ID:    "dmStandupPosted",
+		Other: "Thanks! Your standup is posted to #{{.Channel}}",
+	}, map[string]interface{}{"Channel": channel.ChannelName})
+	if err != nil {
+		return err
+	}
+	return bot.askNextDMStandup(queued)
+}
+
+func (bot *Bot) askNextDMStandup(queued []model.DMStandup) error {
+	if len(queued) == 0 {
+		return nil
+	}
+	return bot.askDMQuestion(queued[0])
+}
+
+// askDMQuestion sends the current question of the standup, the first one
+// goes with the name of the project
+func (bot *Bot) askDMQuestion(d model.DMStandup) error {
+	if d.Position == 0 {
+		channel, err := bot.db.SelectProject(d.ChannelID)
+		if err != nil {
+			return err
+		}
+		err = bot.sendDM(d.UserID, &i18n.Message{
+			ID:    "dmStandupIntro",
+			Other: "Time for your standup in #{{.Channel}}. Answer the questions one by one and I will post your standup to the channel. Reply cancel to stop",
+		}, map[string]interface{}{"Channel": channel.ChannelName})
+		if err != nil {
+			return err
+		}
+	}
+
+	question := d.Questions[d.Position]
+	if question.Required {
+		return bot.SendUserMessage(d.UserID, fmt.Sprintf("*%v*", question.Name))
+	}
+	return bot.sendDM(d.UserID, &i18n.Message{
+		ID:    "dmStandupOptionalQuestion",
+		Other: "*{{.Question}}* (optional, reply skip to leave it out)",
+	}, map[string]interface{}{"Question": question.Name})
+}
+
+// dmStandups returns standups the user is answering in direct messages,
+// the current one goes first. Abandoned standups are dropped
+func (bot *Bot) dmStandups(userID string) ([]model.DMStandup, error) {
+	items, err := bot.db.ListUserDMStandups(bot.workspace.WorkspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	expired := bot.clock.Now().Add(-dmStandupTTL).Unix()
+	standups := []model.DMStandup{}
+	for _, d := range items {
+		if d.CreatedAt < expired {
+			err = bot.db.DeleteDMStandup(d.ID)
+			if err != nil {
+				log.Error("DeleteDMStandup failed: ", err)
+			}
+			continue
+		}
+		standups = append(standups, d)
+	}
+	return standups, nil
+}
+
+// newDMStandup keeps questions of the channel template, so the standup is
+// finished with the questions the user started with
+func (bot *Bot) newDMStandup(channel model.Project, userID string) model.DMStandup {
+	template, _ := bot.standupTemplate(channel.ChannelID)
+	questions := model.StandupTemplate{}
+	for _, section := range template {
+		questions = append(questions, model.StandupSection{Name: section.Name, Required: section.Required})
+	}
+	return model.DMStandup{
+		CreatedAt:   bot.clock.Now().Unix(),
+		WorkspaceID: bot.workspace.WorkspaceID,
+		UserID:      userID,
+		ChannelID:   channel.ChannelID,
+		Questions:   questions,
+	}
+}
+
+// userProjects lists active channels of the workspace the user submits
+// standups in
+func (bot *Bot) userProjects(userID string) []model.Project {
+	standupers, err := bot.db.FindStansupersByUserID(userID)
+	if err != nil {
+		log.Error("FindStansupersByUserID failed: ", err)
+		return nil
+	}
+
+	channels := []model.Project{}
+	for _, standuper := range standupers {
+		if standuper.WorkspaceID != bot.workspace.WorkspaceID {
+			continue
+		}
+		channel, err := bot.db.SelectProject(standuper.ChannelID)
+		if err != nil || channel.Inactive {
+			continue
+		}
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+func (bot *Bot) sendDM(userID string, message *i18n.Message, data map[string]interface{}) error {
+	text, err := bot.localizer.Localize(&i18n.LocalizeConfig{DefaultMessage: message, TemplateData: data})
+	if err != nil {
+		log.Error(err)
+	}
+	return bot.SendUserMessage(userID, text)
+}
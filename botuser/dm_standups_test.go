@@ -0,0 +1,216 @@
+package botuser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/model"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDMStandups(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.workspace.ReportingTime = ""
+	clk := clock.NewMock(time.Date(2019, 11, 5, 0, 0, 0, 0, time.UTC))
+	bot.SetClock(clk)
+
+	general, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "UTC",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday",
+		StandupTemplate: model.StandupTemplate{
+			{Name: "Done", Keywords: []string{"done"}, Required: true},
+			{Name: "Doing", Keywords: []string{"doing"}, Required: true},
+			{Name: "Blocked", Keywords: []string{"blocked"}},
+		},
+	})
+	require.NoError(t, err)
+	backend, err := bot.db.CreateProject(model.Project{WorkspaceID: "testTeam", ChannelID: "CHAN2", ChannelName: "backend", Deadline: "12:00", TZ: "UTC", SubmissionDays: "tuesday"})
+	require.NoError(t, err)
+	for _, s := range []model.Standuper{
+		{ChannelID: "CHAN1", UserID: "U1", DMStandupOffset: 30},
+		{ChannelID: "CHAN2", UserID: "U1"},
+		{ChannelID: "CHAN1", UserID: "U2"},
+	} {
+		messenger.users[s.UserID] = User{ID: s.UserID, TZ: "UTC"}
+		s.WorkspaceID = "testTeam"
+		_, err := bot.db.CreateStanduper(s)
+		require.NoError(t, err)
+	}
+
+	dm := func(userID, text string) []string {
+		require.NoError(t, bot.HandleMessage(&slack.MessageEvent{Msg: slack.Msg{
+			Channel:   "D" + userID,
+			User:      userID,
+			Text:      text,
+			Timestamp: text,
+		}}))
+		texts := []string{}
+		for _, m := range messenger.flush() {
+			texts = append(texts, m.Channel+" "+m.Text)
+		}
+		return texts
+	}
+
+	for end := time.Date(2019, 11, 5, 9, 30, 0, 0, time.UTC); !clk.Now().After(end); clk.Add(time.Minute) {
+		require.NoError(t, bot.runScheduler(clk.Now()))
+		if clk.Now().Before(end) {
+			require.Empty(t, messenger.flush(), clk.Now())
+		}
+	}
+	texts := []string{}
+	for _, m := range messenger.flush() {
+		texts = append(texts, m.Channel+" "+m.Text)
+	}
+	assert.Equal(t, []string{
+		"DU1 Time for your standup in #general. Answer the questions one by one and I will post your standup to the channel. Reply cancel to stop",
+		"DU1 *Done*",
+	}, texts)
+
+	assert.Equal(t, []string{"DU1 *Doing*"}, dm("U1", "fixed bugs"))
+	// the other project is asked after this one
+	require.NoError(t, bot.startDMStandup(backend, "U1"))
+	assert.Empty(t, messenger.flush())
+	assert.Equal(t, []string{"DU1 This question is required, please answer it"}, dm("U1", "skip"))
+	assert.Equal(t, []string{"DU1 *Blocked* (optional, reply skip to leave it out)"}, dm("U1", "<@BOT> writing tests"))
+
+	// failed post is retried by the outbox, messages after it wait for it
+	bot.conf.OutboxMaxAttempts = 3
+	messenger.failures = []error{slack.ErrParametersMissing}
+	assert.Empty(t, dm("U1", "Skip"))
+	clk.Add(10 * time.Second)
+	bot.deliverOutbox(clk.Now())
+	texts = []string{}
+	for _, m := range messenger.flush() {
+		texts = append(texts, m.Channel+" "+m.Text)
+	}
+	assert.Equal(t, []string{
+		"CHAN1 <@U1> posted a standup:\n*Done*\nfixed bugs\n\n*Doing*\nwriting tests",
+		"DU1 Thanks! Your standup is posted to #general",
+		"DU1 Time for your standup in #backend. Answer the questions one by one and I will post your standup to the channel. Reply cancel to stop",
+		"DU1 *yesterday*",
+	}, texts)
+
+	standup, err := bot.db.SelectLatestStandupByUser("U1", "CHAN1")
+	require.NoError(t, err)
+	assert.Equal(t, "*Done*\nfixed bugs\n\n*Doing*\nwriting tests", standup.Comment)
+	answers, err := bot.db.ListStandupAnswers(standup.ID)
+	require.NoError(t, err)
+	require.Len(t, answers, 2)
+	assert.Equal(t, "writing tests", answers[1].Answer)
+
+	// the standup is submitted, so it is not asked again
+	require.NoError(t, bot.startDMStandup(general, "U1"))
+	assert.Equal(t, []string{"DU1 Standup is cancelled"}, dm("U1", "cancel"))
+
+	// standup the user asks for starts with the project
+	pick := "DU1 Which project is your standup for? Reply with the number or the channel name:\n1. #general\n2. #backend"
+	assert.Equal(t, []string{pick}, dm("U1", "hi"))
+	assert.Equal(t, []string{pick}, dm("U1", "3"))
+	assert.Equal(t, []string{
+		"DU1 Time for your standup in #backend. Answer the questions one by one and I will post your standup to the channel. Reply cancel to stop",
+		"DU1 *yesterday*",
+	}, dm("U1", "#Backend"))
+	dm("U1", "fixed bugs")
+	dm("U1", "tests")
+	assert.Equal(t, []string{
+		"CHAN2 <@U1> posted a standup:\n*yesterday*\nfixed bugs\n\n*today*\ntests\n\n*problems*\nnone",
+		"DU1 Thanks! Your standup is posted to #backend",
+	}, dm("U1", "none"))
+
+	assert.Equal(t, []string{
+		"DU2 Time for your standup in #general. Answer the questions one by one and I will post your standup to the channel. Reply cancel to stop",
+		"DU2 *Done*",
+	}, dm("U2", "standup"))
+	// two messages handled at once answer the question once
+	standups, err := bot.dmStandups("U2")
+	require.NoError(t, err)
+	require.Len(t, standups, 1)
+	require.NoError(t, bot.answerDMStandup(standups[0], "fixed bugs", nil))
+	require.NoError(t, bot.answerDMStandup(standups[0], "fixed more bugs", nil))
+	assert.Len(t, messenger.flush(), 1)
+	standups, err = bot.dmStandups("U2")
+	require.NoError(t, err)
+	require.Len(t, standups, 1)
+	assert.Equal(t, 1, standups[0].Position)
+	assert.Equal(t, model.StandupAnswers{{Position: 0, Question: "Done", Answer: "fixed bugs"}}, standups[0].Answers)
+
+	// abandoned standups are dropped
+	clk.Add(dmStandupTTL + time.Minute)
+	assert.Equal(t, []string{"DU3 You do not submit standups in any channel"}, dm("U3", "standup"))
+	standups, err = bot.dmStandups("U2")
+	require.NoError(t, err)
+	assert.Empty(t, standups)
+
+	// messages of the bot itself are not answers
+	require.NoError(t, bot.HandleMessage(&slack.MessageEvent{Msg: slack.Msg{Channel: "DU1", User: "BOT", Text: "*Done*"}}))
+	assert.Empty(t, messenger.flush())
+}
+
+func TestDMStandupJobs(t *testing.T) {
+	bot, _ := newTestBot()
+	bot.workspace.ReportingTime = ""
+	now := time.Date(2019, 11, 4, 0, 0, 0, 0, time.UTC)
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "UTC",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday",
+	})
+	require.NoError(t, err)
+	for _, slot := range []model.DeadlineSlot{
+		{Deadline: "12:00", Days: "monday"},
+		{Deadline: "17:00", Name: "check-in"},
+	} {
+		slot.WorkspaceID = "testTeam"
+		slot.ChannelID = "CHAN1"
+		_, err := bot.db.CreateDeadlineSlot(slot)
+		require.NoError(t, err)
+	}
+	standupers := map[string]model.Standuper{}
+	for _, s := range []model.Standuper{
+		{UserID: "U1", DMStandupOffset: 60},
+		{UserID: "U2", DMStandupOffset: 30, Deadline: "09:00"},
+		{UserID: "U3"},
+	} {
+		s.WorkspaceID = "testTeam"
+		s.ChannelID = "CHAN1"
+		s, err = bot.db.CreateStanduper(s)
+		require.NoError(t, err)
+		standupers[s.UserID] = s
+	}
+
+	dmJobs := func() map[string]time.Time {
+		require.NoError(t, bot.runScheduler(now))
+		jobs, err := bot.db.ListWorkspaceJobs("testTeam")
+		require.NoError(t, err)
+		next := map[string]time.Time{}
+		for _, job := range jobs {
+			if job.Kind == model.JobDMStandup {
+				next[job.UserID] = time.Unix(job.NextRunAt, 0).UTC()
+			}
+		}
+		return next
+	}
+
+	// U1 is asked before the earliest slot, U2 before their own deadline
+	assert.Equal(t, map[string]time.Time{
+		"U1": time.Date(2019, 11, 4, 11, 0, 0, 0, time.UTC),
+		"U2": time.Date(2019, 11, 4, 8, 30, 0, 0, time.UTC),
+	}, dmJobs())
+
+	u1 := standupers["U1"]
+	u1.DMStandupOffset = 0
+	_, err = bot.db.UpdateStanduper(u1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{"U2": time.Date(2019, 11, 4, 8, 30, 0, 0, time.UTC)}, dmJobs())
+}
@@ -0,0 +1,38 @@
+package botuser
+
+import (
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// submissionDay tells if standups are expected in the channel on the day
+// of t: it is one of submission days and not a holiday
+func (bot *Bot) submissionDay(channel model.Project, t time.Time) bool {
+	return shouldSubmitStandupIn(&channel, t) && !bot.holiday(channel.ChannelID, t)
+}
+
+// holiday tells if the day of t is in a holiday calendar of the channel
+// or of the whole workspace
+func (bot *Bot) holiday(channelID string, t time.Time) bool {
+	day := t.Format(model.DateLayout)
+	holidays, err := bot.db.ListChannelHolidays(bot.workspace.WorkspaceID, channelID, day, day)
+	if err != nil {
+		log.Error("ListChannelHolidays failed: ", err)
+		return false
+	}
+	return len(holidays) > 0
+}
+
+// holidayAt tells if deadline at t falls on a holiday in the time zone of
+// the channel, or of the standuper with individual schedule
+func (bot *Bot) holidayAt(channel model.Project, userID string, t time.Time) bool {
+	if userID != "" {
+		standuper, err := bot.db.FindStansuperByUserID(userID, channel.ChannelID)
+		if err == nil {
+			channel = standuperSchedule(channel, standuper)
+		}
+	}
+	return bot.holiday(channel.ChannelID, t.In(location(channel.TZ)))
+}
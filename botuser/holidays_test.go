@@ -0,0 +1,83 @@
+package botuser
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHolidays runs the bot from Tuesday to Thursday. Wednesday becomes
+// a holiday when notifications for it are already scheduled
+func TestHolidays(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.workspace.ReportingTime = ""
+	bot.workspace.MaxReminders = 0
+	clk := clock.NewMock(time.Date(2019, 11, 5, 0, 0, 0, 0, time.UTC))
+	bot.SetClock(clk)
+
+	for _, id := range []string{"CHAN1", "CHAN2"} {
+		_, err := bot.db.CreateProject(model.Project{
+			WorkspaceID:    "testTeam",
+			ChannelID:      id,
+			ChannelName:    id,
+			Deadline:       "10:00",
+			TZ:             "UTC",
+			SubmissionDays: "monday, tuesday, wednesday, thursday, friday",
+		})
+		require.NoError(t, err)
+		messenger.users["U1"] = User{ID: "U1", TZ: "UTC"}
+		_, err = bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: id, UserID: "U1"})
+		require.NoError(t, err)
+	}
+
+	national, err := bot.db.CreateHolidayCalendar(model.HolidayCalendar{WorkspaceID: "testTeam", Name: "Kyrgyzstan"})
+	require.NoError(t, err)
+	office, err := bot.db.CreateHolidayCalendar(model.HolidayCalendar{WorkspaceID: "testTeam", ChannelID: "CHAN2", Name: "Office"})
+	require.NoError(t, err)
+	_, err = bot.db.CreateHoliday(model.Holiday{CalendarID: office.ID, Date: "2019-11-07", Name: "Moving"})
+	require.NoError(t, err)
+
+	var sent []string
+	for end := time.Date(2019, 11, 8, 0, 0, 0, 0, time.UTC); clk.Now().Before(end); clk.Add(time.Minute) {
+		if clk.Now().Equal(time.Date(2019, 11, 5, 12, 0, 0, 0, time.UTC)) {
+			_, err = bot.db.CreateHoliday(model.Holiday{CalendarID: national.ID, Date: "2019-11-06", Name: "Day off"})
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, bot.runScheduler(clk.Now()))
+
+		var texts []string
+		for _, m := range messenger.flush() {
+			texts = append(texts, fmt.Sprintf("%v %v %v", clk.Now().Format("Mon 15:04"), m.Channel, m.Text))
+		}
+		sort.Strings(texts)
+		sent = append(sent, texts...)
+	}
+
+	assert.Equal(t, []string{
+		"Tue 09:50 CHAN1 <@U1>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Tue 09:50 CHAN2 <@U1>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Tue 10:00 CHAN1 <@U1>, you are the only one missed standup, shame!",
+		"Tue 10:00 CHAN2 <@U1>, you are the only one missed standup, shame!",
+		// Wednesday is a holiday everywhere, Thursday only in CHAN2
+		"Thu 09:50 CHAN1 <@U1>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Thu 10:00 CHAN1 <@U1>, you are the only one missed standup, shame!",
+	}, sent)
+
+	// missed standup on holiday does not cost points in report
+	members, err := bot.db.ListProjectStandupers("CHAN1")
+	require.NoError(t, err)
+	clk.Set(time.Date(2019, 11, 7, 10, 0, 0, 0, time.Local))
+	text, points := bot.processStandup(members[0])
+	assert.Equal(t, "", text)
+	assert.Equal(t, 1, points)
+	clk.Set(time.Date(2019, 11, 8, 10, 0, 0, 0, time.Local))
+	_, points = bot.processStandup(members[0])
+	assert.Equal(t, 0, points)
+}
@@ -0,0 +1,285 @@
+package botuser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+const (
+	// mattermostUsersPerPage is the maximum page size Mattermost API allows
+	mattermostUsersPerPage = 200
+	// mattermostDirectChannel is the type of direct messages channels
+	mattermostDirectChannel = "D"
+)
+
+// MattermostUser is a user as Mattermost REST API returns it
+type MattermostUser struct {
+	ID        string            `json:"id"`
+	Username  string            `json:"username"`
+	FirstName string            `json:"first_name"`
+	LastName  string            `json:"last_name"`
+	DeleteAt  int64             `json:"delete_at"`
+	IsBot     bool              `json:"is_bot"`
+	Timezone  map[string]string `json:"timezone"`
+}
+
+// MattermostTeam is a team as Mattermost REST API returns it
+type MattermostTeam struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+type mattermostChannel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type mattermostPost struct {
+	ID        string                 `json:"id,omitempty"`
+	ChannelID string                 `json:"channel_id"`
+	Message   string                 `json:"message"`
+	Props     map[string]interface{} `json:"props,omitempty"`
+}
+
+type mattermostError struct {
+	ID         string `json:"id"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
+// MattermostClient is a minimal client of Mattermost REST API v4
+type MattermostClient struct {
+	serverURL string
+	token     string
+	http      *http.Client
+}
+
+// NewMattermostClient creates client for Mattermost server authenticated with bot access token
+func NewMattermostClient(serverURL, token string) *MattermostClient {
+	return &MattermostClient{
+		serverURL: strings.TrimRight(serverURL, "/"),
+		token:     token,
+		http:      &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GetMe returns the user token belongs to
+func (c *MattermostClient) GetMe() (*MattermostUser, error) {
+	user := &MattermostUser{}
+	err := c.do(http.MethodGet, "/users/me", nil, user)
+	return user, err
+}
+
+// GetTeam returns team by its id
+func (c *MattermostClient) GetTeam(teamID string) (*MattermostTeam, error) {
+	team := &MattermostTeam{}
+	err := c.do(http.MethodGet, "/teams/"+teamID, nil, team)
+	return team, err
+}
+
+func (c *MattermostClient) do(method, path string, body, result interface{}) error {
+	var payload bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&payload).Encode(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.serverURL+"/api/v4"+path, &payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		reset, _ := strconv.Atoi(resp.Header.Get("X-Ratelimit-Reset"))
+		return &slack.RateLimitedError{RetryAfter: time.Duration(reset) * time.Second}
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := mattermostError{}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) != nil || apiErr.Message == "" {
+			return fmt.Errorf("mattermost: %v %v: %v", method, path, resp.Status)
+		}
+		return fmt.Errorf("mattermost: %v %v: %v", method, path, apiErr.Message)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+type mattermostMessenger struct {
+	client    *MattermostClient
+	teamID    string
+	botUserID string
+
+	mu sync.Mutex
+	// direct remembers type of channels, which never changes
+	direct map[string]bool
+}
+
+// NewMattermostMessenger creates Messenger which talks to Mattermost server
+// on behalf of the bot. Mattermost has no message timestamps, so post ids
+// are used wherever Slack expects them
+func NewMattermostMessenger(serverURL, botAccessToken, teamID, botUserID string) Messenger {
+	return &mattermostMessenger{
+		client:    NewMattermostClient(serverURL, botAccessToken),
+		teamID:    teamID,
+		botUserID: botUserID,
+		direct:    map[string]bool{},
+	}
+}
+
+func (m *mattermostMessenger) PostMessage(channelID, text string, attachments []slack.Attachment) (string, error) {
+	post := mattermostPost{
+		ChannelID: channelID,
+		Message:   text,
+	}
+	if len(attachments) > 0 {
+		// Mattermost renders Slack compatible attachments passed in props
+		post.Props = map[string]interface{}{"attachments": attachments}
+	}
+
+	created := mattermostPost{}
+	err := m.client.do(http.MethodPost, "/posts", post, &created)
+	return created.ID, err
+}
+
+func (m *mattermostMessenger) PostEphemeral(channelID, userID, text string) error {
+	return m.client.do(http.MethodPost, "/posts/ephemeral", map[string]interface{}{
+		"user_id": userID,
+		"post": mattermostPost{
+			ChannelID: channelID,
+			Message:   text,
+		},
+	}, nil)
+}
+
+func (m *mattermostMessenger) OpenIMChannel(userID string) (string, error) {
+	channel := mattermostChannel{}
+	err := m.client.do(http.MethodPost, "/channels/direct", []string{m.botUserID, userID}, &channel)
+	return channel.ID, err
+}
+
+// IsDirectChannel asks Mattermost for the channel type, as direct channel
+// IDs look like any other
+func (m *mattermostMessenger) IsDirectChannel(channelID string) (bool, error) {
+	m.mu.Lock()
+	direct, ok := m.direct[channelID]
+	m.mu.Unlock()
+	if ok {
+		return direct, nil
+	}
+
+	ch := mattermostChannel{}
+	err := m.client.do(http.MethodGet, "/channels/"+channelID, nil, &ch)
+	if err != nil {
+		return false, err
+	}
+	direct = ch.Type == mattermostDirectChannel
+
+	m.mu.Lock()
+	m.direct[channelID] = direct
+	m.mu.Unlock()
+	return direct, nil
+}
+
+func (m *mattermostMessenger) AddReaction(name, channelID, timestamp string) error {
+	return m.client.do(http.MethodPost, "/reactions", map[string]string{
+		"user_id":    m.botUserID,
+		"post_id":    timestamp,
+		"emoji_name": name,
+	}, nil)
+}
+
+func (m *mattermostMessenger) GetUserInfo(userID string) (*User, error) {
+	u := MattermostUser{}
+	err := m.client.do(http.MethodGet, "/users/"+userID, nil, &u)
+	if err != nil {
+		return nil, err
+	}
+	user := m.user(u)
+	return &user, nil
+}
+
+func (m *mattermostMessenger) GetConversationInfo(channelID string) (*Channel, error) {
+	ch := mattermostChannel{}
+	err := m.client.do(http.MethodGet, "/channels/"+channelID, nil, &ch)
+	if err != nil {
+		return nil, err
+	}
+	return &Channel{ID: ch.ID, Name: ch.Name}, nil
+}
+
+func (m *mattermostMessenger) GetUsers() ([]User, error) {
+	users := []User{}
+	for page := 0; ; page++ {
+		query := url.Values{}
+		query.Set("in_team", m.teamID)
+		query.Set("page", fmt.Sprint(page))
+		query.Set("per_page", fmt.Sprint(mattermostUsersPerPage))
+
+		batch := []MattermostUser{}
+		err := m.client.do(http.MethodGet, "/users?"+query.Encode(), nil, &batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range batch {
+			users = append(users, m.user(u))
+		}
+		if len(batch) < mattermostUsersPerPage {
+			return users, nil
+		}
+	}
+}
+
+func (m *mattermostMessenger) OpenView(triggerID string, view slack.ModalViewRequest) error {
+	return errNoModals
+}
+
+func (m *mattermostMessenger) user(u MattermostUser) User {
+	realName := strings.TrimSpace(u.FirstName + " " + u.LastName)
+	if realName == "" {
+		realName = u.Username
+	}
+
+	tz := u.Timezone["manualTimezone"]
+	if u.Timezone["useAutomaticTimezone"] == "true" {
+		tz = u.Timezone["automaticTimezone"]
+	}
+	var offset int
+	if loc, err := time.LoadLocation(tz); err == nil && tz != "" {
+		_, offset = time.Now().In(loc).Zone()
+	}
+
+	return User{
+		ID:       u.ID,
+		TeamID:   m.teamID,
+		Name:     u.Username,
+		RealName: realName,
+		TZ:       tz,
+		TZOffset: offset,
+		IsBot:    u.IsBot,
+		Deleted:  u.DeleteAt != 0,
+	}
+}
@@ -0,0 +1,203 @@
+package botuser
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+type mattermostRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Auth   string
+	Body   string
+}
+
+// mockMattermost is a local stand-in for Mattermost REST API
+type mockMattermost struct {
+	mu       sync.Mutex
+	requests []mattermostRequest
+	server   *httptest.Server
+}
+
+func newMockMattermost() *mockMattermost {
+	m := &mockMattermost{}
+	m.server = httptest.NewServer(http.HandlerFunc(m.handle))
+	return m
+}
+
+func (m *mockMattermost) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	m.mu.Lock()
+	m.requests = append(m.requests, mattermostRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Auth:   r.Header.Get("Authorization"),
+		Body:   string(body),
+	})
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v4/posts":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"post1"}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v4/channels/direct":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"dm1"}`))
+	case r.Method == http.MethodPost:
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	case r.URL.Path == "/api/v4/users/U1":
+		w.Write([]byte(`{"id":"U1","username":"john","first_name":"John","last_name":"Doe","timezone":{"useAutomaticTimezone":"false","manualTimezone":"UTC"}}`))
+	case r.URL.Path == "/api/v4/users":
+		w.Write([]byte(`[{"id":"U1","username":"john"},{"id":"U2","username":"gone","delete_at":1},{"id":"BOT","username":"comedian","is_bot":true}]`))
+	case r.URL.Path == "/api/v4/channels/C1":
+		w.Write([]byte(`{"id":"C1","name":"general","display_name":"General","type":"O"}`))
+	case r.URL.Path == "/api/v4/channels/dm1":
+		w.Write([]byte(`{"id":"dm1","name":"BOT__U1","type":"D"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"id":"api.context.404.app_error","message":"Sorry, we could not find the page.","status_code":404}`))
+	}
+}
+
+func (m *mockMattermost) flush() []mattermostRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	requests := m.requests
+	m.requests = nil
+	return requests
+}
+
+func TestMattermostMessenger(t *testing.T) {
+	mm := newMockMattermost()
+	defer mm.server.Close()
+
+	messenger := NewMattermostMessenger(mm.server.URL+"/", "token", "team1", "BOT")
+
+	id, err := messenger.PostMessage("C1", "hello", []slack.Attachment{{Text: "report"}})
+	require.NoError(t, err)
+	assert.Equal(t, "post1", id)
+
+	err = messenger.PostEphemeral("C1", "U1", "psst")
+	require.NoError(t, err)
+
+	dm, err := messenger.OpenIMChannel("U1")
+	require.NoError(t, err)
+	assert.Equal(t, "dm1", dm)
+
+	err = messenger.AddReaction("heavy_check_mark", "C1", "post1")
+	require.NoError(t, err)
+
+	requests := mm.flush()
+	require.Len(t, requests, 4)
+	for _, r := range requests {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer token", r.Auth)
+	}
+
+	assert.Equal(t, "/api/v4/posts", requests[0].Path)
+	post := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(requests[0].Body), &post))
+	assert.Equal(t, "C1", post["channel_id"])
+	assert.Equal(t, "hello", post["message"])
+	assert.Contains(t, requests[0].Body, `"attachments":[{`)
+
+	assert.Equal(t, "/api/v4/posts/ephemeral", requests[1].Path)
+	assert.Contains(t, requests[1].Body, `"user_id":"U1"`)
+
+	assert.Equal(t, "/api/v4/channels/direct", requests[2].Path)
+	assert.JSONEq(t, `["BOT","U1"]`, requests[2].Body)
+
+	assert.Equal(t, "/api/v4/reactions", requests[3].Path)
+	assert.JSONEq(t, `{"user_id":"BOT","post_id":"post1","emoji_name":"heavy_check_mark"}`, requests[3].Body)
+
+	user, err := messenger.GetUserInfo("U1")
+	require.NoError(t, err)
+	assert.Equal(t, User{ID: "U1", TeamID: "team1", Name: "john", RealName: "John Doe", TZ: "UTC"}, *user)
+
+	channel, err := messenger.GetConversationInfo("C1")
+	require.NoError(t, err)
+	assert.Equal(t, Channel{ID: "C1", Name: "general"}, *channel)
+
+	// Mattermost channel type is asked once
+	mm.flush()
+	for i := 0; i < 2; i++ {
+		direct, err := messenger.IsDirectChannel("dm1")
+		require.NoError(t, err)
+		assert.True(t, direct)
+		direct, err = messenger.IsDirectChannel("C1")
+		require.NoError(t, err)
+		assert.False(t, direct)
+	}
+	assert.Len(t, mm.flush(), 2)
+
+	users, err := messenger.GetUsers()
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	assert.Equal(t, "john", users[0].RealName)
+	assert.True(t, users[1].Deleted)
+	assert.True(t, users[2].IsBot)
+
+	requests = mm.flush()
+	assert.Equal(t, "in_team=team1&page=0&per_page=200", requests[len(requests)-1].Query)
+
+	_, err = messenger.GetUserInfo("U404")
+	assert.EqualError(t, err, "mattermost: GET /users/U404: Sorry, we could not find the page.")
+}
+
+func TestMattermostBot(t *testing.T) {
+	mm := newMockMattermost()
+	defer mm.server.Close()
+
+	settings := model.Workspace{
+		WorkspaceID:    "team1",
+		WorkspaceName:  "team1",
+		BotUserID:      "BOT",
+		BotAccessToken: "token",
+		Language:       "en",
+		ReminderOffset: 10,
+		MaxReminders:   3,
+		ReportingTime:  "10am",
+		Platform:       model.PlatformMattermost,
+		ServerURL:      mm.server.URL,
+	}
+
+	bot := New(&config.Config{}, i18n.NewBundle(language.English), settings, storage.NewMemoryDB())
+
+	err := bot.HandleMessage(&slack.MessageEvent{
+		Msg: slack.Msg{
+			Channel:   "C1",
+			User:      "U1",
+			Text:      "<@BOT> yesterday fixed bugs, today write tests, no issues",
+			Timestamp: "post42",
+		},
+	})
+	require.NoError(t, err)
+
+	requests := mm.flush()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/api/v4/channels/C1", requests[0].Path)
+	assert.Equal(t, "/api/v4/reactions", requests[1].Path)
+	assert.True(t, strings.Contains(requests[1].Body, `"post_id":"post42"`))
+
+	standup, err := bot.db.SelectStandupByMessageTS("team1", "C1", "post42")
+	require.NoError(t, err)
+	assert.Equal(t, "U1", standup.UserID)
+}
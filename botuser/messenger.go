@@ -0,0 +1,155 @@
+package botuser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/slack-go/slack"
+)
+
+// Messenger is a chat platform Comedian talks to. Every call bot makes
+// to the chat goes through it
+type Messenger interface {
+	// PostMessage posts a message visible for everyone in the channel and returns its timestamp
+	PostMessage(channelID, text string, attachments []slack.Attachment) (string, error)
+	// PostEphemeral posts a message in the channel visible only for the user
+	PostEphemeral(channelID, userID, text string) error
+	// OpenIMChannel opens direct messages channel with the user and returns its ID
+	OpenIMChannel(userID string) (string, error)
+	// IsDirectChannel tells if the channel is direct messages with the bot
+	IsDirectChannel(channelID string) (bool, error)
+	// AddReaction adds emoji reaction to the message
+	AddReaction(name, channelID, timestamp string) error
+	GetUserInfo(userID string) (*User, error)
+	GetConversationInfo(channelID string) (*Channel, error)
+	GetUsers() ([]User, error)
+	// OpenView opens a modal for the user who triggered the interaction
+	OpenView(triggerID string, view slack.ModalViewRequest) error
+}
+
+// errNoModals is returned by chats which can not open modals
+var errNoModals = errors.New("the chat does not support modals")
+
+// User is a chat platform user
+type User struct {
+	ID       string
+	TeamID   string
+	Name     string
+	RealName string
+	TZ       string
+	TZOffset int
+	IsBot    bool
+	Deleted  bool
+	// StatusEmoji and StatusExpiration are the chat status of the user,
+	// zero expiration means the status does not expire
+	StatusEmoji      string
+	StatusExpiration int64
+}
+
+// Channel is a chat platform channel (conversation)
+type Channel struct {
+	ID   string
+	Name string
+}
+
+// NewMessenger creates Messenger for the chat platform workspace belongs to
+func NewMessenger(settings model.Workspace) Messenger {
+	switch settings.Platform {
+	case model.PlatformMattermost:
+		return NewMattermostMessenger(settings.ServerURL, settings.BotAccessToken, settings.WorkspaceID, settings.BotUserID)
+	case model.PlatformTelegram:
+		return NewTelegramMessenger(settings.ServerURL, settings.BotAccessToken)
+	default:
+		return NewSlackMessenger(settings.BotAccessToken)
+	}
+}
+
+type slackMessenger struct {
+	client *slack.Client
+}
+
+// NewSlackMessenger creates Messenger which talks to Slack on behalf of the bot
+func NewSlackMessenger(botAccessToken string) Messenger {
+	return &slackMessenger{
+		client: slack.New(botAccessToken),
+	}
+}
+
+func (s *slackMessenger) PostMessage(channelID, text string, attachments []slack.Attachment) (string, error) {
+	_, ts, err := s.client.PostMessage(channelID, slack.MsgOptionText(text, true), slack.MsgOptionAttachments(attachments...))
+	return ts, err
+}
+
+func (s *slackMessenger) PostEphemeral(channelID, userID, text string) error {
+	_, err := s.client.PostEphemeral(channelID, userID, slack.MsgOptionText(text, true))
+	return err
+}
+
+func (s *slackMessenger) OpenIMChannel(userID string) (string, error) {
+	_, _, channelID, err := s.client.OpenIMChannel(userID)
+	return channelID, err
+}
+
+// IsDirectChannel tells direct messages by the ID, which starts with D in
+// Slack
+func (s *slackMessenger) IsDirectChannel(channelID string) (bool, error) {
+	return strings.HasPrefix(channelID, "D"), nil
+}
+
+func (s *slackMessenger) AddReaction(name, channelID, timestamp string) error {
+	return s.client.AddReaction(name, slack.ItemRef{
+		Channel:   channelID,
+		Timestamp: timestamp,
+	})
+}
+
+func (s *slackMessenger) GetUserInfo(userID string) (*User, error) {
+	u, err := s.client.GetUserInfo(userID)
+	if err != nil {
+		return nil, err
+	}
+	user := slackUser(*u)
+	return &user, nil
+}
+
+func (s *slackMessenger) GetConversationInfo(channelID string) (*Channel, error) {
+	ch, err := s.client.GetConversationInfo(channelID, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Channel{ID: ch.ID, Name: ch.Name}, nil
+}
+
+func (s *slackMessenger) GetUsers() ([]User, error) {
+	slackUsers, err := s.client.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]User, 0, len(slackUsers))
+	for _, u := range slackUsers {
+		users = append(users, slackUser(u))
+	}
+	return users, nil
+}
+
+func (s *slackMessenger) OpenView(triggerID string, view slack.ModalViewRequest) error {
+	_, err := s.client.OpenView(triggerID, view)
+	return err
+}
+
+func slackUser(u slack.User) User {
+	return User{
+		ID:       u.ID,
+		TeamID:   u.TeamID,
+		Name:     u.Name,
+		RealName: u.RealName,
+		TZ:       u.TZ,
+		TZOffset: u.TZOffset,
+		IsBot:    u.IsBot,
+		Deleted:  u.Deleted,
+
+		StatusEmoji:      u.Profile.StatusEmoji,
+		StatusExpiration: int64(u.Profile.StatusExpiration),
+	}
+}
@@ -0,0 +1,167 @@
+package botuser
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/maddevsio/comedian/config"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/slack-go/slack"
+	"golang.org/x/text/language"
+)
+
+// recordingMessenger is a fake chat which records everything bot sends
+type recordingMessenger struct {
+	mu        sync.Mutex
+	users     map[string]User
+	channels  map[string]Channel
+	messages  []Message
+	reactions []string
+	views     []slack.ModalViewRequest
+	// failures are returned by the next PostMessage calls, one per call
+	failures []error
+	// onPost is called before a message is posted, e.g. to run another
+	// replica in the middle of a pass
+	onPost func()
+	// onGetUsers is called before users are listed
+	onGetUsers func()
+	// userInfoCalls and usersCalls count requests of user profiles
+	userInfoCalls int
+	usersCalls    int
+}
+
+func newRecordingMessenger() *recordingMessenger {
+	return &recordingMessenger{
+		users:    map[string]User{},
+		channels: map[string]Channel{},
+	}
+}
+
+func (r *recordingMessenger) PostMessage(channelID, text string, attachments []slack.Attachment) (string, error) {
+	if r.onPost != nil {
+		r.onPost()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.failures) > 0 {
+		err := r.failures[0]
+		r.failures = r.failures[1:]
+		if err != nil {
+			return "", err
+		}
+	}
+	r.messages = append(r.messages, Message{
+		Type:        "message",
+		Channel:     channelID,
+		Text:        text,
+		Attachments: attachments,
+	})
+	return fmt.Sprintf("%d.000100", len(r.messages)), nil
+}
+
+func (r *recordingMessenger) PostEphemeral(channelID, userID, text string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, Message{
+		Type:    "ephemeral",
+		Channel: channelID,
+		User:    userID,
+		Text:    text,
+	})
+	return nil
+}
+
+func (r *recordingMessenger) OpenIMChannel(userID string) (string, error) {
+	return "D" + userID, nil
+}
+
+func (r *recordingMessenger) IsDirectChannel(channelID string) (bool, error) {
+	return strings.HasPrefix(channelID, "D"), nil
+}
+
+func (r *recordingMessenger) AddReaction(name, channelID, timestamp string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reactions = append(r.reactions, fmt.Sprintf("%s:%s:%s", name, channelID, timestamp))
+	return nil
+}
+
+func (r *recordingMessenger) GetUserInfo(userID string) (*User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.userInfoCalls++
+	u, ok := r.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("user_not_found")
+	}
+	return &u, nil
+}
+
+func (r *recordingMessenger) GetConversationInfo(channelID string) (*Channel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ch, ok := r.channels[channelID]
+	if !ok {
+		return nil, fmt.Errorf("channel_not_found")
+	}
+	return &ch, nil
+}
+
+func (r *recordingMessenger) GetUsers() ([]User, error) {
+	if r.onGetUsers != nil {
+		r.onGetUsers()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usersCalls++
+	users := []User{}
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func (r *recordingMessenger) OpenView(triggerID string, view slack.ModalViewRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if triggerID == "" {
+		return fmt.Errorf("invalid_trigger_id")
+	}
+	r.views = append(r.views, view)
+	return nil
+}
+
+// flush returns recorded messages and forgets them
+func (r *recordingMessenger) flush() []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	messages := r.messages
+	r.messages = nil
+	return messages
+}
+
+// newTestBot creates bot which works with in-memory storage and recording messenger
+func newTestBot(messages ...*i18n.Message) (*Bot, *recordingMessenger) {
+	bundle := i18n.NewBundle(language.English)
+	bundle.AddMessages(language.English, messages...)
+
+	settings := model.Workspace{
+		WorkspaceID:      "testTeam",
+		WorkspaceName:    "testTeam",
+		BotUserID:        "BOT",
+		BotAccessToken:   "foo",
+		Language:         "en",
+		ReminderOffset:   10,
+		MaxReminders:     3,
+		ReportingChannel: "reports",
+		ReportingTime:    "10am",
+	}
+
+	messenger := newRecordingMessenger()
+	bot := New(&config.Config{NotificationTime: 1, SchedulerGraceWindow: 15}, bundle, settings, storage.NewMemoryDB())
+	bot.messenger = messenger
+	return bot, messenger
+}
@@ -1,189 +1,161 @@
 package botuser
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/maddevsio/comedian/model"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
-	"github.com/olebedev/when"
-	"github.com/olebedev/when/rules/en"
-	"github.com/olebedev/when/rules/ru"
 	log "github.com/sirupsen/logrus"
 )
 
-func (bot *Bot) notifyChannels() error {
-	channels, err := bot.listTeamActiveChannels()
+// warn tags channel standupers who have not submitted standup yet
+// ReminderOffset minutes before the deadline. Given userID it warns only
+// the standuper with individual schedule, given slotID it warns about the
+// deadline slot of the channel
+func (bot *Bot) warn(channel model.Project, userID string, slotID int64, deadline time.Time) error {
+	nonReporters, err := bot.findNonReporters(channel, userID, bot.slotWindowStart(channel, slotID, deadline))
 	if err != nil {
-		return err
+		return fmt.Errorf("could not get non reporters: %v", err)
 	}
 
-	if len(channels) == 0 {
-		return nil
+	message, err := bot.composeWarnMessage(nonReporters)
+	if err != nil {
+		return fmt.Errorf("could not compose Warn Message: %v", err)
 	}
 
-	for _, channel := range channels {
-		err := bot.notify(channel)
-		if err != nil {
-			log.Error(err)
-		}
+	if message == "" {
+		return nil
 	}
 
-	return nil
+	return bot.send(&Message{
+		Type:    "message",
+		Channel: channel.ChannelID,
+		Text:    message,
+	})
 }
 
-func (bot *Bot) notify(channel model.Project) error {
-	if !shouldSubmitStandupIn(&channel, time.Now()) {
-		return nil
-	}
-
-	loc, err := time.LoadLocation(channel.TZ)
+// alarm tags channel standupers who missed the deadline and opens
+// notification thread to remind them later. It returns time of the first
+// reminder or zero time if there is nobody to remind
+func (bot *Bot) alarm(channel model.Project, userID string, slotID int64, deadline time.Time) (time.Time, error) {
+	nonReporters, err := bot.findNonReporters(channel, userID, bot.slotWindowStart(channel, slotID, deadline))
 	if err != nil {
-		return err
+		return time.Time{}, fmt.Errorf("could not get non reporters: %v", err)
 	}
 
-	w := when.New(nil)
-	w.Add(en.All...)
-	w.Add(ru.All...)
-
-	//the error is ommited here since to get to this stage the channel
-	//needs to have proper standup time
-	r, _ := w.Parse(channel.Deadline, time.Now())
-
-	alarmtime := time.Unix(r.Time.Unix(), 0)
-	warningTime := time.Unix(r.Time.Unix()-bot.workspace.ReminderOffset*60, 0)
-
-	var message string
-
-	switch {
-	case time.Now().In(loc).Hour() == warningTime.Hour() && time.Now().In(loc).Minute() == warningTime.Minute():
-		nonReporters, err := bot.findChannelNonReporters(channel)
-		if err != nil {
-			return fmt.Errorf("could not get non reporters: %v", err)
-		}
-
-		message, err = bot.composeWarnMessage(nonReporters)
+	// thread left from previous days is of no use anymore
+	thread, err := bot.selectNotificationThread(channel.ChannelID, userID, slotID)
+	if err == nil {
+		err = bot.db.DeleteNotificationThread(thread.ID)
 		if err != nil {
-			return fmt.Errorf("could not compose Warn Message: %v", err)
+			return time.Time{}, err
 		}
+	}
 
-	case time.Now().In(loc).Hour() == alarmtime.Hour() && time.Now().In(loc).Minute() == alarmtime.Minute():
-		threadTime := time.Now().Unix() + bot.conf.NotificationTime*60
+	var remindAt time.Time
 
-		nonReporters, err := bot.findChannelNonReporters(channel)
+	if len(nonReporters) > 0 && bot.workspace.MaxReminders > 0 {
+		remindAt = deadline.Add(time.Duration(bot.conf.NotificationTime) * time.Minute)
 
+		_, err = bot.db.CreateNotificationThread(model.NotificationThread{
+			ChannelID:        channel.ChannelID,
+			UserIDs:          strings.Join(nonReporters, ","),
+			NotificationTime: remindAt.Unix(),
+			ReminderCounter:  0,
+			UserID:           userID,
+			SlotID:           slotID,
+		})
 		if err != nil {
-			return fmt.Errorf("could not get non reporters: %v", err)
+			log.Error("Error on executing CreateNotificationThread ", err, "ChannelID: ", channel.ChannelID)
+			return time.Time{}, err
 		}
+	}
 
-		if len(nonReporters) > 0 {
-			usersNonReport := strings.Join(nonReporters, ",")
-
-			_, err = bot.db.CreateNotificationThread(model.NotificationThread{
-				ChannelID:        channel.ChannelID,
-				UserIDs:          usersNonReport,
-				NotificationTime: threadTime,
-				ReminderCounter:  0,
-			})
-			if err != nil {
-				log.Error("Error on executing CreateNotificationThread ", err, "ChannelID: ", channel.ChannelID)
-				return err
-			}
-		}
-		message, err = bot.composeAlarmMessage(nonReporters)
-		if err != nil {
-			return fmt.Errorf("could not compose Alarm Message: %v", err)
-		}
+	message, err := bot.composeAlarmMessage(nonReporters)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not compose Alarm Message: %v", err)
+	}
+
+	if message == "" {
+		return remindAt, nil
 	}
 
-	bot.send(&Message{
+	return remindAt, bot.send(&Message{
 		Type:    "message",
 		Channel: channel.ChannelID,
 		Text:    message,
 	})
+}
 
-	thread, err := bot.db.SelectNotificationsThread(channel.ChannelID)
-	if err != nil && err.Error() != "sql: no rows in result set" {
-		log.Error("Error on executing SelectNotificationsThread! ", err, "ChannelID: ", channel.ChannelID, "ChannelName: ", channel.ChannelName)
-		return err
+// remind tags standupers from notification thread who still have not
+// submitted standup. It returns time of the next reminder or zero time
+// when reminding is over
+func (bot *Bot) remind(channel model.Project, userID string, slotID int64, scheduled time.Time) (time.Time, error) {
+	thread, err := bot.selectNotificationThread(channel.ChannelID, userID, slotID)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
 	}
-
-	var remindTime time.Time
-
-	remindTime = time.Unix(thread.NotificationTime, 0)
-
-	if time.Now().In(loc).Hour() != remindTime.Hour() || time.Now().In(loc).Minute() != remindTime.Minute() {
-		return nil
+	if err != nil {
+		log.Error("Error on executing SelectNotificationsThread! ", err, "ChannelID: ", channel.ChannelID, "ChannelName: ", channel.ChannelName)
+		return time.Time{}, err
 	}
 
-	if thread.ReminderCounter >= bot.workspace.MaxReminders {
-		err = bot.db.DeleteNotificationThread(thread.ID)
-		if err != nil {
-			log.Error("Error on executing DeleteNotificationsThread! ", err, "Thread ID: ", thread.ID)
-			return err
+	since := bot.slotWindowStart(channel, slotID, scheduled)
+	stillNonReporters := []string{}
+	for _, nonReporter := range strings.Split(thread.UserIDs, ",") {
+		if nonReporter != "" && !bot.submittedStandup(nonReporter, thread.ChannelID, since) && !bot.absentToday(nonReporter, bot.userLocation(nonReporter, channel.ChannelID)) {
+			stillNonReporters = append(stillNonReporters, nonReporter)
 		}
 	}
 
-	stillNonReporters := strings.Split(thread.UserIDs, ",")
-
-	var updatedNonReporters string
-
-	for i, nonReport := range stillNonReporters {
-		if bot.submittedStandupToday(nonReport, thread.ChannelID) {
-			stillNonReporters = append(stillNonReporters[:i], stillNonReporters[i+1:]...)
-		}
+	if len(stillNonReporters) == 0 || thread.ReminderCounter >= bot.workspace.MaxReminders {
+		return time.Time{}, bot.db.DeleteNotificationThread(thread.ID)
 	}
-	updatedNonReporters = strings.Join(stillNonReporters, ",")
 
-	if len(updatedNonReporters) == 0 {
-		err = bot.db.DeleteNotificationThread(thread.ID)
-		if err != nil {
-			log.Error("Error on executing DeleteNotificationsThread! ", err, "Thread ID: ", thread.ID)
-			return err
-		}
-	}
+	updatedNonReporters := strings.Join(stillNonReporters, ",")
 
-	message, err = bot.composeRemindMessage(stillNonReporters)
+	message, err := bot.composeRemindMessage(stillNonReporters)
 	if err != nil {
-		return fmt.Errorf("could not compose Remind Message: %v", err)
-	}
-
-	if message == "" {
-		return nil
+		return time.Time{}, fmt.Errorf("could not compose Remind Message: %v", err)
 	}
 
-	bot.send(&Message{
+	err = bot.send(&Message{
 		Type:    "message",
 		Channel: channel.ChannelID,
 		Text:    message,
 	})
+	if err != nil {
+		return time.Time{}, err
+	}
 
-	thread.NotificationTime = thread.NotificationTime + bot.conf.NotificationTime*60
+	if thread.ReminderCounter+1 >= bot.workspace.MaxReminders {
+		return time.Time{}, bot.db.DeleteNotificationThread(thread.ID)
+	}
 
-	return bot.db.UpdateNotificationThread(thread.ID, thread.NotificationTime, updatedNonReporters)
-}
+	next := scheduled.Add(time.Duration(bot.conf.NotificationTime) * time.Minute)
 
-func (bot *Bot) listTeamActiveChannels() ([]model.Project, error) {
-	var channels []model.Project
+	return next, bot.db.UpdateNotificationThread(thread.ID, next.Unix(), updatedNonReporters)
+}
 
-	chs, err := bot.db.ListWorkspaceProjects(bot.workspace.WorkspaceID)
-	if err != nil {
-		return channels, err
+// findNonReporters returns channel standupers who have not submitted
+// standup since the given time (today when it is zero) and are not away,
+// or only the given standuper with individual schedule
+func (bot *Bot) findNonReporters(project model.Project, userID string, since time.Time) ([]string, error) {
+	if userID == "" {
+		return bot.findChannelNonReporters(project, since)
 	}
-
-	for _, channel := range chs {
-		if channel.Deadline == "" {
-			continue
-		}
-
-		channels = append(channels, channel)
+	if bot.submittedStandup(userID, project.ChannelID, since) || bot.absentToday(userID, bot.userLocation(userID, project.ChannelID)) {
+		return []string{}, nil
 	}
-
-	return channels, nil
+	return []string{userID}, nil
 }
 
-func (bot *Bot) findChannelNonReporters(project model.Project) ([]string, error) {
+// findChannelNonReporters returns standupers who follow the channel
+// schedule, are not away and have not submitted standup
+func (bot *Bot) findChannelNonReporters(project model.Project, since time.Time) ([]string, error) {
 	nonReporters := []string{}
 
 	standupers, err := bot.db.ListProjectStandupers(project.ChannelID)
@@ -191,7 +163,10 @@ func (bot *Bot) findChannelNonReporters(project model.Project) ([]string, error)
 		return nonReporters, err
 	}
 	for _, standuper := range standupers {
-		if !bot.submittedStandupToday(standuper.UserID, standuper.ChannelID) {
+		if standuper.HasSchedule() {
+			continue
+		}
+		if !bot.submittedStandup(standuper.UserID, standuper.ChannelID, since) && !bot.absentToday(standuper.UserID, standuperLocation(project, standuper)) {
 			nonReporters = append(nonReporters, standuper.UserID)
 		}
 	}
@@ -299,10 +274,12 @@ func (bot *Bot) composeRemindMessage(nonReporters []string) (string, error) {
 	return remindNonReporters, nil
 }
 
+// shouldSubmitStandupIn tells if t falls on one of submission days of the channel
 func shouldSubmitStandupIn(channel *model.Project, t time.Time) bool {
-	// TODO need to think of how to include translated versions
-	if strings.Contains(channel.SubmissionDays, strings.ToLower(t.Weekday().String())) {
-		return true
+	days, err := model.ParseWeekdays(channel.SubmissionDays)
+	if err != nil {
+		log.Errorf("could not recognize submission days of %v: %v", channel.ChannelName, err)
+		return false
 	}
-	return false
+	return days.Has(t.Weekday())
 }
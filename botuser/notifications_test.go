@@ -6,13 +6,14 @@ import (
 
 	"github.com/maddevsio/comedian/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFindChannelNonReporters(t *testing.T) {
 	t.Skip("Need to fix test and only then run")
 	nonReportes, err := bot.findChannelNonReporters(model.Project{
 		ChannelID: "CHAN123",
-	})
+	}, time.Time{})
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(nonReportes))
 
@@ -27,7 +28,7 @@ func TestFindChannelNonReporters(t *testing.T) {
 
 	nonReportes, err = bot.findChannelNonReporters(model.Project{
 		ChannelID: "CHAN123",
-	})
+	}, time.Time{})
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(nonReportes))
 	assert.Equal(t, "<@"+standuper.UserID+">", nonReportes[0])
@@ -43,10 +44,92 @@ func TestFindChannelNonReporters(t *testing.T) {
 
 	nonReportes, err = bot.findChannelNonReporters(model.Project{
 		ChannelID: "CHAN123",
-	})
+	}, time.Time{})
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(nonReportes))
 
 	assert.NoError(t, bot.db.DeleteStanduper(standuper.ID))
 	assert.NoError(t, bot.db.DeleteStandup(standup.ID))
 }
+
+func TestAlarmAndRemind(t *testing.T) {
+	bot, messenger := newTestBot()
+	messenger.users["U1"] = User{ID: "U1", TZ: "UTC"}
+
+	channel, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "UTC",
+		SubmissionDays: "monday",
+	})
+	require.NoError(t, err)
+
+	_, err = bot.db.CreateStanduper(model.Standuper{
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN1",
+		UserID:      "U1",
+	})
+	require.NoError(t, err)
+
+	deadline := time.Date(2019, 11, 4, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, bot.warn(channel, "", 0, deadline))
+	messages := messenger.flush()
+	require.Equal(t, 1, len(messages))
+	assert.Equal(t, "<@U1>, you are the only one to miss standup, in 10 minutes, hurry up!", messages[0].Text)
+
+	remindAt, err := bot.alarm(channel, "", 0, deadline)
+	require.NoError(t, err)
+	assert.Equal(t, deadline.Add(time.Minute), remindAt)
+
+	messages = messenger.flush()
+	require.Equal(t, 1, len(messages))
+	assert.Equal(t, "CHAN1", messages[0].Channel)
+	assert.Equal(t, "<@U1>, you are the only one missed standup, shame!", messages[0].Text)
+
+	thread, err := bot.db.SelectNotificationsThread("CHAN1")
+	require.NoError(t, err)
+	assert.Equal(t, "U1", thread.UserIDs)
+	assert.Equal(t, remindAt.Unix(), thread.NotificationTime)
+
+	// workspace allows 3 reminders
+	for i := 0; i < 3; i++ {
+		next, err := bot.remind(channel, "", 0, remindAt)
+		require.NoError(t, err)
+		messages = messenger.flush()
+		require.Equal(t, 1, len(messages))
+		assert.Equal(t, "<@U1>, you still haven't written a standup! Write a standup!", messages[0].Text)
+		if i < 2 {
+			assert.Equal(t, remindAt.Add(time.Minute), next)
+		} else {
+			assert.True(t, next.IsZero())
+		}
+		remindAt = next
+	}
+
+	_, err = bot.db.SelectNotificationsThread("CHAN1")
+	assert.Error(t, err)
+
+	// standupers who submitted standup are not reminded
+	_, err = bot.alarm(channel, "", 0, deadline)
+	require.NoError(t, err)
+	messenger.flush()
+
+	_, err = bot.db.CreateStandup(model.Standup{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN1",
+		UserID:      "U1",
+		MessageTS:   "1",
+	})
+	require.NoError(t, err)
+
+	next, err := bot.remind(channel, "", 0, deadline.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, next.IsZero())
+	assert.Empty(t, messenger.flush())
+
+	require.NoError(t, bot.warn(channel, "", 0, deadline))
+	assert.Empty(t, messenger.flush())
+}
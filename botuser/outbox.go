@@ -0,0 +1,207 @@
+package botuser
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+const (
+	// outboxTick is how often the leader looks for messages to retry
+	outboxTick = time.Second
+	// outboxClaimTime is how long a sender holds a message. Messages of a
+	// crashed sender are sent again after it
+	outboxClaimTime = time.Minute
+	// outboxRetryDelay is the delay before the first retry, it doubles with every attempt
+	outboxRetryDelay = 10 * time.Second
+	outboxBatch      = 50
+	// outboxPurgeInterval is how often the leader deletes sent messages
+	outboxPurgeInterval = time.Hour
+)
+
+// send saves the message to the outbox and delivers it at once unless the
+// workspace is rate limited or older messages are still waiting, so a new
+// message never overtakes them. Messages which could not be delivered now
+// are retried by deliverOutbox, so send fails only if the message is not
+// saved
+func (bot *Bot) send(msg *Message) error {
+	var attachments string
+	if len(msg.Attachments) > 0 {
+		data, err := json.Marshal(msg.Attachments)
+		if err != nil {
+			return err
+		}
+		attachments = string(data)
+	}
+
+	now := bot.clock.Now()
+	o, err := bot.db.CreateOutboxMessage(model.OutboxMessage{
+		WorkspaceID:   bot.workspace.WorkspaceID,
+		Type:          msg.Type,
+		ChannelID:     msg.Channel,
+		UserID:        msg.User,
+		Text:          msg.Text,
+		Attachments:   attachments,
+		Status:        model.OutboxPending,
+		CreatedAt:     now.Unix(),
+		NextAttemptAt: now.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	waiting, err := bot.db.HasUnsentOutboxMessages(o.WorkspaceID, o.ID)
+	if err != nil {
+		log.Error("HasUnsentOutboxMessages failed: ", err)
+		return nil
+	}
+	if !waiting && bot.allowPost(now) {
+		bot.deliver(o.ID)
+	}
+	return nil
+}
+
+// deliverOutbox sends messages which are due as fast as rate limit allows
+func (bot *Bot) deliverOutbox(now time.Time) {
+	messages, err := bot.db.ListDueOutboxMessages(bot.workspace.WorkspaceID, now.Unix(), outboxBatch)
+	if err != nil {
+		log.Error("ListDueOutboxMessages failed: ", err)
+		return
+	}
+
+	for _, o := range messages {
+		if !bot.allowPost(now) {
+			return
+		}
+		bot.deliver(o.ID)
+	}
+}
+
+// deliver posts the message unless another sender holds it. Rate limited
+// messages wait for Retry-After without losing an attempt, other failures
+// are retried with growing delay until they run out of attempts
+func (bot *Bot) deliver(id int64) {
+	now := bot.clock.Now()
+	claimed, err := bot.db.ClaimOutboxMessage(id, now.Unix(), now.Add(outboxClaimTime).Unix())
+	if err != nil {
+		log.Error("ClaimOutboxMessage failed: ", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	o, err := bot.db.GetOutboxMessage(id)
+	if err != nil {
+		log.Error("GetOutboxMessage failed: ", err)
+		return
+	}
+
+	err = bot.post(&o)
+
+	switch rateLimited, ok := err.(*slack.RateLimitedError); {
+	case err == nil:
+		o.Status = model.OutboxSent
+		o.Error = ""
+		o.SentAt = now.Unix()
+		o.NextAttemptAt = 0
+	case ok:
+		until := now.Add(rateLimited.RetryAfter)
+		pauseErr := bot.db.PauseOutbox(o.WorkspaceID, milliseconds(until))
+		if pauseErr != nil {
+			log.Error("PauseOutbox failed: ", pauseErr)
+		}
+		o.Status = model.OutboxPending
+		o.Attempts--
+		o.Error = err.Error()
+		o.NextAttemptAt = until.Unix()
+		log.WithFields(log.Fields{"workspace": o.WorkspaceID, "retry_after": rateLimited.RetryAfter}).Warning("chat rate limited the bot")
+	case o.Attempts >= bot.maxOutboxAttempts():
+		o.Status = model.OutboxFailed
+		o.Error = err.Error()
+		o.NextAttemptAt = 0
+		log.WithFields(log.Fields{"message": o.ID, "workspace": o.WorkspaceID, "error": err}).Error("message is not delivered")
+	default:
+		o.Status = model.OutboxPending
+		o.Error = err.Error()
+		o.NextAttemptAt = now.Add(outboxRetryDelay << uint(o.Attempts-1)).Unix()
+		log.WithFields(log.Fields{"message": o.ID, "attempts": o.Attempts, "error": err}).Warning("message delivery failed, will retry")
+	}
+
+	_, err = bot.db.UpdateOutboxMessage(o)
+	if err != nil {
+		log.Error("UpdateOutboxMessage failed: ", err)
+	}
+}
+
+// allowPost takes a token from the rate limit of the workspace. The limit
+// is kept in the database, so it holds for all replicas together
+func (bot *Bot) allowPost(now time.Time) bool {
+	var interval int64
+	if bot.conf.OutboxRate > 0 {
+		interval = int64(float64(time.Second/time.Millisecond) / bot.conf.OutboxRate)
+	}
+
+	allowed, err := bot.db.TakeOutboxToken(bot.workspace.WorkspaceID, milliseconds(now), interval, bot.conf.OutboxBurst)
+	if err != nil {
+		log.Error("TakeOutboxToken failed: ", err)
+		return false
+	}
+	return allowed
+}
+
+// purgeOutbox deletes messages sent more than OUTBOX_RETENTION days ago.
+// Failed messages are kept for inspection
+func (bot *Bot) purgeOutbox(now time.Time) {
+	if bot.conf.OutboxRetention <= 0 {
+		return
+	}
+
+	before := now.Add(-time.Duration(bot.conf.OutboxRetention) * 24 * time.Hour).Unix()
+	err := bot.db.DeleteSentOutboxMessages(bot.workspace.WorkspaceID, before)
+	if err != nil {
+		log.Error("DeleteSentOutboxMessages failed: ", err)
+	}
+}
+
+// post sends the message to the chat. Direct messages remember the
+// channel opened for them, so retries do not open it again
+func (bot *Bot) post(o *model.OutboxMessage) error {
+	var err error
+	switch o.Type {
+	case "ephemeral":
+		return bot.messenger.PostEphemeral(o.ChannelID, o.UserID, o.Text)
+	case "direct":
+		if o.ChannelID == "" {
+			o.ChannelID, err = bot.messenger.OpenIMChannel(o.UserID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var attachments []slack.Attachment
+	if o.Attachments != "" {
+		err = json.Unmarshal([]byte(o.Attachments), &attachments)
+		if err != nil {
+			return err
+		}
+	}
+
+	o.MessageTS, err = bot.messenger.PostMessage(o.ChannelID, o.Text, attachments)
+	return err
+}
+
+func (bot *Bot) maxOutboxAttempts() int {
+	if bot.conf.OutboxMaxAttempts < 1 {
+		return 1
+	}
+	return bot.conf.OutboxMaxAttempts
+}
+
+func milliseconds(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,174 @@
+package botuser
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/model"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.conf.OutboxMaxAttempts = 3
+	clk := clock.NewMock(time.Date(2019, 11, 4, 9, 0, 0, 0, time.UTC))
+	bot.SetClock(clk)
+
+	// direct message remembers its channel
+	require.NoError(t, bot.SendUserMessage("U1", "hello"))
+	sent, err := bot.db.ListWorkspaceOutboxMessages("testTeam", model.OutboxSent, 10)
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+	assert.Equal(t, "DU1", sent[0].ChannelID)
+	assert.Equal(t, "1.000100", sent[0].MessageTS)
+	assert.Equal(t, 1, sent[0].Attempts)
+
+	// rate limited message waits for Retry-After without losing an attempt
+	messenger.failures = []error{&slack.RateLimitedError{RetryAfter: 30 * time.Second}}
+	require.NoError(t, bot.SendMessage("C1", "first", nil))
+	require.NoError(t, bot.SendMessage("C1", "second", nil))
+	assert.Len(t, messenger.messages, 1)
+
+	pending, err := bot.db.ListWorkspaceOutboxMessages("testTeam", model.OutboxPending, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "first", pending[1].Text)
+
+	clk.Add(10 * time.Second)
+	bot.deliverOutbox(clk.Now())
+	assert.Len(t, messenger.messages, 1)
+
+	clk.Add(20 * time.Second)
+	bot.deliverOutbox(clk.Now())
+	require.Len(t, messenger.messages, 3)
+	assert.Equal(t, "first", messenger.messages[1].Text)
+	assert.Equal(t, "second", messenger.messages[2].Text)
+
+	first, err := bot.db.GetOutboxMessage(pending[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OutboxSent, first.Status)
+	assert.Equal(t, 1, first.Attempts)
+
+	// other failures are retried with backoff until attempts run out
+	fail := errors.New("channel_not_found")
+	messenger.failures = []error{fail, fail, fail}
+	require.NoError(t, bot.SendMessage("C2", "lost", nil))
+
+	failing, err := bot.db.ListWorkspaceOutboxMessages("testTeam", model.OutboxPending, 10)
+	require.NoError(t, err)
+	require.Len(t, failing, 1)
+	assert.Equal(t, clk.Now().Add(10*time.Second).Unix(), failing[0].NextAttemptAt)
+
+	clk.Add(10 * time.Second)
+	bot.deliverOutbox(clk.Now())
+	o, err := bot.db.GetOutboxMessage(failing[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OutboxPending, o.Status)
+	assert.Equal(t, 2, o.Attempts)
+	assert.Equal(t, clk.Now().Add(20*time.Second).Unix(), o.NextAttemptAt)
+
+	clk.Add(20 * time.Second)
+	bot.deliverOutbox(clk.Now())
+	o, err = bot.db.GetOutboxMessage(failing[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OutboxFailed, o.Status)
+	assert.Equal(t, "channel_not_found", o.Error)
+	assert.Len(t, messenger.messages, 3)
+}
+
+func TestOutboxRateLimit(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.conf.OutboxRate = 1
+	bot.conf.OutboxBurst = 2
+	clk := clock.NewMock(time.Date(2019, 11, 30, 9, 0, 0, 0, time.UTC))
+	bot.SetClock(clk)
+
+	// the limit is shared with other replicas of the workspace
+	other := New(bot.conf, bot.bundle, *bot.workspace, bot.db)
+	otherMessenger := newRecordingMessenger()
+	other.messenger = otherMessenger
+	other.SetClock(clk)
+
+	require.NoError(t, bot.SendUserMessage("U1", "log your work"))
+	require.NoError(t, other.SendUserMessage("U2", "log your work"))
+	for _, user := range []string{"U3", "U4", "U5"} {
+		require.NoError(t, bot.SendUserMessage(user, "log your work"))
+	}
+	require.NoError(t, other.SendUserMessage("U6", "log your work"))
+	assert.Len(t, messenger.messages, 1)
+	assert.Len(t, otherMessenger.messages, 1)
+
+	for i := 0; i < 3; i++ {
+		clk.Add(time.Second)
+		bot.deliverOutbox(clk.Now())
+	}
+	require.Len(t, messenger.messages, 4)
+	assert.Equal(t, "DU5", messenger.messages[3].Channel)
+	require.Len(t, otherMessenger.messages, 1)
+
+	clk.Add(time.Minute)
+	bot.deliverOutbox(clk.Now())
+	require.Len(t, messenger.messages, 5)
+	assert.Equal(t, "DU6", messenger.messages[4].Channel)
+
+	// Retry-After pauses every replica
+	otherMessenger.failures = []error{&slack.RateLimitedError{RetryAfter: 30 * time.Second}}
+	require.NoError(t, other.SendUserMessage("U7", "log your work"))
+	assert.Len(t, otherMessenger.messages, 1)
+	clk.Add(10 * time.Second)
+	assert.False(t, bot.allowPost(clk.Now()))
+	clk.Add(20 * time.Second)
+	bot.deliverOutbox(clk.Now())
+	require.Len(t, messenger.messages, 6)
+	assert.Equal(t, "DU7", messenger.messages[5].Channel)
+}
+
+func TestOutboxOrder(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.conf.OutboxMaxAttempts = 3
+	clk := clock.NewMock(time.Date(2019, 11, 4, 9, 0, 0, 0, time.UTC))
+	bot.SetClock(clk)
+
+	// a new message does not overtake the one waiting for retry
+	messenger.failures = []error{errors.New("timeout")}
+	require.NoError(t, bot.SendMessage("C1", "question 1", nil))
+	require.NoError(t, bot.SendMessage("C1", "question 2", nil))
+	assert.Empty(t, messenger.messages)
+
+	clk.Add(10 * time.Second)
+	bot.deliverOutbox(clk.Now())
+	require.Len(t, messenger.messages, 2)
+	assert.Equal(t, "question 1", messenger.messages[0].Text)
+	assert.Equal(t, "question 2", messenger.messages[1].Text)
+
+	require.NoError(t, bot.SendMessage("C1", "question 3", nil))
+	assert.Len(t, messenger.messages, 3)
+}
+
+func TestPurgeOutbox(t *testing.T) {
+	bot, _ := newTestBot()
+	bot.conf.OutboxRetention = 7
+	clk := clock.NewMock(time.Date(2019, 11, 4, 9, 0, 0, 0, time.UTC))
+	bot.SetClock(clk)
+
+	require.NoError(t, bot.SendMessage("C1", "old", nil))
+	clk.Add(7*24*time.Hour + time.Hour)
+	require.NoError(t, bot.SendMessage("C1", "new", nil))
+
+	bot.purgeOutbox(clk.Now())
+	sent, err := bot.db.ListWorkspaceOutboxMessages("testTeam", model.OutboxSent, 10)
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+	assert.Equal(t, "new", sent[0].Text)
+
+	bot.conf.OutboxRetention = 0
+	clk.Add(30 * 24 * time.Hour)
+	bot.purgeOutbox(clk.Now())
+	sent, err = bot.db.ListWorkspaceOutboxMessages("testTeam", model.OutboxSent, 10)
+	require.NoError(t, err)
+	assert.Len(t, sent, 1)
+}
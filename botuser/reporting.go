@@ -1,6 +1,7 @@
 package botuser
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -11,9 +12,6 @@ import (
 	"github.com/maddevsio/comedian/model"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/slack-go/slack"
-	"github.com/olebedev/when"
-	"github.com/olebedev/when/rules/en"
-	"github.com/olebedev/when/rules/ru"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -29,54 +27,6 @@ type AttachmentItem struct {
 	Points          int
 }
 
-// CallDisplayYesterdayTeamReport calls displayYesterdayTeamReport
-func (bot *Bot) CallDisplayYesterdayTeamReport() error {
-	if bot.workspace.ReportingTime == "" {
-		return nil
-	}
-
-	w := when.New(nil)
-	w.Add(en.All...)
-	w.Add(ru.All...)
-
-	r, err := w.Parse(bot.workspace.ReportingTime, time.Now())
-	if err != nil {
-		return err
-	}
-
-	if time.Now().Hour() != r.Time.Hour() || time.Now().Minute() != r.Time.Minute() {
-		return nil
-	}
-
-	_, err = bot.displayYesterdayTeamReport()
-	return err
-
-}
-
-// CallDisplayWeeklyTeamReport calls displayWeeklyTeamReport
-func (bot *Bot) CallDisplayWeeklyTeamReport() error {
-	if int(time.Now().Weekday()) != 0 {
-		return nil
-	}
-
-	if bot.workspace.ReportingTime == "" {
-		return nil
-	}
-
-	w := when.New(nil)
-	w.Add(en.All...)
-	w.Add(ru.All...)
-
-	r, err := w.Parse(bot.workspace.ReportingTime, time.Now())
-
-	if time.Now().Hour() != r.Time.Hour() || time.Now().Minute() != r.Time.Minute() {
-		return nil
-	}
-
-	_, err = bot.displayWeeklyTeamReport()
-	return err
-}
-
 // displayYesterdayTeamReport generates report on users who submit standups
 func (bot *Bot) displayYesterdayTeamReport() (string, error) {
 	var allReports []slack.Attachment
@@ -97,6 +47,9 @@ func (bot *Bot) displayYesterdayTeamReport() (string, error) {
 	}
 
 	for _, channel := range channels {
+		if channel.Inactive {
+			continue
+		}
 
 		var attachments []slack.Attachment
 		var attachmentsPull []AttachmentItem
@@ -117,11 +70,20 @@ func (bot *Bot) displayYesterdayTeamReport() (string, error) {
 			var worklogs, commits, standup string
 			var worklogsPoints, commitsPoints, standupPoints int
 
-			dataOnUser, dataOnUserInProject, collectorError := bot.GetCollectorDataOnMember(standuper, time.Now().AddDate(0, 0, -1), time.Now().AddDate(0, 0, -1))
+			// days are counted in the time zone of the channel or the standuper
+			now := bot.clock.Now().In(standuperLocation(channel, standuper))
+			yesterday := now.AddDate(0, 0, -1)
+
+			// nobody is expected to report about days off
+			if bot.absentBetween(standuper.UserID, yesterday, yesterday) {
+				continue
+			}
+
+			dataOnUser, dataOnUserInProject, collectorError := bot.GetCollectorDataOnMember(standuper, yesterday, yesterday)
 
 			if collectorError == nil {
-				worklogs, worklogsPoints = bot.processWorklogs(dataOnUser.Worklogs, dataOnUserInProject.Worklogs)
-				commits, commitsPoints = bot.processCommits(dataOnUser.Commits, dataOnUserInProject.Commits)
+				worklogs, worklogsPoints = bot.processWorklogs(now, dataOnUser.Worklogs, dataOnUserInProject.Worklogs)
+				commits, commitsPoints = bot.processCommits(now, dataOnUser.Commits, dataOnUserInProject.Commits)
 			}
 
 			if standuper.Role == "pm" || standuper.Role == "designer" {
@@ -189,7 +151,7 @@ func (bot *Bot) displayYesterdayTeamReport() (string, error) {
 				attachment.Color = "good"
 			}
 
-			if int(time.Now().Weekday()) == 0 || int(time.Now().Weekday()) == 1 {
+			if int(now.Weekday()) == 0 || int(now.Weekday()) == 1 {
 				attachment.Color = "good"
 			}
 
@@ -265,6 +227,10 @@ func (bot *Bot) displayWeeklyTeamReport() (string, error) {
 	}
 
 	for _, channel := range channels {
+		if channel.Inactive {
+			continue
+		}
+
 		var attachmentsPull []AttachmentItem
 		var attachments []slack.Attachment
 
@@ -284,11 +250,18 @@ func (bot *Bot) displayWeeklyTeamReport() (string, error) {
 			var worklogs, commits string
 			var worklogsPoints, commitsPoints int
 
-			dataOnUser, dataOnUserInProject, collectorError := bot.GetCollectorDataOnMember(standuper, time.Now().AddDate(0, 0, -7), time.Now().AddDate(0, 0, -1))
+			now := bot.clock.Now().In(standuperLocation(channel, standuper))
+
+			// worklogs of a week with days off are not comparable with others
+			if bot.absentBetween(standuper.UserID, now.AddDate(0, 0, -7), now.AddDate(0, 0, -1)) {
+				continue
+			}
+
+			dataOnUser, dataOnUserInProject, collectorError := bot.GetCollectorDataOnMember(standuper, now.AddDate(0, 0, -7), now.AddDate(0, 0, -1))
 
 			if collectorError == nil {
 				worklogs, worklogsPoints = bot.processWeeklyWorklogs(dataOnUser.Worklogs, dataOnUserInProject.Worklogs)
-				commits, commitsPoints = bot.processCommits(dataOnUser.Commits, dataOnUserInProject.Commits)
+				commits, commitsPoints = bot.processCommits(now, dataOnUser.Commits, dataOnUserInProject.Commits)
 			}
 
 			if standuper.Role == "pm" || standuper.Role == "designer" {
@@ -404,7 +377,9 @@ func (bot *Bot) displayWeeklyTeamReport() (string, error) {
 	return fmt.Sprintf(reportHeaderWeekly, allReports), err
 }
 
-func (bot *Bot) processWorklogs(totalWorklogs, projectWorklogs int) (string, int) {
+// processWorklogs rates worklogs of the day, now tells Sundays and Mondays in
+// the time zone of the standuper
+func (bot *Bot) processWorklogs(now time.Time, totalWorklogs, projectWorklogs int) (string, int) {
 
 	var points int
 	worklogsEmoji := ""
@@ -439,7 +414,7 @@ func (bot *Bot) processWorklogs(totalWorklogs, projectWorklogs int) (string, int
 		}
 	}
 
-	if int(time.Now().Weekday()) == 0 || int(time.Now().Weekday()) == 1 {
+	if int(now.Weekday()) == 0 || int(now.Weekday()) == 1 {
 		worklogsEmoji = ""
 		if projectWorklogs == 0 {
 			return "", points
@@ -504,7 +479,9 @@ func (bot *Bot) processWeeklyWorklogs(totalWorklogs, projectWorklogs int) (strin
 	return worklogsTranslation, points
 }
 
-func (bot *Bot) processCommits(totalCommits, projectCommits int) (string, int) {
+// processCommits rates commits of the day, now tells Sundays and Mondays in
+// the time zone of the standuper
+func (bot *Bot) processCommits(now time.Time, totalCommits, projectCommits int) (string, int) {
 	var points int
 	commitsEmoji := ""
 
@@ -517,7 +494,7 @@ func (bot *Bot) processCommits(totalCommits, projectCommits int) (string, int) {
 		points++
 	}
 
-	if int(time.Now().Weekday()) == 0 || int(time.Now().Weekday()) == 1 {
+	if int(now.Weekday()) == 0 || int(now.Weekday()) == 1 {
 		commitsEmoji = ""
 		if projectCommits == 0 {
 			return "", points
@@ -541,24 +518,27 @@ func (bot *Bot) processStandup(member model.Standuper) (string, int) {
 	var text string
 	var points int
 
-	t := time.Now().AddDate(0, 0, -1)
-
-	timeFrom := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local).Unix()
-	timeTo := time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, time.Local).Unix()
-
 	channel, err := bot.db.SelectProject(member.ChannelID)
 	if err != nil {
 		log.Error("reporting SelectProject failed: ", err)
 		return "", points
 	}
 
-	standup, err := bot.db.GetStandupForPeriod(member.UserID, member.ChannelID, timeFrom, timeTo)
-	if err != nil {
+	// yesterday is counted in the time zone of the channel or the standuper
+	loc := standuperLocation(channel, member)
+	t := bot.clock.Now().In(loc).AddDate(0, 0, -1)
+
+	timeFrom := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Unix()
+	timeTo := time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, loc).Unix()
+
+	_, err = bot.db.GetStandupForPeriod(member.UserID, member.ChannelID, timeFrom, timeTo)
+	if err != nil && err != sql.ErrNoRows {
 		log.Error("GetStandupForPeriod failed: ", err)
 		return "", points
 	}
-	if standup == nil {
-		if !shouldSubmitStandupIn(&channel, t) {
+	if err == sql.ErrNoRows {
+		schedule := standuperSchedule(channel, member)
+		if !bot.submissionDay(schedule, t) {
 			return "", points + 1
 		}
 
@@ -0,0 +1,69 @@
+package botuser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayYesterdayTeamReport(t *testing.T) {
+	bot, messenger := newTestBot(
+		&i18n.Message{ID: "reportHeader", Other: "Daily report"},
+		&i18n.Message{ID: "hasStandup", Other: "standup submitted"},
+		&i18n.Message{ID: "notTagStanduper", Other: "{{.user}} in #{{.channel}}"},
+	)
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN1",
+		ChannelName: "general",
+		TZ:          "Local",
+	})
+	require.NoError(t, err)
+
+	_, err = bot.db.CreateProject(model.Project{
+		WorkspaceID: "testTeam",
+		ChannelID:   "CREP",
+		ChannelName: "reports",
+		TZ:          "Local",
+	})
+	require.NoError(t, err)
+
+	_, err = bot.db.CreateStanduper(model.Standuper{
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN1",
+		UserID:      "U1",
+		RealName:    "John Doe",
+	})
+	require.NoError(t, err)
+
+	_, err = bot.displayYesterdayTeamReport()
+	require.NoError(t, err)
+	assert.Empty(t, messenger.flush())
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	_, err = bot.db.CreateStandup(model.Standup{
+		CreatedAt:   time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 12, 0, 0, 0, time.Local).Unix(),
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN1",
+		UserID:      "U1",
+		MessageTS:   "1.1",
+	})
+	require.NoError(t, err)
+
+	_, err = bot.displayYesterdayTeamReport()
+	require.NoError(t, err)
+
+	messages := messenger.flush()
+	require.Equal(t, 1, len(messages))
+	assert.Equal(t, "CREP", messages[0].Channel)
+	assert.Equal(t, "Daily report", messages[0].Text)
+	require.Equal(t, 1, len(messages[0].Attachments))
+	assert.Equal(t, "John Doe in #general", messages[0].Attachments[0].Text)
+	assert.Equal(t, "good", messages[0].Attachments[0].Color)
+	assert.Equal(t, "standup submitted", messages[0].Attachments[0].Fields[0].Value)
+}
@@ -0,0 +1,528 @@
+package botuser
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/olebedev/when"
+	"github.com/olebedev/when/rules/en"
+	"github.com/olebedev/when/rules/ru"
+	log "github.com/sirupsen/logrus"
+)
+
+// schedulerTick is how often bot looks for due jobs. A late tick does not
+// lose anything: everything that became due since the previous tick runs
+const schedulerTick = 10 * time.Second
+
+// worklogsReminderHour is the hour of the last day of month when
+// standupers are asked to double check their worklogs
+const worklogsReminderHour = 10
+
+// jobSpec is a job bot has to have in its schedule and the rule which
+// computes its next run
+type jobSpec struct {
+	job  model.Job
+	next func(after time.Time) time.Time
+}
+
+// runScheduler brings the schedule in line with workspace and projects
+// settings and runs jobs due at now
+func (bot *Bot) runScheduler(now time.Time) error {
+	specs, err := bot.jobSpecs()
+	if err != nil {
+		return err
+	}
+
+	err = bot.syncJobs(specs, now)
+	if err != nil {
+		return err
+	}
+
+	return bot.runDueJobs(specs, now)
+}
+
+// jobSpecs lists periodic jobs of the workspace. Reminders are not listed:
+// alarm schedules them when somebody misses the deadline
+func (bot *Bot) jobSpecs() (map[string]jobSpec, error) {
+	specs := map[string]jobSpec{}
+
+	channels, err := bot.db.ListWorkspaceProjects(bot.workspace.WorkspaceID)
+	if err != nil {
+		return specs, err
+	}
+	standupers, err := bot.db.ListWorkspaceStandupers(bot.workspace.WorkspaceID)
+	if err != nil {
+		return specs, err
+	}
+
+	slots, err := bot.db.ListWorkspaceDeadlineSlots(bot.workspace.WorkspaceID)
+	if err != nil {
+		return specs, err
+	}
+	channelSlots := map[string][]model.DeadlineSlot{}
+	for _, slot := range slots {
+		channelSlots[slot.ChannelID] = append(channelSlots[slot.ChannelID], slot)
+	}
+
+	byID := map[string]model.Project{}
+	for _, channel := range channels {
+		// nobody is notified in archived channels and channels without the bot
+		if channel.Inactive {
+			continue
+		}
+		byID[channel.ChannelID] = channel
+		// deadline slots replace the deadline of the channel
+		for _, slot := range channelSlots[channel.ChannelID] {
+			bot.addDeadlineJobSpecs(specs, channel, "", slot)
+		}
+		if channel.Deadline != "" && len(channelSlots[channel.ChannelID]) == 0 {
+			bot.addDeadlineJobSpecs(specs, channel, "", model.DeadlineSlot{})
+		}
+	}
+
+	// standupers with individual schedule are notified on their own
+	for _, standuper := range standupers {
+		channel, ok := byID[standuper.ChannelID]
+		if !ok || !standuper.HasSchedule() {
+			continue
+		}
+		schedule := standuperSchedule(channel, standuper)
+		if schedule.Deadline != "" {
+			bot.addDeadlineJobSpecs(specs, schedule, standuper.UserID, model.DeadlineSlot{})
+		}
+	}
+
+	// standupers who answer in direct messages are asked before deadline
+	for _, standuper := range standupers {
+		channel, ok := byID[standuper.ChannelID]
+		if !ok || standuper.DMStandupOffset == 0 {
+			continue
+		}
+		bot.addDMStandupJobSpec(specs, channel, standuper, channelSlots[channel.ChannelID])
+	}
+
+	if bot.workspace.ReportingTime != "" {
+		hour, minute, err := bot.parseClock(bot.workspace.ReportingTime)
+		if err != nil {
+			log.Errorf("could not schedule reports: %v", err)
+		} else {
+			loc := bot.workspaceLocation()
+			bot.addJobSpec(specs, model.JobDailyReport, "", "", 0, bot.workspace.ReportingTime, func(after time.Time) time.Time {
+				return nextTime(hour, minute, loc, after, func(time.Time) bool {
+					return true
+				})
+			})
+			bot.addJobSpec(specs, model.JobWeeklyReport, "", "", 0, bot.workspace.ReportingTime, func(after time.Time) time.Time {
+				return nextTime(hour, minute, loc, after, func(t time.Time) bool {
+					return t.Weekday() == time.Sunday
+				})
+			})
+			bot.addJobSpec(specs, model.JobBlockersDigest, "", "", 0, bot.workspace.ReportingTime, func(after time.Time) time.Time {
+				return nextTime(hour, minute, loc, after, func(time.Time) bool {
+					return true
+				})
+			})
+		}
+	}
+
+	bot.addJobSpec(specs, model.JobWorklogsReminder, "", "", 0, "", func(after time.Time) time.Time {
+		return nextTime(worklogsReminderHour, 0, bot.workspaceLocation(), after, func(t time.Time) bool {
+			return t.AddDate(0, 0, 1).Day() == 1
+		})
+	})
+
+	return specs, nil
+}
+
+// addDeadlineJobSpecs adds warning and alarm of the channel schedule, of
+// individual schedule of the standuper when userID is set, or of the deadline
+// slot of the channel when slot is set
+func (bot *Bot) addDeadlineJobSpecs(specs map[string]jobSpec, channel model.Project, userID string, slot model.DeadlineSlot) {
+	deadline, spec, err := bot.deadlineRule(channel, slot)
+	if err != nil {
+		log.Errorf("could not schedule notifications in %v: %v", channel.ChannelName, err)
+		return
+	}
+
+	bot.addJobSpec(specs, model.JobAlarm, channel.ChannelID, userID, slot.ID, spec, deadline)
+	bot.addJobSpec(specs, model.JobWarning, channel.ChannelID, userID, slot.ID, fmt.Sprintf("%v|%v", spec, bot.workspace.ReminderOffset), beforeDeadline(deadline, bot.workspace.ReminderOffset))
+}
+
+// addDMStandupJobSpec adds the job which asks the standuper standup
+// questions in direct messages before their deadline or before the
+// earliest deadline slot of the channel
+func (bot *Bot) addDMStandupJobSpec(specs map[string]jobSpec, channel model.Project, standuper model.Standuper, slots []model.DeadlineSlot) {
+	if standuper.HasSchedule() {
+		channel = standuperSchedule(channel, standuper)
+		slots = nil
+	}
+	if len(slots) == 0 {
+		if channel.Deadline == "" {
+			return
+		}
+		slots = []model.DeadlineSlot{{}}
+	}
+
+	rules := []func(time.Time) time.Time{}
+	spec := ""
+	for _, slot := range slots {
+		rule, slotSpec, err := bot.deadlineRule(channel, slot)
+		if err != nil {
+			log.Errorf("could not schedule DM standups in %v: %v", channel.ChannelName, err)
+			continue
+		}
+		rules = append(rules, rule)
+		spec += slotSpec + "|"
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	deadline := func(after time.Time) time.Time {
+		var earliest time.Time
+		for _, rule := range rules {
+			next := rule(after)
+			if !next.IsZero() && (earliest.IsZero() || next.Before(earliest)) {
+				earliest = next
+			}
+		}
+		return earliest
+	}
+	bot.addJobSpec(specs, model.JobDMStandup, channel.ChannelID, standuper.UserID, 0, fmt.Sprintf("%v%v", spec, standuper.DMStandupOffset), beforeDeadline(deadline, standuper.DMStandupOffset))
+}
+
+// deadlineRule returns the rule which computes the next deadline of the
+// channel schedule, or of the deadline slot when slot is set, and the
+// settings the rule depends on
+func (bot *Bot) deadlineRule(channel model.Project, slot model.DeadlineSlot) (func(time.Time) time.Time, string, error) {
+	loc, err := time.LoadLocation(channel.TZ)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var hour, minute int
+	days := model.AllWeekdays
+	spec := fmt.Sprintf("%v|%v|%v", channel.Deadline, channel.TZ, channel.SubmissionDays)
+	if slot.ID == 0 {
+		hour, minute, err = bot.parseClock(channel.Deadline)
+	} else {
+		hour, minute, days, err = parseSlot(slot)
+		spec = fmt.Sprintf("%v|%v|%v|%v", slot.Deadline, channel.TZ, channel.SubmissionDays, slot.Days)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	submissionDay := func(t time.Time) bool {
+		return days.Has(t.Weekday()) && bot.submissionDay(channel, t)
+	}
+	return func(after time.Time) time.Time {
+		return nextTime(hour, minute, loc, after, submissionDay)
+	}, spec, nil
+}
+
+// beforeDeadline returns the rule which runs minutes before the deadline
+func beforeDeadline(deadline func(time.Time) time.Time, minutes int64) func(time.Time) time.Time {
+	offset := time.Duration(minutes) * time.Minute
+	return func(after time.Time) time.Time {
+		next := deadline(after.Add(offset))
+		if next.IsZero() {
+			return next
+		}
+		return next.Add(-offset)
+	}
+}
+
+func (bot *Bot) addJobSpec(specs map[string]jobSpec, kind, channelID, userID string, slotID int64, spec string, next func(time.Time) time.Time) {
+	name := jobName(kind, channelID, userID, slotID)
+	specs[name] = jobSpec{
+		job: model.Job{
+			WorkspaceID: bot.workspace.WorkspaceID,
+			Name:        name,
+			Kind:        kind,
+			ChannelID:   channelID,
+			UserID:      userID,
+			SlotID:      slotID,
+			Spec:        spec,
+		},
+		next: next,
+	}
+}
+
+// syncJobs creates jobs which are missing, reschedules jobs whose settings
+// changed and deletes jobs which are not needed anymore
+func (bot *Bot) syncJobs(specs map[string]jobSpec, now time.Time) error {
+	jobs, err := bot.db.ListWorkspaceJobs(bot.workspace.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]model.Job{}
+	for _, job := range jobs {
+		existing[job.Name] = job
+
+		if job.Kind == model.JobReminder {
+			if _, ok := specs[jobName(model.JobAlarm, job.ChannelID, job.UserID, job.SlotID)]; ok {
+				continue
+			}
+		} else if _, ok := specs[job.Name]; ok {
+			continue
+		}
+
+		err := bot.db.DeleteJob(job.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for name, spec := range specs {
+		job, ok := existing[name]
+		if ok && job.Spec == spec.job.Spec {
+			continue
+		}
+
+		if ok {
+			job.Spec = spec.job.Spec
+			job.NextRunAt = unix(spec.next(now))
+			_, err = bot.db.UpdateJob(job)
+		} else {
+			job = spec.job
+			job.NextRunAt = unix(spec.next(now))
+			_, err = bot.db.CreateJob(job)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runDueJobs runs jobs which are due. Jobs overdue for more than grace
+// window (e.g. Comedian was down) are recorded as missed and skipped
+func (bot *Bot) runDueJobs(specs map[string]jobSpec, now time.Time) error {
+	jobs, err := bot.db.ListWorkspaceJobs(bot.workspace.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	grace := time.Duration(bot.conf.SchedulerGraceWindow) * time.Minute
+
+	for _, job := range jobs {
+		if job.NextRunAt == 0 || job.NextRunAt > now.Unix() {
+			continue
+		}
+
+		// previous jobs of this run could have rescheduled or deleted it
+		job, err = bot.db.GetJob(job.ID)
+		if err != nil || job.NextRunAt == 0 || job.NextRunAt > now.Unix() {
+			continue
+		}
+
+		// the claim keeps other replicas from running the job even if this
+		// pass outlives the lease. Job of a replica which died while running
+		// it is run again once the claim expires
+		claimed, err := bot.db.ClaimJob(job.ID, job.NextRunAt, now.Add(grace).Unix())
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		scheduled := time.Unix(job.NextRunAt, 0)
+		run := model.JobRun{
+			JobID:       job.ID,
+			WorkspaceID: job.WorkspaceID,
+			JobName:     job.Name,
+			ScheduledAt: job.NextRunAt,
+			StartedAt:   now.Unix(),
+		}
+
+		var next time.Time
+		if now.Sub(scheduled) > grace {
+			run.Status = model.JobRunMissed
+		} else {
+			next, err = bot.runJob(job, scheduled)
+			run.Status = model.JobRunSucceeded
+			if err != nil {
+				log.Errorf("job %v of %v failed: %v", job.Name, job.WorkspaceID, err)
+				run.Status = model.JobRunFailed
+				run.Error = err.Error()
+			}
+		}
+		run.FinishedAt = bot.clock.Now().Unix()
+
+		_, err = bot.db.CreateJobRun(run)
+		if err != nil {
+			log.Error("CreateJobRun failed: ", err)
+		}
+
+		if spec, ok := specs[job.Name]; ok {
+			next = spec.next(now)
+		}
+
+		if job.Kind == model.JobReminder && next.IsZero() {
+			err = bot.db.DeleteJob(job.ID)
+		} else {
+			job.LastRunAt = scheduled.Unix()
+			job.NextRunAt = unix(next)
+			_, err = bot.db.UpdateJob(job)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runJob does the work of the job. For reminders it returns time of the
+// next reminder, other jobs are rescheduled by their spec
+func (bot *Bot) runJob(job model.Job, scheduled time.Time) (time.Time, error) {
+	switch job.Kind {
+	case model.JobWarning:
+		channel, err := bot.db.SelectProject(job.ChannelID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		// holiday could be added after the job was scheduled
+		offset := time.Duration(bot.workspace.ReminderOffset) * time.Minute
+		if bot.holidayAt(channel, job.UserID, scheduled.Add(offset)) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, bot.warn(channel, job.UserID, job.SlotID, scheduled.Add(offset))
+
+	case model.JobAlarm:
+		channel, err := bot.db.SelectProject(job.ChannelID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if bot.holidayAt(channel, job.UserID, scheduled) {
+			return time.Time{}, nil
+		}
+		remindAt, err := bot.alarm(channel, job.UserID, job.SlotID, scheduled)
+		if err != nil || remindAt.IsZero() {
+			return time.Time{}, err
+		}
+		return time.Time{}, bot.scheduleReminder(channel.ChannelID, job.UserID, job.SlotID, remindAt)
+
+	case model.JobReminder:
+		channel, err := bot.db.SelectProject(job.ChannelID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return bot.remind(channel, job.UserID, job.SlotID, scheduled)
+
+	case model.JobDailyReport:
+		_, err := bot.displayYesterdayTeamReport()
+		return time.Time{}, err
+
+	case model.JobWeeklyReport:
+		_, err := bot.displayWeeklyTeamReport()
+		return time.Time{}, err
+
+	case model.JobWorklogsReminder:
+		return time.Time{}, bot.remindAboutWorklogs()
+
+	case model.JobBlockersDigest:
+		return time.Time{}, bot.sendBlockersDigest(scheduled)
+
+	case model.JobDMStandup:
+		channel, err := bot.db.SelectProject(job.ChannelID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		standuper, err := bot.db.FindStansuperByUserID(job.UserID, job.ChannelID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		offset := time.Duration(standuper.DMStandupOffset) * time.Minute
+		if bot.holidayAt(channel, job.UserID, scheduled.Add(offset)) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, bot.startDMStandup(channel, job.UserID)
+
+	default:
+		return time.Time{}, fmt.Errorf("unknown job kind %v", job.Kind)
+	}
+}
+
+func (bot *Bot) scheduleReminder(channelID, userID string, slotID int64, at time.Time) error {
+	name := jobName(model.JobReminder, channelID, userID, slotID)
+
+	jobs, err := bot.db.ListWorkspaceJobs(bot.workspace.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Name == name {
+			job.NextRunAt = at.Unix()
+			_, err = bot.db.UpdateJob(job)
+			return err
+		}
+	}
+
+	_, err = bot.db.CreateJob(model.Job{
+		WorkspaceID: bot.workspace.WorkspaceID,
+		Name:        name,
+		Kind:        model.JobReminder,
+		ChannelID:   channelID,
+		UserID:      userID,
+		SlotID:      slotID,
+		NextRunAt:   at.Unix(),
+	})
+	return err
+}
+
+func jobName(kind, channelID, userID string, slotID int64) string {
+	if channelID == "" {
+		return kind
+	}
+	if slotID != 0 {
+		return fmt.Sprintf("%v:%v:slot%v", kind, channelID, slotID)
+	}
+	if userID == "" {
+		return kind + ":" + channelID
+	}
+	return kind + ":" + channelID + ":" + userID
+}
+
+// parseClock returns hour and minute of time of day like "10am" or "13:00".
+// Text is parsed relative to the bot clock, like the rest of the scheduler
+func (bot *Bot) parseClock(text string) (int, int, error) {
+	w := when.New(nil)
+	w.Add(en.All...)
+	w.Add(ru.All...)
+
+	r, err := w.Parse(text, bot.clock.Now())
+	if err != nil {
+		return 0, 0, err
+	}
+	if r == nil {
+		return 0, 0, fmt.Errorf("could not recognize time %q", text)
+	}
+	return r.Time.Hour(), r.Time.Minute(), nil
+}
+
+// nextTime returns the first hour:minute in loc strictly after the given
+// time which falls on a matching day, or zero time if no day matches
+func nextTime(hour, minute int, loc *time.Location, after time.Time, match func(time.Time) bool) time.Time {
+	day := after.In(loc)
+	// 62 days is enough to find the last day of the next month
+	for i := 0; i < 62; i++ {
+		t := time.Date(day.Year(), day.Month(), day.Day()+i, hour, minute, 0, 0, loc)
+		if t.After(after) && match(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,361 @@
+package botuser
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/model"
+	"github.com/maddevsio/comedian/storage"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextTime(t *testing.T) {
+	bishkek, err := time.LoadLocation("Asia/Bishkek")
+	require.NoError(t, err)
+
+	weekdays := func(t time.Time) bool {
+		return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+	}
+	lastDayOfMonth := func(t time.Time) bool {
+		return t.AddDate(0, 0, 1).Day() == 1
+	}
+	never := func(time.Time) bool {
+		return false
+	}
+
+	testCases := []struct {
+		after    time.Time
+		hour     int
+		loc      *time.Location
+		match    func(time.Time) bool
+		expected time.Time
+	}{
+		// Monday before and after deadline
+		{time.Date(2019, 11, 4, 9, 0, 0, 0, bishkek), 10, bishkek, weekdays, time.Date(2019, 11, 4, 10, 0, 0, 0, bishkek)},
+		{time.Date(2019, 11, 4, 10, 0, 0, 0, bishkek), 10, bishkek, weekdays, time.Date(2019, 11, 5, 10, 0, 0, 0, bishkek)},
+		// Friday evening goes to Monday
+		{time.Date(2019, 11, 8, 11, 0, 0, 0, bishkek), 10, bishkek, weekdays, time.Date(2019, 11, 11, 10, 0, 0, 0, bishkek)},
+		// deadline in project timezone, not in the timezone of the given time
+		{time.Date(2019, 11, 4, 3, 0, 0, 0, time.UTC), 10, bishkek, weekdays, time.Date(2019, 11, 4, 10, 0, 0, 0, bishkek)},
+		{time.Date(2019, 11, 4, 5, 0, 0, 0, time.UTC), 10, bishkek, weekdays, time.Date(2019, 11, 5, 10, 0, 0, 0, bishkek)},
+		{time.Date(2019, 11, 4, 11, 0, 0, 0, time.UTC), 10, time.UTC, lastDayOfMonth, time.Date(2019, 11, 30, 10, 0, 0, 0, time.UTC)},
+		{time.Date(2019, 1, 31, 11, 0, 0, 0, time.UTC), 10, time.UTC, lastDayOfMonth, time.Date(2019, 2, 28, 10, 0, 0, 0, time.UTC)},
+		{time.Date(2019, 11, 4, 11, 0, 0, 0, time.UTC), 10, time.UTC, never, time.Time{}},
+	}
+
+	for _, tt := range testCases {
+		assert.Equal(t, tt.expected, nextTime(tt.hour, 0, tt.loc, tt.after, tt.match), "after %v", tt.after)
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	bot, _ := newTestBot()
+	bot.SetClock(clock.NewMock(time.Date(2019, 3, 10, 0, 0, 0, 0, time.UTC)))
+
+	testCases := []struct {
+		text   string
+		hour   int
+		minute int
+	}{
+		{"10am", 10, 0},
+		{"13:00", 13, 0},
+		{"2:30am", 2, 30},
+		{"в 9:15", 9, 15},
+	}
+	for _, tt := range testCases {
+		hour, minute, err := bot.parseClock(tt.text)
+		require.NoError(t, err, tt.text)
+		assert.Equal(t, tt.hour, hour, tt.text)
+		assert.Equal(t, tt.minute, minute, tt.text)
+	}
+
+	_, _, err := bot.parseClock("someday")
+	assert.Error(t, err)
+}
+
+func TestScheduler(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.workspace.ReportingTime = ""
+	messenger.users["U1"] = User{ID: "U1", TZ: "UTC"}
+
+	channel, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "UTC",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday",
+	})
+	require.NoError(t, err)
+
+	_, err = bot.db.CreateStanduper(model.Standuper{
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN1",
+		UserID:      "U1",
+	})
+	require.NoError(t, err)
+
+	job := func(name string) model.Job {
+		jobs, err := bot.db.ListWorkspaceJobs("testTeam")
+		require.NoError(t, err)
+		for _, j := range jobs {
+			if j.Name == name {
+				return j
+			}
+		}
+		return model.Job{}
+	}
+
+	at := func(day, hour, min, sec int) time.Time {
+		return time.Date(2019, 11, day, hour, min, sec, 0, time.UTC)
+	}
+
+	tick := func(now time.Time) []Message {
+		require.NoError(t, bot.runScheduler(now))
+		return messenger.flush()
+	}
+
+	// Monday, November 4
+	assert.Empty(t, tick(at(4, 9, 0, 0)))
+	assert.Equal(t, at(4, 9, 50, 0).Unix(), job("warning:CHAN1").NextRunAt)
+	assert.Equal(t, at(4, 10, 0, 0).Unix(), job("alarm:CHAN1").NextRunAt)
+
+	messages := tick(at(4, 9, 50, 7))
+	require.Len(t, messages, 1)
+	assert.Equal(t, "<@U1>, you are the only one to miss standup, in 10 minutes, hurry up!", messages[0].Text)
+	assert.Equal(t, at(5, 9, 50, 0).Unix(), job("warning:CHAN1").NextRunAt)
+	assert.Equal(t, at(4, 9, 50, 0).Unix(), job("warning:CHAN1").LastRunAt)
+
+	// a slow tick does not skip the alarm
+	messages = tick(at(4, 10, 0, 40))
+	require.Len(t, messages, 1)
+	assert.Equal(t, "<@U1>, you are the only one missed standup, shame!", messages[0].Text)
+	assert.Equal(t, at(4, 10, 1, 0).Unix(), job("reminder:CHAN1").NextRunAt)
+
+	messages = tick(at(4, 10, 1, 5))
+	require.Len(t, messages, 1)
+	assert.Equal(t, "<@U1>, you still haven't written a standup! Write a standup!", messages[0].Text)
+
+	// Comedian was down for a couple of minutes, reminders catch up
+	assert.Len(t, tick(at(4, 10, 4, 0)), 1)
+	assert.Len(t, tick(at(4, 10, 4, 10)), 1)
+	assert.Empty(t, tick(at(4, 10, 4, 20)))
+	assert.Equal(t, model.Job{}, job("reminder:CHAN1"), "reminders are over")
+
+	runs, err := bot.db.ListJobRuns(job("alarm:CHAN1").ID)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, model.JobRunSucceeded, runs[0].Status)
+	assert.Equal(t, at(4, 10, 0, 0).Unix(), runs[0].ScheduledAt)
+
+	// Comedian was down the whole Tuesday morning, jobs out of grace window are missed
+	assert.Empty(t, tick(at(5, 12, 0, 0)))
+	runs, err = bot.db.ListJobRuns(job("alarm:CHAN1").ID)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, model.JobRunMissed, runs[0].Status)
+	assert.Equal(t, at(6, 10, 0, 0).Unix(), job("alarm:CHAN1").NextRunAt)
+
+	// new deadline reschedules jobs
+	channel.Deadline = "11:00"
+	_, err = bot.db.UpdateProject(channel)
+	require.NoError(t, err)
+	assert.Empty(t, tick(at(5, 12, 0, 10)))
+	assert.Equal(t, at(6, 11, 0, 0).Unix(), job("alarm:CHAN1").NextRunAt)
+
+	// Friday alarm is followed by Monday one
+	assert.Empty(t, tick(at(8, 10, 0, 0)))
+	messenger.flush()
+	assert.NotEmpty(t, tick(at(8, 11, 0, 0)))
+	assert.Equal(t, at(11, 11, 0, 0).Unix(), job("alarm:CHAN1").NextRunAt)
+
+	// jobs of removed deadline are deleted
+	channel.Deadline = ""
+	_, err = bot.db.UpdateProject(channel)
+	require.NoError(t, err)
+	tick(at(8, 12, 0, 0))
+	assert.Equal(t, model.Job{}, job("alarm:CHAN1"))
+	assert.Equal(t, model.Job{}, job("warning:CHAN1"))
+	assert.Equal(t, model.Job{}, job("reminder:CHAN1"))
+	assert.NotEqual(t, model.Job{}, job(model.JobWorklogsReminder))
+}
+
+// TestWorkspaceSchedule checks reports and reminders of the workspace are
+// scheduled in its time zone whatever the time zone of the server is
+func TestWorkspaceSchedule(t *testing.T) {
+	local := time.Local
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	time.Local = tokyo
+	defer func() { time.Local = local }()
+
+	bot, _ := newTestBot()
+	bot.workspace.ReportingTime = "10am"
+	bot.workspace.TZ = "Europe/Berlin"
+
+	job := func(name string) model.Job {
+		jobs, err := bot.db.ListWorkspaceJobs("testTeam")
+		require.NoError(t, err)
+		for _, j := range jobs {
+			if j.Name == name {
+				return j
+			}
+		}
+		return model.Job{}
+	}
+
+	// Monday, November 4, Berlin is UTC+1
+	require.NoError(t, bot.runScheduler(time.Date(2019, 11, 4, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2019, 11, 4, 9, 0, 0, 0, time.UTC).Unix(), job(model.JobDailyReport).NextRunAt)
+	assert.Equal(t, time.Date(2019, 11, 4, 9, 0, 0, 0, time.UTC).Unix(), job(model.JobBlockersDigest).NextRunAt)
+	assert.Equal(t, time.Date(2019, 11, 10, 9, 0, 0, 0, time.UTC).Unix(), job(model.JobWeeklyReport).NextRunAt)
+	assert.Equal(t, time.Date(2019, 11, 30, 9, 0, 0, 0, time.UTC).Unix(), job(model.JobWorklogsReminder).NextRunAt)
+}
+
+// TestStandupDays runs the bot minute by minute from Friday to Tuesday in a
+// project whose timezone switches to daylight saving time on Sunday
+func TestStandupDays(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.workspace.ReportingTime = ""
+	clk := clock.NewMock(time.Date(2019, 3, 8, 0, 0, 0, 0, time.UTC))
+	bot.SetClock(clk)
+
+	for _, id := range []string{"U1", "U2"} {
+		messenger.users[id] = User{ID: id, TZ: "America/New_York", TZOffset: -5 * 3600}
+		_, err := bot.db.CreateStanduper(model.Standuper{
+			WorkspaceID: "testTeam",
+			ChannelID:   "CHAN1",
+			UserID:      id,
+		})
+		require.NoError(t, err)
+	}
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "America/New_York",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday",
+	})
+	require.NoError(t, err)
+
+	var sent []string
+	for end := time.Date(2019, 3, 12, 0, 0, 0, 0, time.UTC); clk.Now().Before(end); clk.Add(time.Minute) {
+		// U1 writes standup on Monday before the warning
+		if clk.Now().Equal(time.Date(2019, 3, 11, 13, 30, 0, 0, time.UTC)) {
+			err := bot.HandleMessage(&slack.MessageEvent{Msg: slack.Msg{
+				Channel:   "CHAN1",
+				User:      "U1",
+				Text:      "<@BOT> yesterday fixed bugs, today write tests, no issues",
+				Timestamp: "1.1",
+			}})
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, bot.runScheduler(clk.Now()))
+		for _, m := range messenger.flush() {
+			sent = append(sent, fmt.Sprintf("%v %v", clk.Now().Format("Mon 15:04"), m.Text))
+		}
+	}
+
+	assert.Equal(t, []string{
+		// Friday, EST (UTC-5)
+		"Fri 14:50 <@U1>, <@U2> you may miss the deadline in 10 minutes",
+		"Fri 15:00 <@U1>, <@U2> you have missed standup deadlines, shame!",
+		"Fri 15:01 <@U1>,<@U2> you still haven't written a standup! Write a standup!",
+		"Fri 15:02 <@U1>,<@U2> you still haven't written a standup! Write a standup!",
+		"Fri 15:03 <@U1>,<@U2> you still haven't written a standup! Write a standup!",
+		// Monday, EDT (UTC-4)
+		"Mon 13:50 <@U2>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Mon 14:00 <@U2>, you are the only one missed standup, shame!",
+		"Mon 14:01 <@U2>, you still haven't written a standup! Write a standup!",
+		"Mon 14:02 <@U2>, you still haven't written a standup! Write a standup!",
+		"Mon 14:03 <@U2>, you still haven't written a standup! Write a standup!",
+	}, sent)
+}
+
+func TestLeaderElection(t *testing.T) {
+	clk := clock.NewMock(time.Date(2019, 11, 4, 9, 0, 0, 0, time.UTC))
+
+	replica := func(id string, db storage.Store) (*Bot, *recordingMessenger) {
+		bot, messenger := newTestBot()
+		bot.conf.ReplicaID = id
+		bot.conf.LeaseTTL = 30
+		bot.workspace.ReportingTime = ""
+		bot.SetClock(clk)
+		if db != nil {
+			bot.db = db
+		}
+		messenger.users["U1"] = User{ID: "U1", TZ: "UTC"}
+		return bot, messenger
+	}
+
+	first, firstMessenger := replica("replica-1", nil)
+	second, secondMessenger := replica("replica-2", first.db)
+
+	_, err := first.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "UTC",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday",
+	})
+	require.NoError(t, err)
+	_, err = first.db.CreateStanduper(model.Standuper{
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN1",
+		UserID:      "U1",
+	})
+	require.NoError(t, err)
+
+	// both replicas tick, only the leader posts the warning
+	for clk.Now().Before(time.Date(2019, 11, 4, 9, 55, 0, 0, time.UTC)) {
+		first.tick()
+		second.tick()
+		clk.Add(schedulerTick)
+	}
+	assert.True(t, first.Leader())
+	assert.False(t, second.Leader())
+	assert.Len(t, firstMessenger.flush(), 1)
+	assert.Empty(t, secondMessenger.flush())
+
+	// the leader dies, the other replica takes over once the lease expires
+	// and posts the alarm
+	for clk.Now().Before(time.Date(2019, 11, 4, 10, 0, 30, 0, time.UTC)) {
+		second.tick()
+		clk.Add(schedulerTick)
+	}
+	assert.True(t, second.Leader())
+	messages := secondMessenger.flush()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "<@U1>, you are the only one missed standup, shame!", messages[0].Text)
+
+	// a replica which took the lease while the former leader is still
+	// running the reminder does not post it again
+	clk.Add(time.Date(2019, 11, 4, 10, 1, 0, 0, time.UTC).Sub(clk.Now()))
+	firstMessenger.onPost = func() {
+		firstMessenger.onPost = nil
+		assert.NoError(t, second.runScheduler(clk.Now()))
+	}
+	require.NoError(t, first.runScheduler(clk.Now()))
+	assert.Empty(t, secondMessenger.flush())
+	messages = firstMessenger.flush()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "<@U1>, you still haven't written a standup! Write a standup!", messages[0].Text)
+
+	// stopped leader has given the lease up once Stop returns
+	second.Start()
+	second.Stop()
+	leases, err := second.db.ListLeases()
+	require.NoError(t, err)
+	assert.Empty(t, leases)
+
+	first.tick()
+	assert.True(t, first.Leader())
+}
@@ -0,0 +1,243 @@
+package botuser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// mentionPattern matches escaped user mention like <@U1234|john>
+var mentionPattern = regexp.MustCompile(`^<@([A-Za-z0-9]+)(\|[^>]*)?>$`)
+
+// standuperSchedule returns the channel with its schedule replaced by the
+// individual schedule of the standuper where one is set
+func standuperSchedule(channel model.Project, standuper model.Standuper) model.Project {
+	if standuper.Deadline != "" {
+		channel.Deadline = standuper.Deadline
+	}
+	if standuper.TZ != "" {
+		channel.TZ = standuper.TZ
+	}
+	if standuper.SubmissionDays != "" {
+		channel.SubmissionDays = standuper.SubmissionDays
+	}
+	return channel
+}
+
+// modifySchedule sets individual deadline, time zone or submission days of
+// the standuper in the channel, e.g. "/schedule @john days monday, wednesday".
+// Empty value returns the setting to the channel one, empty text shows
+// the schedule
+func (bot *Bot) modifySchedule(command slack.SlashCommand) string {
+	userID := command.UserID
+	fields := strings.Fields(command.Text)
+	if len(fields) > 0 {
+		if m := mentionPattern.FindStringSubmatch(fields[0]); m != nil {
+			userID = m[1]
+			fields = fields[1:]
+		}
+	}
+
+	standuper, err := bot.db.FindStansuperByUserID(userID, command.ChannelID)
+	if err != nil {
+		notStanduper, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "scheduleNotStanduper",
+				Other: "<@{{.User}}> does not submit standups in this channel",
+			},
+			TemplateData: map[string]interface{}{"User": userID},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return notStanduper
+	}
+
+	if len(fields) == 0 {
+		return bot.describeSchedule(standuper)
+	}
+
+	value := strings.TrimSpace(strings.Join(fields[1:], " "))
+
+	switch strings.ToLower(fields[0]) {
+	case "deadline":
+		if value != "" {
+			hour, minute, err := bot.parseClock(value)
+			if err != nil {
+				wrongDeadlineFormat, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+					DefaultMessage: &i18n.Message{
+						ID:    "wrongDeadlineFormat",
+						Other: "Could not recognize deadline time. Use 1pm or 13:00 formats",
+					},
+				})
+				if err != nil {
+					log.Error(err)
+				}
+				return wrongDeadlineFormat
+			}
+			value = time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC).Format(model.SlotDeadlineLayout)
+		}
+		standuper.Deadline = value
+	case "tz":
+		if value != "" {
+			_, err := time.LoadLocation(value)
+			if err != nil {
+				msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+					DefaultMessage: &i18n.Message{
+						ID:    "failedRecognizeTZ",
+						Other: "Failed to recognize new TZ you entered, double check the tz name and try again",
+					},
+				})
+				if err != nil {
+					log.Error(err)
+				}
+				return msg
+			}
+		}
+		standuper.TZ = value
+	case "days":
+		days, err := model.NormalizeWeekdays(value)
+		if err != nil {
+			return bot.wrongSubmittionDays(err)
+		}
+		standuper.SubmissionDays = days
+	case "dm":
+		offset, err := parseDMOffset(value)
+		if err != nil {
+			wrongDMOffset, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "wrongDMOffset",
+					Other: "Could not recognize the time before the deadline. Use 30m, 1h or off formats",
+				},
+			})
+			if err != nil {
+				log.Error(err)
+			}
+			return wrongDMOffset
+		}
+		standuper.DMStandupOffset = offset
+	case "reset":
+		standuper.Deadline = ""
+		standuper.TZ = ""
+		standuper.SubmissionDays = ""
+		standuper.DMStandupOffset = 0
+	default:
+		scheduleUsage, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "scheduleUsage",
+				Other: "Use /schedule [@user] deadline 11am, /schedule [@user] tz Europe/Berlin, /schedule [@user] days monday, wednesday, /schedule [@user] dm 30m or /schedule [@user] reset",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return scheduleUsage
+	}
+
+	standuper, err = bot.db.UpdateStanduper(standuper)
+	if err != nil {
+		log.Error("UpdateStanduper failed: ", err)
+		scheduleNotSet, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "scheduleNotSet",
+				Other: "Could not change individual schedule",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return scheduleNotSet
+	}
+
+	return bot.describeSchedule(standuper)
+}
+
+func (bot *Bot) describeSchedule(standuper model.Standuper) string {
+	schedule := bot.describeDeadline(standuper)
+	if standuper.DMStandupOffset == 0 {
+		return schedule
+	}
+
+	dmStandup, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "dmStandupSchedule",
+			Other: "Standup questions are sent in direct messages {{.Offset}} minutes before the deadline",
+		},
+		TemplateData: map[string]interface{}{"Offset": standuper.DMStandupOffset},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return schedule + "\n" + dmStandup
+}
+
+// parseDMOffset returns minutes of "30", "30m" or "1h30m", "off" turns DM
+// standups off
+func parseDMOffset(text string) (int64, error) {
+	text = strings.ToLower(text)
+	if text == "off" {
+		return 0, nil
+	}
+	if _, err := strconv.Atoi(text); err == nil {
+		text += "m"
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, err
+	}
+	if d < time.Minute || d >= 24*time.Hour {
+		return 0, fmt.Errorf("%v is out of range", d)
+	}
+	return int64(d / time.Minute), nil
+}
+
+func (bot *Bot) describeDeadline(standuper model.Standuper) string {
+	if !standuper.HasSchedule() {
+		channelSchedule, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "followsChannelSchedule",
+				Other: "<@{{.User}}> follows the channel schedule",
+			},
+			TemplateData: map[string]interface{}{"User": standuper.UserID},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return channelSchedule
+	}
+
+	channel, err := bot.db.SelectProject(standuper.ChannelID)
+	if err != nil {
+		log.Error("describeSchedule SelectProject failed: ", err)
+	}
+	schedule := standuperSchedule(channel, standuper)
+
+	deadline := schedule.Deadline
+	if deadline == "" {
+		deadline = "-"
+	}
+
+	individualSchedule, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "individualSchedule",
+			Other: "<@{{.User}}> submits standups on {{.SD}} no later than {{.Deadline}} in {{.TZ}} timezone",
+		},
+		TemplateData: map[string]interface{}{
+			"User":     standuper.UserID,
+			"SD":       schedule.SubmissionDays,
+			"Deadline": deadline,
+			"TZ":       schedule.TZ,
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return individualSchedule
+}
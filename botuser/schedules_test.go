@@ -0,0 +1,145 @@
+package botuser
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/model"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifySchedule(t *testing.T) {
+	bot, _ := newTestBot()
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10am",
+		TZ:             "Asia/Bishkek",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday",
+	})
+	require.NoError(t, err)
+	for _, id := range []string{"U1", "U2"} {
+		_, err := bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "CHAN1", UserID: id})
+		require.NoError(t, err)
+	}
+
+	schedule := func(text string) string {
+		return bot.ImplementCommands(slack.SlashCommand{
+			Command:   "/schedule",
+			Text:      text,
+			TeamID:    "testTeam",
+			ChannelID: "CHAN1",
+			UserID:    "U1",
+		})
+	}
+
+	testCases := []struct {
+		text     string
+		expected string
+	}{
+		{"", "<@U1> follows the channel schedule"},
+		{"deadline 11am", "<@U1> submits standups on monday, tuesday, wednesday, thursday, friday no later than 11:00 in Asia/Bishkek timezone"},
+		{"deadline never", "Could not recognize deadline time. Use 1pm or 13:00 formats"},
+		{"tz Mars/Olympus", "Failed to recognize new TZ you entered, double check the tz name and try again"},
+		{"tz Europe/Berlin", "<@U1> submits standups on monday, tuesday, wednesday, thursday, friday no later than 11:00 in Europe/Berlin timezone"},
+		{"<@U2|john> days Monday, Wednesday", "<@U2> submits standups on monday, wednesday no later than 10am in Asia/Bishkek timezone"},
+		{"<@U3|jane> days monday", "<@U3> does not submit standups in this channel"},
+		{"vacation", "Use /schedule [@user] deadline 11am, /schedule [@user] tz Europe/Berlin, /schedule [@user] days monday, wednesday, /schedule [@user] dm 30m or /schedule [@user] reset"},
+		{"deadline", "<@U1> submits standups on monday, tuesday, wednesday, thursday, friday no later than 10am in Europe/Berlin timezone"},
+		{"dm 30", "<@U1> submits standups on monday, tuesday, wednesday, thursday, friday no later than 10am in Europe/Berlin timezone\nStandup questions are sent in direct messages 30 minutes before the deadline"},
+		{"dm tomorrow", "Could not recognize the time before the deadline. Use 30m, 1h or off formats"},
+		{"dm 25h", "Could not recognize the time before the deadline. Use 30m, 1h or off formats"},
+		{"reset", "<@U1> follows the channel schedule"},
+		{"dm 1h", "<@U1> follows the channel schedule\nStandup questions are sent in direct messages 60 minutes before the deadline"},
+		{"dm off", "<@U1> follows the channel schedule"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, schedule(tc.text), tc.text)
+	}
+
+	s, err := bot.db.FindStansuperByUserID("U2", "CHAN1")
+	require.NoError(t, err)
+	assert.Equal(t, "monday, wednesday", s.SubmissionDays)
+}
+
+// TestIndividualSchedules runs the bot from Tuesday to Thursday in a channel
+// with a part-timer and a standuper with own deadline in another timezone
+func TestIndividualSchedules(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.workspace.ReportingTime = ""
+	bot.workspace.MaxReminders = 1
+	clk := clock.NewMock(time.Date(2019, 11, 5, 0, 0, 0, 0, time.UTC))
+	bot.SetClock(clk)
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "UTC",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday",
+	})
+	require.NoError(t, err)
+
+	standupers := []model.Standuper{
+		{UserID: "U1"},
+		{UserID: "U2", SubmissionDays: "monday, wednesday"},
+		{UserID: "U3", Deadline: "12:00", TZ: "Europe/Berlin"},
+	}
+	for _, s := range standupers {
+		messenger.users[s.UserID] = User{ID: s.UserID, TZ: "UTC"}
+		s.WorkspaceID = "testTeam"
+		s.ChannelID = "CHAN1"
+		_, err := bot.db.CreateStanduper(s)
+		require.NoError(t, err)
+	}
+
+	var sent []string
+	for end := time.Date(2019, 11, 7, 0, 0, 0, 0, time.UTC); clk.Now().Before(end); clk.Add(time.Minute) {
+		require.NoError(t, bot.runScheduler(clk.Now()))
+
+		var texts []string
+		for _, m := range messenger.flush() {
+			texts = append(texts, fmt.Sprintf("%v %v", clk.Now().Format("Mon 15:04"), m.Text))
+		}
+		sort.Strings(texts)
+		sent = append(sent, texts...)
+	}
+
+	assert.Equal(t, []string{
+		// Tuesday, the part-timer U2 is off
+		"Tue 09:50 <@U1>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Tue 10:00 <@U1>, you are the only one missed standup, shame!",
+		"Tue 10:01 <@U1>, you still haven't written a standup! Write a standup!",
+		"Tue 10:50 <@U3>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Tue 11:00 <@U3>, you are the only one missed standup, shame!",
+		"Tue 11:01 <@U3>, you still haven't written a standup! Write a standup!",
+		// Wednesday
+		"Wed 09:50 <@U1>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Wed 09:50 <@U2>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Wed 10:00 <@U1>, you are the only one missed standup, shame!",
+		"Wed 10:00 <@U2>, you are the only one missed standup, shame!",
+		"Wed 10:01 <@U1>, you still haven't written a standup! Write a standup!",
+		"Wed 10:01 <@U2>, you still haven't written a standup! Write a standup!",
+		"Wed 10:50 <@U3>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Wed 11:00 <@U3>, you are the only one missed standup, shame!",
+		"Wed 11:01 <@U3>, you still haven't written a standup! Write a standup!",
+	}, sent)
+
+	// the day off of the part-timer does not cost points in report
+	clk.Set(time.Date(2019, 11, 6, 10, 0, 0, 0, time.UTC))
+	members, err := bot.db.ListProjectStandupers("CHAN1")
+	require.NoError(t, err)
+	points := map[string]int{}
+	for _, m := range members {
+		_, points[m.UserID] = bot.processStandup(m)
+	}
+	assert.Equal(t, map[string]int{"U1": 0, "U2": 1, "U3": 0}, points)
+}
@@ -0,0 +1,278 @@
+package botuser
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// parseSlot returns deadline and days of the slot. Empty days of the slot
+// mean every day
+func parseSlot(slot model.DeadlineSlot) (int, int, model.Weekdays, error) {
+	deadline, err := time.Parse(model.SlotDeadlineLayout, slot.Deadline)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("could not recognize deadline %q", slot.Deadline)
+	}
+	if strings.TrimSpace(slot.Days) == "" {
+		return deadline.Hour(), deadline.Minute(), model.AllWeekdays, nil
+	}
+	days, err := model.ParseWeekdays(slot.Days)
+	return deadline.Hour(), deadline.Minute(), days, err
+}
+
+// slotWindowStart returns the time since which standups count for the slot
+// deadline which t belongs to: the latest earlier deadline of other slots
+// of the channel on that day. Zero time means standups of the whole day
+// count, like for channels without slots
+func (bot *Bot) slotWindowStart(channel model.Project, slotID int64, t time.Time) time.Time {
+	if slotID == 0 {
+		return time.Time{}
+	}
+	loc := location(channel.TZ)
+	slot, err := bot.db.GetDeadlineSlot(slotID)
+	if err != nil {
+		log.Error("GetDeadlineSlot failed: ", err)
+		return time.Time{}
+	}
+	hour, minute, _, err := parseSlot(slot)
+	if err != nil {
+		return time.Time{}
+	}
+
+	// reminders of late slots may run after midnight
+	day := t.In(loc)
+	deadline := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+	if deadline.After(t) {
+		deadline = deadline.AddDate(0, 0, -1)
+	}
+
+	slots, err := bot.db.ListChannelDeadlineSlots(channel.ChannelID)
+	if err != nil {
+		log.Error("ListChannelDeadlineSlots failed: ", err)
+		return time.Time{}
+	}
+
+	var start time.Time
+	for _, other := range slots {
+		hour, minute, days, err := parseSlot(other)
+		if other.ID == slotID || err != nil || !days.Has(deadline.Weekday()) {
+			continue
+		}
+		earlier := time.Date(deadline.Year(), deadline.Month(), deadline.Day(), hour, minute, 0, 0, loc)
+		if earlier.Before(deadline) && earlier.After(start) {
+			start = earlier
+		}
+	}
+	return start
+}
+
+// submittedStandup tells if the user has submitted standup in the channel
+// since the given time, or today when it is zero
+func (bot *Bot) submittedStandup(userID, channelID string, since time.Time) bool {
+	if since.IsZero() {
+		return bot.submittedStandupToday(userID, channelID)
+	}
+	standup, err := bot.db.SelectLatestStandupByUser(userID, channelID)
+	if err != nil {
+		return false
+	}
+	return standup.CreatedAt >= since.Unix()
+}
+
+// selectNotificationThread returns notification thread of the channel
+// schedule, of the standuper with individual schedule or of the slot
+func (bot *Bot) selectNotificationThread(channelID, userID string, slotID int64) (model.NotificationThread, error) {
+	if slotID != 0 {
+		return bot.db.SelectSlotNotificationsThread(channelID, slotID)
+	}
+	return bot.db.SelectUserNotificationsThread(channelID, userID)
+}
+
+// manageSlots lists deadline slots of the channel, adds or removes them, e.g.
+// "/slots add 10:00 mon-fri morning" or "/slots remove 2"
+func (bot *Bot) manageSlots(command slack.SlashCommand) string {
+	fields := strings.Fields(command.Text)
+	if len(fields) == 0 {
+		return bot.listSlots(command.ChannelID)
+	}
+
+	channel, err := bot.db.SelectProject(command.ChannelID)
+	if err != nil {
+		return bot.slotsNotChanged()
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "add":
+		if len(fields) < 2 {
+			return bot.slotsUsage()
+		}
+		hour, minute, err := bot.parseClock(fields[1])
+		if err != nil {
+			wrongDeadlineFormat, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "wrongDeadlineFormat",
+					Other: "Could not recognize deadline time. Use 1pm or 13:00 formats",
+				},
+			})
+			if err != nil {
+				log.Error(err)
+			}
+			return wrongDeadlineFormat
+		}
+
+		// days go first and the rest is the name of the slot
+		rest := fields[2:]
+		split := 0
+		for i := len(rest); i > 0; i-- {
+			if _, err := model.ParseWeekdays(strings.Join(rest[:i], " ")); err == nil {
+				split = i
+				break
+			}
+		}
+		days, err := model.NormalizeWeekdays(strings.Join(rest[:split], " "))
+		if err != nil {
+			return bot.wrongSubmittionDays(err)
+		}
+
+		_, err = bot.db.CreateDeadlineSlot(model.DeadlineSlot{
+			CreatedAt:   bot.clock.Now().Unix(),
+			WorkspaceID: channel.WorkspaceID,
+			ChannelID:   channel.ChannelID,
+			Name:        strings.Join(rest[split:], " "),
+			Days:        days,
+			Deadline:    time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC).Format(model.SlotDeadlineLayout),
+		})
+		if err != nil {
+			log.Error("CreateDeadlineSlot failed: ", err)
+			return bot.slotsNotChanged()
+		}
+
+	case "remove":
+		slots, err := bot.db.ListChannelDeadlineSlots(channel.ChannelID)
+		if err != nil {
+			log.Error("ListChannelDeadlineSlots failed: ", err)
+			return bot.slotsNotChanged()
+		}
+		if len(fields) != 2 {
+			return bot.slotsUsage()
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 1 || n > len(slots) {
+			return bot.slotsUsage()
+		}
+		err = bot.db.DeleteDeadlineSlot(slots[n-1].ID)
+		if err != nil {
+			log.Error("DeleteDeadlineSlot failed: ", err)
+			return bot.slotsNotChanged()
+		}
+
+	case "clear":
+		slots, err := bot.db.ListChannelDeadlineSlots(channel.ChannelID)
+		if err != nil {
+			log.Error("ListChannelDeadlineSlots failed: ", err)
+			return bot.slotsNotChanged()
+		}
+		for _, slot := range slots {
+			err = bot.db.DeleteDeadlineSlot(slot.ID)
+			if err != nil && err != sql.ErrNoRows {
+				log.Error("DeleteDeadlineSlot failed: ", err)
+				return bot.slotsNotChanged()
+			}
+		}
+
+	default:
+		return bot.slotsUsage()
+	}
+
+	return bot.listSlots(channel.ChannelID)
+}
+
+func (bot *Bot) listSlots(channelID string) string {
+	slots, err := bot.db.ListChannelDeadlineSlots(channelID)
+	if err != nil {
+		log.Error("ListChannelDeadlineSlots failed: ", err)
+	}
+	if len(slots) == 0 {
+		noSlots, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "noDeadlineSlots",
+				Other: "The channel has no deadline slots, standups are due by the channel deadline",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return noSlots
+	}
+	return bot.describeSlots(slots)
+}
+
+// describeSlots returns numbered list of the slots, numbers are used to
+// remove slots
+func (bot *Bot) describeSlots(slots []model.DeadlineSlot) string {
+	header, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "showDeadlineSlots",
+			Other: "Standup deadlines are:",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	everyDay, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "slotEverySubmissionDay",
+			Other: "every submission day",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	lines := []string{header}
+	for i, slot := range slots {
+		days := slot.Days
+		if days == "" {
+			days = everyDay
+		}
+		line := fmt.Sprintf("%v. %v on %v", i+1, slot.Deadline, days)
+		if slot.Name != "" {
+			line += " (" + slot.Name + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (bot *Bot) slotsUsage() string {
+	slotsUsage, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "slotsUsage",
+			Other: "Use /slots add 10:00 [mon-fri] [name] to add a deadline, /slots remove 2 to remove the second one or /slots clear to return to the channel deadline",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return slotsUsage
+}
+
+func (bot *Bot) slotsNotChanged() string {
+	slotsNotChanged, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "slotsNotChanged",
+			Other: "Could not change deadline slots",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return slotsNotChanged
+}
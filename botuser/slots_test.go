@@ -0,0 +1,144 @@
+package botuser
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/model"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManageSlots(t *testing.T) {
+	bot, _ := newTestBot()
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10am",
+		TZ:             "UTC",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday",
+	})
+	require.NoError(t, err)
+
+	slots := func(text string) string {
+		return bot.ImplementCommands(slack.SlashCommand{
+			Command:   "/slots",
+			Text:      text,
+			TeamID:    "testTeam",
+			ChannelID: "CHAN1",
+			UserID:    "U1",
+		})
+	}
+
+	usage := "Use /slots add 10:00 [mon-fri] [name] to add a deadline, /slots remove 2 to remove the second one or /slots clear to return to the channel deadline"
+
+	testCases := []struct {
+		text     string
+		expected string
+	}{
+		{"", "The channel has no deadline slots, standups are due by the channel deadline"},
+		{"add 10am tue-fri morning standup", "Standup deadlines are:\n1. 10:00 on tuesday, wednesday, thursday, friday (morning standup)"},
+		{"add 12:00 Monday", "Standup deadlines are:\n1. 10:00 on tuesday, wednesday, thursday, friday (morning standup)\n2. 12:00 on monday"},
+		{"add 17:00 check-in", "Standup deadlines are:\n1. 10:00 on tuesday, wednesday, thursday, friday (morning standup)\n2. 12:00 on monday\n3. 17:00 on every submission day (check-in)"},
+		{"add never", "Could not recognize deadline time. Use 1pm or 13:00 formats"},
+		{"add", usage},
+		{"remove 4", usage},
+		{"remove 2", "Standup deadlines are:\n1. 10:00 on tuesday, wednesday, thursday, friday (morning standup)\n2. 17:00 on every submission day (check-in)"},
+		{"move 1", usage},
+		{"clear", "The channel has no deadline slots, standups are due by the channel deadline"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, slots(tc.text), tc.text)
+	}
+}
+
+// TestDeadlineSlots runs the bot from Monday to Tuesday in a channel with
+// a late deadline on Mondays, a morning deadline on other days and
+// an evening check-in every day
+func TestDeadlineSlots(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.workspace.ReportingTime = ""
+	bot.workspace.MaxReminders = 1
+	clk := clock.NewMock(time.Date(2019, 11, 4, 0, 0, 0, 0, time.UTC))
+	bot.SetClock(clk)
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "UTC",
+		SubmissionDays: "monday, tuesday, wednesday, thursday, friday",
+	})
+	require.NoError(t, err)
+	for _, slot := range []model.DeadlineSlot{
+		{Deadline: "12:00", Days: "monday"},
+		{Deadline: "10:00", Days: "tuesday, wednesday, thursday, friday"},
+		{Deadline: "17:00", Name: "check-in"},
+	} {
+		slot.WorkspaceID = "testTeam"
+		slot.ChannelID = "CHAN1"
+		_, err := bot.db.CreateDeadlineSlot(slot)
+		require.NoError(t, err)
+	}
+
+	for _, id := range []string{"U1", "U2"} {
+		messenger.users[id] = User{ID: id, TZ: "UTC"}
+		_, err := bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "CHAN1", UserID: id})
+		require.NoError(t, err)
+	}
+
+	// U1 writes standups in the morning, U2 only in the evening
+	standups := map[string]string{
+		"Mon 11:00": "U1",
+		"Mon 16:00": "U2",
+		"Tue 09:00": "U1",
+	}
+
+	var sent []string
+	for end := time.Date(2019, 11, 6, 0, 0, 0, 0, time.UTC); clk.Now().Before(end); clk.Add(time.Minute) {
+		if userID, ok := standups[clk.Now().Format("Mon 15:04")]; ok {
+			_, err := bot.db.CreateStandup(model.Standup{
+				CreatedAt:   clk.Now().Unix(),
+				WorkspaceID: "testTeam",
+				ChannelID:   "CHAN1",
+				UserID:      userID,
+				MessageTS:   clk.Now().Format("Mon 15:04"),
+			})
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, bot.runScheduler(clk.Now()))
+
+		var texts []string
+		for _, m := range messenger.flush() {
+			texts = append(texts, fmt.Sprintf("%v %v", clk.Now().Format("Mon 15:04"), m.Text))
+		}
+		sort.Strings(texts)
+		sent = append(sent, texts...)
+	}
+
+	assert.Equal(t, []string{
+		// Monday, the channel deadline is replaced by the late slot
+		"Mon 11:50 <@U2>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Mon 12:00 <@U2>, you are the only one missed standup, shame!",
+		"Mon 12:01 <@U2>, you still haven't written a standup! Write a standup!",
+		// standup of U1 was written before the previous deadline
+		"Mon 16:50 <@U1>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Mon 17:00 <@U1>, you are the only one missed standup, shame!",
+		"Mon 17:01 <@U1>, you still haven't written a standup! Write a standup!",
+		// Tuesday
+		"Tue 09:50 <@U2>, you are the only one to miss standup, in 10 minutes, hurry up!",
+		"Tue 10:00 <@U2>, you are the only one missed standup, shame!",
+		"Tue 10:01 <@U2>, you still haven't written a standup! Write a standup!",
+		"Tue 16:50 <@U1>, <@U2> you may miss the deadline in 10 minutes",
+		"Tue 17:00 <@U1>, <@U2> you have missed standup deadlines, shame!",
+		"Tue 17:01 <@U1>,<@U2> you still haven't written a standup! Write a standup!",
+	}, sent)
+}
@@ -0,0 +1,248 @@
+package botuser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+const (
+	// standupCallbackID marks submissions of the standup modal
+	standupCallbackID = "standup"
+	answerActionID    = "answer"
+)
+
+// standupModalMetadata is kept in the modal, so the submission is saved
+// with the questions the user saw even if the template changed meanwhile
+type standupModalMetadata struct {
+	ChannelID string                 `json:"channel_id"`
+	Questions []model.StandupSection `json:"questions"`
+}
+
+func questionBlockID(position int) string {
+	return fmt.Sprintf("question_%v", position)
+}
+
+// standupCommand opens a modal with a question per section of the channel
+// standup template
+func (bot *Bot) standupCommand(command slack.SlashCommand) string {
+	channel, err := bot.db.SelectProject(command.ChannelID)
+	if err != nil {
+		noStandups, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "noStandupsInChannel",
+				Other: "Standups are not collected in this channel",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return noStandups
+	}
+
+	template, _ := bot.standupTemplate(channel.ChannelID)
+	view, err := bot.standupModal(channel, template)
+	if err == nil {
+		err = bot.messenger.OpenView(command.TriggerID, view)
+	}
+	if err != nil {
+		log.Error("OpenView failed: ", err)
+		modalFailed, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "standupModalFailed",
+				Other: "Could not open standup form, post your standup as a message mentioning the bot",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return modalFailed
+	}
+	return ""
+}
+
+func (bot *Bot) standupModal(channel model.Project, template model.StandupTemplate) (slack.ModalViewRequest, error) {
+	meta := standupModalMetadata{ChannelID: channel.ChannelID}
+	blocks := []slack.Block{}
+	for i, section := range template {
+		meta.Questions = append(meta.Questions, model.StandupSection{Name: section.Name, Required: section.Required})
+
+		input := slack.NewPlainTextInputBlockElement(nil, answerActionID)
+		input.Multiline = true
+		block := slack.NewInputBlock(questionBlockID(i), slack.NewTextBlockObject(slack.PlainTextType, section.Name, false, false), input)
+		block.Optional = !section.Required
+		blocks = append(blocks, block)
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return slack.ModalViewRequest{}, err
+	}
+
+	title, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "standupModalTitle",
+			Other: "Standup in #{{.Channel}}",
+		},
+		TemplateData: map[string]interface{}{
+			"Channel": channel.ChannelName,
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	// Slack cuts titles of modals at 24 characters
+	if runes := []rune(title); len(runes) > 24 {
+		title = string(runes[:23]) + "…"
+	}
+	submit, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "standupModalSubmit",
+			Other: "Post",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      standupCallbackID,
+		Title:           slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+		Submit:          slack.NewTextBlockObject(slack.PlainTextType, submit, false, false),
+		Blocks:          slack.Blocks{BlockSet: blocks},
+		PrivateMetadata: string(data),
+	}, nil
+}
+
+// HandleViewSubmission posts the standup submitted with the modal to the
+// channel and saves it with answers to every question. Only standupers of
+// the channel who have not posted a standup today may submit it. Returned
+// response keeps the modal open with errors, nil closes it
+func (bot *Bot) HandleViewSubmission(callback *slack.InteractionCallback) (*slack.ViewSubmissionResponse, error) {
+	if callback.View.CallbackID != standupCallbackID {
+		return nil, nil
+	}
+
+	var meta standupModalMetadata
+	err := json.Unmarshal([]byte(callback.View.PrivateMetadata), &meta)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = bot.db.FindStansuperByUserID(callback.User.ID, meta.ChannelID)
+	if err != nil {
+		return bot.standupModalError(0, &i18n.Message{
+			ID:    "notStanduper",
+			Other: "You do not standup yet",
+		}), nil
+	}
+	if bot.submittedStandupToday(callback.User.ID, meta.ChannelID) {
+		return bot.standupModalError(0, &i18n.Message{
+			ID:    "standupModalSubmitted",
+			Other: "You have already posted a standup today",
+		}), nil
+	}
+
+	answers := []model.StandupAnswer{}
+	for i, question := range meta.Questions {
+		var value string
+		if callback.View.State != nil {
+			value = strings.TrimSpace(callback.View.State.Values[questionBlockID(i)][answerActionID].Value)
+		}
+		if value == "" {
+			if question.Required {
+				return bot.standupModalError(i, &i18n.Message{
+					ID:    "standupModalRequired",
+					Other: "Please answer this question",
+				}), nil
+			}
+			continue
+		}
+		answers = append(answers, model.StandupAnswer{Position: i, Question: question.Name, Answer: value})
+	}
+	if len(answers) == 0 {
+		return bot.standupModalError(0, &i18n.Message{
+			ID:    "standupModalEmpty",
+			Other: "Please answer at least one question",
+		}), nil
+	}
+
+	// the standup is saved before it is posted, so a failed post does not
+	// keep the modal open for a submission which would post it twice
+	_, err = bot.saveStandup(meta.ChannelID, callback.User.ID, "view:"+callback.View.ID, answers)
+	if err != nil {
+		return nil, err
+	}
+	err = bot.postStandup(meta.ChannelID, callback.User.ID, answers)
+	if err != nil {
+		log.Error("postStandup failed: ", err)
+	}
+	return nil, nil
+}
+
+// postStandup sends answers of the user to the channel through the outbox
+func (bot *Bot) postStandup(channelID, userID string, answers []model.StandupAnswer) error {
+	posted, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "standupPosted",
+			Other: "<@{{.User}}> posted a standup:\n{{.Standup}}",
+		},
+		TemplateData: map[string]interface{}{
+			"User":    userID,
+			"Standup": formatAnswers(answers),
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return bot.SendMessage(channelID, posted, nil)
+}
+
+// saveStandup saves the standup posted by postStandup with answers to
+// every question and blockers mentioned in them. The standup is posted by
+// the bot, so instead of a message of the user it is saved with the
+// reference to the form it came from, which never matches edited messages
+func (bot *Bot) saveStandup(channelID, userID, reference string, answers []model.StandupAnswer) (model.Standup, error) {
+	standup, err := bot.db.CreateStandup(model.Standup{
+		CreatedAt:   bot.clock.Now().Unix(),
+		WorkspaceID: bot.workspace.WorkspaceID,
+		ChannelID:   channelID,
+		UserID:      userID,
+		Comment:     formatAnswers(answers),
+		MessageTS:   reference,
+	})
+	if err != nil {
+		return standup, err
+	}
+	for _, a := range answers {
+		a.StandupID = standup.ID
+		_, err = bot.db.CreateStandupAnswer(a)
+		if err != nil {
+			return standup, err
+		}
+	}
+	return standup, bot.saveBlockers(standup, answers)
+}
+
+// formatAnswers turns answers into standup text with a bold question
+// above every answer
+func formatAnswers(answers []model.StandupAnswer) string {
+	parts := make([]string, 0, len(answers))
+	for _, a := range answers {
+		parts = append(parts, fmt.Sprintf("*%v*\n%v", a.Question, a.Answer))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (bot *Bot) standupModalError(position int, message *i18n.Message) *slack.ViewSubmissionResponse {
+	text, err := bot.localizer.Localize(&i18n.LocalizeConfig{DefaultMessage: message})
+	if err != nil {
+		log.Error(err)
+	}
+	return slack.NewErrorsViewSubmissionResponse(map[string]string{questionBlockID(position): text})
+}
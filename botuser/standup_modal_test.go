@@ -0,0 +1,147 @@
+package botuser
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/model"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandupModal(t *testing.T) {
+	bot, messenger := newTestBot()
+	clk := clock.NewMock(time.Date(2019, 11, 4, 9, 0, 0, 0, time.UTC))
+	bot.SetClock(clk)
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN1",
+		ChannelName: "general",
+		TZ:          "UTC",
+		StandupTemplate: model.StandupTemplate{
+			{Name: "Done", Keywords: []string{"done"}, Required: true},
+			{Name: "Doing", Keywords: []string{"doing"}, Required: true},
+			{Name: "Blocked", Keywords: []string{"blocked"}},
+		},
+	})
+	require.NoError(t, err)
+
+	command := func(channelID, triggerID string) string {
+		return bot.ImplementCommands(slack.SlashCommand{
+			Command:   "/standup",
+			TeamID:    "testTeam",
+			ChannelID: channelID,
+			UserID:    "U1",
+			TriggerID: triggerID,
+		})
+	}
+
+	assert.Equal(t, "Standups are not collected in this channel", command("CHAN404", "trigger"))
+	assert.Equal(t, "Could not open standup form, post your standup as a message mentioning the bot", command("CHAN1", ""))
+	assert.Equal(t, "", command("CHAN1", "trigger"))
+
+	require.Len(t, messenger.views, 1)
+	view := messenger.views[0]
+	assert.Equal(t, "Standup in #general", view.Title.Text)
+	assert.Equal(t, standupCallbackID, view.CallbackID)
+	require.Len(t, view.Blocks.BlockSet, 3)
+	labels := []string{}
+	for _, b := range view.Blocks.BlockSet {
+		input := b.(*slack.InputBlock)
+		labels = append(labels, input.Label.Text)
+		assert.Equal(t, input.Label.Text == "Blocked", input.Optional)
+	}
+	assert.Equal(t, []string{"Done", "Doing", "Blocked"}, labels)
+
+	// the template changes while the modal is open
+	channel, err := bot.db.SelectProject("CHAN1")
+	require.NoError(t, err)
+	channel.StandupTemplate = nil
+	_, err = bot.db.UpdateProject(channel)
+	require.NoError(t, err)
+
+	submit := func(values map[string]string) *slack.ViewSubmissionResponse {
+		state := &slack.ViewState{Values: map[string]map[string]slack.BlockAction{}}
+		for blockID, value := range values {
+			state.Values[blockID] = map[string]slack.BlockAction{answerActionID: {Value: value}}
+		}
+		response, err := bot.HandleViewSubmission(&slack.InteractionCallback{
+			Type: slack.InteractionTypeViewSubmission,
+			User: slack.User{ID: "U1"},
+			View: slack.View{
+				CallbackID:      view.CallbackID,
+				PrivateMetadata: view.PrivateMetadata,
+				State:           state,
+			},
+		})
+		require.NoError(t, err)
+		return response
+	}
+
+	// only standupers of the channel may submit
+	response := submit(map[string]string{"question_0": "fixed bugs", "question_1": "writing tests"})
+	require.NotNil(t, response)
+	assert.Equal(t, slack.RAErrors, response.ResponseAction)
+	assert.Equal(t, map[string]string{"question_0": "You do not standup yet"}, response.Errors)
+	assert.False(t, bot.submittedStandupToday("U1", "CHAN1"))
+
+	_, err = bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "CHAN1", UserID: "U1"})
+	require.NoError(t, err)
+
+	response = submit(map[string]string{"question_0": "fixed bugs", "question_1": " "})
+	require.NotNil(t, response)
+	assert.Equal(t, slack.RAErrors, response.ResponseAction)
+	assert.Equal(t, map[string]string{"question_1": "Please answer this question"}, response.Errors)
+	assert.Empty(t, messenger.flush())
+
+	// failed post is retried by the outbox, the modal is closed at once
+	bot.conf.OutboxMaxAttempts = 3
+	messenger.failures = []error{slack.ErrParametersMissing}
+	assert.Nil(t, submit(map[string]string{"question_0": "fixed bugs", "question_1": "writing tests"}))
+	assert.Empty(t, messenger.flush())
+	clk.Add(10 * time.Second)
+	bot.deliverOutbox(clk.Now())
+	messages := messenger.flush()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "CHAN1", messages[0].Channel)
+	assert.Equal(t, "<@U1> posted a standup:\n*Done*\nfixed bugs\n\n*Doing*\nwriting tests", messages[0].Text)
+
+	standup, err := bot.db.SelectLatestStandupByUser("U1", "CHAN1")
+	require.NoError(t, err)
+	assert.Equal(t, "U1", standup.UserID)
+	assert.Equal(t, "CHAN1", standup.ChannelID)
+	assert.Equal(t, "*Done*\nfixed bugs\n\n*Doing*\nwriting tests", standup.Comment)
+	assert.True(t, bot.submittedStandupToday("U1", "CHAN1"))
+
+	// the modal opened before the standup cannot post a second one
+	response = submit(map[string]string{"question_0": "fixed more bugs", "question_1": "writing more tests"})
+	require.NotNil(t, response)
+	assert.Equal(t, map[string]string{"question_0": "You have already posted a standup today"}, response.Errors)
+	assert.Empty(t, messenger.flush())
+	latest, err := bot.db.SelectLatestStandupByUser("U1", "CHAN1")
+	require.NoError(t, err)
+	assert.Equal(t, standup.ID, latest.ID)
+
+	answers, err := bot.db.ListStandupAnswers(standup.ID)
+	require.NoError(t, err)
+	for i := range answers {
+		answers[i].ID = 0
+	}
+	assert.Equal(t, []model.StandupAnswer{
+		{StandupID: standup.ID, Position: 0, Question: "Done", Answer: "fixed bugs"},
+		{StandupID: standup.ID, Position: 1, Question: "Doing", Answer: "writing tests"},
+	}, answers)
+
+	// submissions of other modals are not standups
+	response, err = bot.HandleViewSubmission(&slack.InteractionCallback{View: slack.View{CallbackID: "other"}})
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+
+	var meta standupModalMetadata
+	require.NoError(t, json.Unmarshal([]byte(view.PrivateMetadata), &meta))
+	assert.Equal(t, "CHAN1", meta.ChannelID)
+}
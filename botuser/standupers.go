@@ -3,7 +3,6 @@ package botuser
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/maddevsio/comedian/model"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
@@ -26,21 +25,20 @@ func (bot *Bot) joinCommand(command slack.SlashCommand) string {
 		return youAlreadyStandup
 	}
 
-	u, err := bot.slack.GetUserInfo(command.UserID)
+	u, err := bot.user(command.UserID)
 	if err != nil {
-		log.Error("joinCommand bot.slack.GetUserInfo failed: ", err)
-		u = &slack.User{RealName: command.UserName}
+		log.Error("joinCommand bot.user failed: ", err)
+		u = &User{RealName: command.UserName}
 	}
 
-	ch, err := bot.slack.GetConversationInfo(command.ChannelID, true)
+	ch, err := bot.messenger.GetConversationInfo(command.ChannelID)
 	if err != nil {
-		log.Error("joinCommand bot.slack.GetChannelInfo failed: ", err)
-		ch = &slack.Channel{}
-		ch.Name = command.ChannelName
+		log.Error("joinCommand bot.messenger.GetConversationInfo failed: ", err)
+		ch = &Channel{Name: command.ChannelName}
 	}
 
 	_, err = bot.db.CreateStanduper(model.Standuper{
-		CreatedAt:   time.Now().Unix(),
+		CreatedAt:   bot.clock.Now().Unix(),
 		WorkspaceID: command.TeamID,
 		UserID:      command.UserID,
 		ChannelID:   command.ChannelID,
@@ -65,7 +63,7 @@ func (bot *Bot) joinCommand(command slack.SlashCommand) string {
 	channel, err := bot.db.SelectProject(command.ChannelID)
 	if err != nil {
 		channel, err = bot.db.CreateProject(model.Project{
-			CreatedAt:        time.Now().Unix(),
+			CreatedAt:        bot.clock.Now().Unix(),
 			WorkspaceID:      command.TeamID,
 			ChannelID:        command.ChannelID,
 			ChannelName:      ch.Name,
@@ -108,15 +106,14 @@ func (bot *Bot) showCommand(command slack.SlashCommand) string {
 	var deadline, tz, submittionDays string
 	channel, err := bot.db.SelectProject(command.ChannelID)
 	if err != nil {
-		ch, err := bot.slack.GetChannelInfo(command.ChannelID)
+		ch, err := bot.messenger.GetConversationInfo(command.ChannelID)
 		if err != nil {
-			log.Error("Failed to GetChannelInfo in show command: ", err)
-			ch = &slack.Channel{}
-			ch.Name = command.ChannelName
+			log.Error("Failed to GetConversationInfo in show command: ", err)
+			ch = &Channel{Name: command.ChannelName}
 		}
 
 		channel, err = bot.db.CreateProject(model.Project{
-			CreatedAt:        time.Now().Unix(),
+			CreatedAt:        bot.clock.Now().Unix(),
 			WorkspaceID:      command.TeamID,
 			ChannelID:        command.ChannelID,
 			ChannelName:      ch.Name,
@@ -155,6 +152,15 @@ func (bot *Bot) showCommand(command slack.SlashCommand) string {
 		deadline = showStandupTime
 	}
 
+	// deadline slots replace the channel deadline
+	slots, err := bot.db.ListChannelDeadlineSlots(channel.ChannelID)
+	if err != nil {
+		log.Error("ListChannelDeadlineSlots failed: ", err)
+	}
+	if len(slots) > 0 {
+		deadline = bot.describeSlots(slots)
+	}
+
 	tz, err = bot.localizer.Localize(&i18n.LocalizeConfig{
 		DefaultMessage: &i18n.Message{
 			ID:    "showTZ",
@@ -166,7 +172,19 @@ func (bot *Bot) showCommand(command slack.SlashCommand) string {
 		log.Error(err)
 	}
 
-	if channel.SubmissionDays == "" {
+	days, daysErr := model.ParseWeekdays(channel.SubmissionDays)
+	if daysErr != nil {
+		submittionDays, err = bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "showWrongSubmittionDays",
+				Other: "Submittion days \"{{.SD}}\" are not recognized, no standups are expected. Set them with /submittion_days mon-fri",
+			},
+			TemplateData: map[string]interface{}{"SD": channel.SubmissionDays},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+	} else if days == 0 {
 		submittionDays, err = bot.localizer.Localize(&i18n.LocalizeConfig{
 			DefaultMessage: &i18n.Message{
 				ID:    "showNoSubmittionDays",
@@ -182,7 +200,7 @@ func (bot *Bot) showCommand(command slack.SlashCommand) string {
 				ID:    "showSubmittionDays",
 				Other: "Submit standups on {{.SD}}",
 			},
-			TemplateData: map[string]interface{}{"SD": channel.SubmissionDays},
+			TemplateData: map[string]interface{}{"SD": days.String()},
 		})
 		if err != nil {
 			log.Error(err)
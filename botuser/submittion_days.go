@@ -1,13 +1,17 @@
 package botuser
 
 import (
+	"github.com/maddevsio/comedian/model"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	log "github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
 )
 
 func (bot *Bot) modifySubmittionDays(command slack.SlashCommand) string {
-	submittionDays := command.Text
+	submittionDays, err := model.NormalizeWeekdays(command.Text)
+	if err != nil {
+		return bot.wrongSubmittionDays(err)
+	}
 
 	channel, err := bot.db.SelectProject(command.ChannelID)
 	if err != nil {
@@ -40,6 +44,19 @@ func (bot *Bot) modifySubmittionDays(command slack.SlashCommand) string {
 		return msg
 	}
 
+	if submittionDays == "" {
+		msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "clearSubmittionDays",
+				Other: "Channel submittion days are cleared, no standups are expected",
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		return msg
+	}
+
 	msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
 		DefaultMessage: &i18n.Message{
 			ID:    "updateSubmittionDays",
@@ -54,3 +71,17 @@ func (bot *Bot) modifySubmittionDays(command slack.SlashCommand) string {
 	}
 	return msg
 }
+
+func (bot *Bot) wrongSubmittionDays(parseErr error) string {
+	msg, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "wrongSubmittionDays",
+			Other: "Could not recognize submittion days: {{.Error}}. Use day names or ranges like monday, wednesday or mon-fri",
+		},
+		TemplateData: map[string]interface{}{"Error": parseErr},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return msg
+}
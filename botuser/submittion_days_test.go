@@ -0,0 +1,57 @@
+package botuser
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifySubmittionDays(t *testing.T) {
+	bot, _ := newTestBot()
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		TZ:             "UTC",
+		SubmissionDays: "monday",
+	})
+	require.NoError(t, err)
+	_, err = bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "CHAN1", UserID: "U1"})
+	require.NoError(t, err)
+
+	command := func(name, text string) string {
+		return bot.ImplementCommands(slack.SlashCommand{
+			Command:   name,
+			Text:      text,
+			TeamID:    "testTeam",
+			ChannelID: "CHAN1",
+			UserID:    "U1",
+		})
+	}
+
+	testCases := []struct {
+		text     string
+		expected string
+		days     string
+	}{
+		{"пн, вт", "Channel submittion days are updated, new schedule is monday, tuesday", "monday, tuesday"},
+		{"Mon - Fri", "Channel submittion days are updated, new schedule is monday, tuesday, wednesday, thursday, friday", "monday, tuesday, wednesday, thursday, friday"},
+		{"mon, funday", `Could not recognize submittion days: could not recognize day "funday". Use day names or ranges like monday, wednesday or mon-fri`, "monday, tuesday, wednesday, thursday, friday"},
+		{"sat-sun", "Channel submittion days are updated, new schedule is saturday, sunday", "saturday, sunday"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, command("/submittion_days", tc.text), tc.text)
+		channel, err := bot.db.SelectProject("CHAN1")
+		require.NoError(t, err)
+		assert.Equal(t, tc.days, channel.SubmissionDays, tc.text)
+	}
+
+	assert.Contains(t, command("/show", ""), "Submit standups on saturday, sunday")
+
+	assert.Equal(t, "Channel submittion days are cleared, no standups are expected", command("/submittion_days", ""))
+	assert.Contains(t, command("/show", ""), "No submittion days")
+}
@@ -0,0 +1,313 @@
+package botuser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// TelegramAPIURL is the default Telegram Bot API server
+const TelegramAPIURL = "https://api.telegram.org"
+
+var telegramMentionRegex = regexp.MustCompile(`&lt;@(-?\d+)&gt;`)
+
+// telegramReactions maps Slack emoji names bot uses to emoji Telegram
+// allows as message reactions
+var telegramReactions = map[string]string{
+	"heavy_check_mark": "👍",
+}
+
+// TelegramUser is a user or a bot as Telegram Bot API returns it
+type TelegramUser struct {
+	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Username  string `json:"username"`
+}
+
+// TelegramChat is a private chat, group or channel
+type TelegramChat struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// TelegramMessage is a message sent to the chat
+type TelegramMessage struct {
+	MessageID      int64          `json:"message_id"`
+	From           *TelegramUser  `json:"from"`
+	Chat           TelegramChat   `json:"chat"`
+	Date           int64          `json:"date"`
+	Text           string         `json:"text"`
+	Caption        string         `json:"caption"`
+	NewChatMembers []TelegramUser `json:"new_chat_members"`
+}
+
+// TelegramChatMemberUpdated is sent when bot membership in the chat changes
+type TelegramChatMemberUpdated struct {
+	Chat          TelegramChat `json:"chat"`
+	From          TelegramUser `json:"from"`
+	NewChatMember struct {
+		User   TelegramUser `json:"user"`
+		Status string       `json:"status"`
+	} `json:"new_chat_member"`
+}
+
+// TelegramUpdate is an incoming update Telegram posts to bot webhook
+type TelegramUpdate struct {
+	UpdateID      int64                      `json:"update_id"`
+	Message       *TelegramMessage           `json:"message"`
+	EditedMessage *TelegramMessage           `json:"edited_message"`
+	MyChatMember  *TelegramChatMemberUpdated `json:"my_chat_member"`
+}
+
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// TelegramClient is a minimal client of Telegram Bot API
+type TelegramClient struct {
+	apiURL string
+	token  string
+	http   *http.Client
+}
+
+// NewTelegramClient creates client for Telegram Bot API authenticated with bot token.
+// Empty apiURL means the official Telegram server
+func NewTelegramClient(apiURL, token string) *TelegramClient {
+	if apiURL == "" {
+		apiURL = TelegramAPIURL
+	}
+	return &TelegramClient{
+		apiURL: strings.TrimRight(apiURL, "/"),
+		token:  token,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GetMe returns the bot token belongs to
+func (c *TelegramClient) GetMe() (*TelegramUser, error) {
+	user := &TelegramUser{}
+	err := c.call("getMe", nil, user)
+	return user, err
+}
+
+// SetWebhook makes Telegram post updates to webhookURL with secret token in
+// X-Telegram-Bot-Api-Secret-Token header
+func (c *TelegramClient) SetWebhook(webhookURL, secretToken string) error {
+	return c.call("setWebhook", map[string]interface{}{
+		"url":             webhookURL,
+		"secret_token":    secretToken,
+		"allowed_updates": []string{"message", "edited_message", "my_chat_member"},
+	}, nil)
+}
+
+// GetChat returns up to date information about the chat or the user
+func (c *TelegramClient) GetChat(chatID string) (*TelegramChat, error) {
+	chat := &TelegramChat{}
+	err := c.call("getChat", map[string]string{"chat_id": chatID}, chat)
+	return chat, err
+}
+
+func (c *TelegramClient) call(method string, params, result interface{}) error {
+	var payload bytes.Buffer
+	if params == nil {
+		params = map[string]string{}
+	}
+	err := json.NewEncoder(&payload).Encode(params)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Post(fmt.Sprintf("%v/bot%v/%v", c.apiURL, c.token, method), "application/json", &payload)
+	if err != nil {
+		// url.Error carries the request URL, which contains the bot token
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram: %v: %v", method, err)
+	}
+	defer resp.Body.Close()
+
+	res := telegramResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return fmt.Errorf("telegram: %v: %v", method, resp.Status)
+	}
+	if res.ErrorCode == http.StatusTooManyRequests {
+		return &slack.RateLimitedError{RetryAfter: time.Duration(res.Parameters.RetryAfter) * time.Second}
+	}
+	if !res.OK {
+		return fmt.Errorf("telegram: %v: %v", method, res.Description)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(res.Result, result)
+}
+
+type telegramMessenger struct {
+	client *TelegramClient
+
+	mu    sync.Mutex
+	names map[string]string
+}
+
+// NewTelegramMessenger creates Messenger which talks to Telegram Bot API on
+// behalf of the bot. Chat ids are used as channel ids, message ids as
+// timestamps, and private chat with a user has the id of the user
+func NewTelegramMessenger(apiURL, botAccessToken string) Messenger {
+	return &telegramMessenger{
+		client: NewTelegramClient(apiURL, botAccessToken),
+		names:  map[string]string{},
+	}
+}
+
+func (t *telegramMessenger) PostMessage(channelID, text string, attachments []slack.Attachment) (string, error) {
+	text = t.format(text)
+	for _, a := range attachments {
+		text += "\n\n" + t.formatAttachment(a)
+	}
+
+	message := TelegramMessage{}
+	err := t.client.call("sendMessage", map[string]interface{}{
+		"chat_id":    channelID,
+		"text":       strings.TrimSpace(text),
+		"parse_mode": "HTML",
+	}, &message)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(message.MessageID, 10), nil
+}
+
+// PostEphemeral mentions the user in the chat, since Telegram has no
+// messages visible only for one member
+func (t *telegramMessenger) PostEphemeral(channelID, userID, text string) error {
+	_, err := t.PostMessage(channelID, fmt.Sprintf("<@%v> %v", userID, text), nil)
+	return err
+}
+
+func (t *telegramMessenger) OpenIMChannel(userID string) (string, error) {
+	return userID, nil
+}
+
+// IsDirectChannel tells private chats by the ID, which is the positive ID
+// of the user, while IDs of groups are negative
+func (t *telegramMessenger) IsDirectChannel(channelID string) (bool, error) {
+	return !strings.HasPrefix(channelID, "-"), nil
+}
+
+func (t *telegramMessenger) AddReaction(name, channelID, timestamp string) error {
+	emoji, ok := telegramReactions[name]
+	if !ok {
+		emoji = telegramReactions["heavy_check_mark"]
+	}
+	messageID, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("telegram: invalid message id %q", timestamp)
+	}
+	return t.client.call("setMessageReaction", map[string]interface{}{
+		"chat_id":    channelID,
+		"message_id": messageID,
+		"reaction":   []map[string]string{{"type": "emoji", "emoji": emoji}},
+	}, nil)
+}
+
+func (t *telegramMessenger) GetUserInfo(userID string) (*User, error) {
+	chat, err := t.client.GetChat(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &User{
+		ID:       userID,
+		Name:     chat.Username,
+		RealName: strings.TrimSpace(chat.FirstName + " " + chat.LastName),
+	}, nil
+}
+
+func (t *telegramMessenger) GetConversationInfo(channelID string) (*Channel, error) {
+	chat, err := t.client.GetChat(channelID)
+	if err != nil {
+		return nil, err
+	}
+	name := chat.Title
+	if name == "" {
+		name = chat.Username
+	}
+	return &Channel{ID: channelID, Name: name}, nil
+}
+
+// GetUsers returns no users: Telegram bots can not list members of chats
+func (t *telegramMessenger) GetUsers() ([]User, error) {
+	return []User{}, nil
+}
+
+func (t *telegramMessenger) OpenView(triggerID string, view slack.ModalViewRequest) error {
+	return errNoModals
+}
+
+// format escapes text for HTML parse mode and turns Slack style user
+// mentions into Telegram inline mentions
+func (t *telegramMessenger) format(text string) string {
+	return telegramMentionRegex.ReplaceAllStringFunc(html.EscapeString(text), func(mention string) string {
+		userID := telegramMentionRegex.FindStringSubmatch(mention)[1]
+		return fmt.Sprintf(`<a href="tg://user?id=%v">%v</a>`, userID, html.EscapeString(t.name(userID)))
+	})
+}
+
+func (t *telegramMessenger) formatAttachment(a slack.Attachment) string {
+	lines := []string{}
+	if a.Title != "" {
+		lines = append(lines, "<b>"+t.format(a.Title)+"</b>")
+	}
+	if a.Text != "" {
+		lines = append(lines, t.format(a.Text))
+	}
+	for _, f := range a.Fields {
+		lines = append(lines, fmt.Sprintf("%v: %v", t.format(f.Title), t.format(f.Value)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// name returns display name of the user, falling back to the id
+func (t *telegramMessenger) name(userID string) string {
+	t.mu.Lock()
+	name, ok := t.names[userID]
+	t.mu.Unlock()
+	if ok {
+		return name
+	}
+
+	name = userID
+	user, err := t.GetUserInfo(userID)
+	if err == nil && user.RealName != "" {
+		name = user.RealName
+	}
+
+	t.mu.Lock()
+	t.names[userID] = name
+	t.mu.Unlock()
+	return name
+}
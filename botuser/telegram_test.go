@@ -0,0 +1,147 @@
+package botuser
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type telegramCall struct {
+	Method string
+	Params map[string]interface{}
+}
+
+// mockTelegram is a local stand-in for Telegram Bot API
+type mockTelegram struct {
+	mu     sync.Mutex
+	calls  []telegramCall
+	server *httptest.Server
+}
+
+func newMockTelegram() *mockTelegram {
+	m := &mockTelegram{}
+	m.server = httptest.NewServer(http.HandlerFunc(m.handle))
+	return m
+}
+
+func (m *mockTelegram) handle(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/bottoken/") {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"ok":false,"error_code":401,"description":"Unauthorized"}`))
+		return
+	}
+	method := strings.TrimPrefix(r.URL.Path, "/bottoken/")
+
+	params := map[string]interface{}{}
+	json.NewDecoder(r.Body).Decode(&params)
+	m.mu.Lock()
+	m.calls = append(m.calls, telegramCall{Method: method, Params: params})
+	m.mu.Unlock()
+
+	switch method {
+	case "sendMessage":
+		w.Write([]byte(`{"ok":true,"result":{"message_id":7,"chat":{"id":-100}}}`))
+	case "getChat":
+		switch params["chat_id"] {
+		case "42":
+			w.Write([]byte(`{"ok":true,"result":{"id":42,"type":"private","first_name":"John","last_name":"Doe","username":"john"}}`))
+		case "-100":
+			w.Write([]byte(`{"ok":true,"result":{"id":-100,"type":"supergroup","title":"Backend"}}`))
+		default:
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+		}
+	default:
+		w.Write([]byte(`{"ok":true,"result":true}`))
+	}
+}
+
+func (m *mockTelegram) flush() []telegramCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls := m.calls
+	m.calls = nil
+	return calls
+}
+
+func TestTelegramMessenger(t *testing.T) {
+	tg := newMockTelegram()
+	defer tg.server.Close()
+
+	messenger := NewTelegramMessenger(tg.server.URL, "token")
+
+	id, err := messenger.PostMessage("-100", "<@42>, <@13> you missed standup & deadline", []slack.Attachment{{
+		Text:   "John Doe in #backend",
+		Fields: []slack.AttachmentField{{Title: "worklogs", Value: "8h"}},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "7", id)
+
+	calls := tg.flush()
+	require.Len(t, calls, 3)
+	assert.Equal(t, "getChat", calls[0].Method)
+	assert.Equal(t, "getChat", calls[1].Method)
+	assert.Equal(t, "sendMessage", calls[2].Method)
+	assert.Equal(t, "-100", calls[2].Params["chat_id"])
+	assert.Equal(t, "HTML", calls[2].Params["parse_mode"])
+	assert.Equal(t, `<a href="tg://user?id=42">John Doe</a>, <a href="tg://user?id=13">13</a> you missed standup &amp; deadline`+
+		"\n\nJohn Doe in #backend\nworklogs: 8h", calls[2].Params["text"])
+
+	// names are cached
+	err = messenger.PostEphemeral("-100", "42", "standup is incomplete")
+	require.NoError(t, err)
+	calls = tg.flush()
+	require.Len(t, calls, 1)
+	assert.Equal(t, `<a href="tg://user?id=42">John Doe</a> standup is incomplete`, calls[0].Params["text"])
+
+	err = messenger.AddReaction("heavy_check_mark", "-100", "7")
+	require.NoError(t, err)
+	calls = tg.flush()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "setMessageReaction", calls[0].Method)
+	assert.Equal(t, float64(7), calls[0].Params["message_id"])
+
+	dm, err := messenger.OpenIMChannel("42")
+	require.NoError(t, err)
+	assert.Equal(t, "42", dm)
+	direct, err := messenger.IsDirectChannel(dm)
+	require.NoError(t, err)
+	assert.True(t, direct)
+	direct, err = messenger.IsDirectChannel("-100")
+	require.NoError(t, err)
+	assert.False(t, direct)
+
+	user, err := messenger.GetUserInfo("42")
+	require.NoError(t, err)
+	assert.Equal(t, User{ID: "42", Name: "john", RealName: "John Doe"}, *user)
+
+	channel, err := messenger.GetConversationInfo("-100")
+	require.NoError(t, err)
+	assert.Equal(t, Channel{ID: "-100", Name: "Backend"}, *channel)
+
+	_, err = messenger.GetConversationInfo("-200")
+	assert.EqualError(t, err, "telegram: getChat: Bad Request: chat not found")
+
+	users, err := messenger.GetUsers()
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	_, err = NewTelegramMessenger(tg.server.URL, "wrong").PostMessage("-100", "hi", nil)
+	assert.EqualError(t, err, "telegram: sendMessage: Unauthorized")
+}
+
+func TestTelegramClientHidesToken(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	_, err := NewTelegramClient(server.URL, "123:secret").GetMe()
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "telegram: getMe: "), err.Error())
+	assert.NotContains(t, err.Error(), "123:secret")
+}
@@ -0,0 +1,285 @@
+package botuser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// defaultSectionWarnings keep translated warnings about default sections
+var defaultSectionWarnings = map[string]*i18n.Message{
+	"yesterday": {ID: "noYesterdayMention", Other: "- no 'yesterday' keywords detected: {{.Keywords}}"},
+	"today":     {ID: "noTodayMention", Other: "- no 'today' keywords detected: {{.Keywords}}"},
+	"problems":  {ID: "noProblemsMention", Other: "- no 'problems' keywords detected: {{.Keywords}}"},
+}
+
+// standupTemplate returns template of the channel or the default one
+func (bot *Bot) standupTemplate(channelID string) (model.StandupTemplate, bool) {
+	channel, err := bot.db.SelectProject(channelID)
+	if err != nil || len(channel.StandupTemplate) == 0 {
+		return model.DefaultStandupTemplate, false
+	}
+	return channel.StandupTemplate, true
+}
+
+// analizeStandup returns warnings about required sections of the channel
+// template missing in the standup, empty when it is fine
+func (bot *Bot) analizeStandup(channelID, message string) string {
+	template, custom := bot.standupTemplate(channelID)
+
+	errors := []string{}
+	for _, section := range template {
+		if !section.Required || section.Matches(message) {
+			continue
+		}
+		warning := defaultSectionWarnings[section.Name]
+		if custom {
+			warning = &i18n.Message{
+				ID:    "noSectionMention",
+				Other: "- no '{{.Section}}' section detected: {{.Keywords}}",
+			}
+		}
+		text, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+			DefaultMessage: warning,
+			TemplateData: map[string]interface{}{
+				"Section":  section.Name,
+				"Keywords": sectionKeywords(section),
+			},
+		})
+		if err != nil {
+			log.Error(err)
+		}
+		errors = append(errors, text)
+	}
+	return strings.Join(errors, ", ")
+}
+
+// saveAnswers replaces answers of the free text standup with its sections
+// found by the channel template and tracks blockers mentioned in them
+func (bot *Bot) saveAnswers(standup model.Standup) error {
+	err := bot.db.DeleteStandupAnswers(standup.ID)
+	if err != nil {
+		return err
+	}
+	template, _ := bot.standupTemplate(standup.ChannelID)
+	answers := template.Split(standup.Comment)
+	for _, a := range answers {
+		a.StandupID = standup.ID
+		_, err = bot.db.CreateStandupAnswer(a)
+		if err != nil {
+			return err
+		}
+	}
+	return bot.saveBlockers(standup, answers)
+}
+
+// sectionKeywords lists what the section is found by, the pattern is
+// shown between slashes
+func sectionKeywords(section model.StandupSection) string {
+	keywords := append([]string{}, section.Keywords...)
+	if section.Pattern != "" {
+		keywords = append(keywords, "/"+section.Pattern+"/")
+	}
+	return strings.Join(keywords, ", ")
+}
+
+// parseSection reads "Done: done, finished" or "Blocked: /block(ed|er)/"
+func parseSection(text string, required bool) (model.StandupSection, bool) {
+	parts := strings.SplitN(text, ":", 2)
+	if len(parts) != 2 {
+		return model.StandupSection{}, false
+	}
+	section := model.StandupSection{Name: strings.TrimSpace(parts[0]), Required: required}
+	for _, k := range strings.Split(parts[1], ",") {
+		k = strings.TrimSpace(k)
+		if len(k) > 2 && strings.HasPrefix(k, "/") && strings.HasSuffix(k, "/") {
+			section.Pattern = k[1 : len(k)-1]
+			continue
+		}
+		if k != "" {
+			section.Keywords = append(section.Keywords, k)
+		}
+	}
+	return section, true
+}
+
+// manageTemplate shows sections expected in standups of the channel, adds,
+// replaces or removes them, e.g. "/template add Done: done, finished",
+// "/template optional Blocked: /block(ed|er)/" or "/template remove Done".
+// "/template blockers Blocked" makes the section the one blockers are
+// tracked from
+func (bot *Bot) manageTemplate(command slack.SlashCommand) string {
+	text := strings.TrimSpace(command.Text)
+	if text == "" {
+		template, custom := bot.standupTemplate(command.ChannelID)
+		return bot.describeTemplate(template, custom)
+	}
+
+	channel, err := bot.db.SelectProject(command.ChannelID)
+	if err != nil {
+		return bot.templateNotChanged()
+	}
+
+	fields := strings.Fields(text)
+	args := strings.TrimSpace(strings.TrimPrefix(text, fields[0]))
+	template := append(model.StandupTemplate{}, channel.StandupTemplate...)
+
+	switch strings.ToLower(fields[0]) {
+	case "add", "optional":
+		section, ok := parseSection(args, strings.ToLower(fields[0]) == "add")
+		if !ok {
+			return bot.templateUsage()
+		}
+		replaced := false
+		for i, s := range template {
+			if strings.EqualFold(s.Name, section.Name) {
+				section.Blockers = s.Blockers
+				template[i] = section
+				replaced = true
+			}
+		}
+		if !replaced {
+			template = append(template, section)
+		}
+
+	case "remove":
+		removed := model.StandupTemplate{}
+		for i, s := range template {
+			if !strings.EqualFold(s.Name, args) && args != fmt.Sprint(i+1) {
+				removed = append(removed, s)
+			}
+		}
+		if len(removed) == len(template) {
+			return bot.templateUsage()
+		}
+		template = removed
+
+	case "blockers":
+		found := false
+		for i := range template {
+			template[i].Blockers = strings.EqualFold(template[i].Name, args) || args == fmt.Sprint(i+1)
+			found = found || template[i].Blockers
+		}
+		if !found {
+			return bot.templateUsage()
+		}
+
+	case "reset":
+		template = nil
+
+	default:
+		return bot.templateUsage()
+	}
+
+	err = template.Validate()
+	if err != nil {
+		return bot.invalidTemplate(err)
+	}
+	channel.StandupTemplate = template
+	_, err = bot.db.UpdateProject(channel)
+	if err != nil {
+		log.Error("UpdateProject failed: ", err)
+		return bot.templateNotChanged()
+	}
+	return bot.describeTemplate(bot.standupTemplate(channel.ChannelID))
+}
+
+// describeTemplate returns numbered list of sections, numbers or names are
+// used to remove them
+func (bot *Bot) describeTemplate(template model.StandupTemplate, custom bool) string {
+	header := &i18n.Message{
+		ID:    "showStandupTemplate",
+		Other: "Standups of the channel should have these sections:",
+	}
+	if !custom {
+		header = &i18n.Message{
+			ID:    "showDefaultStandupTemplate",
+			Other: "The channel uses default standup sections:",
+		}
+	}
+	text, err := bot.localizer.Localize(&i18n.LocalizeConfig{DefaultMessage: header})
+	if err != nil {
+		log.Error(err)
+	}
+	optional, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "optionalSection",
+			Other: "optional",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	blockers, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "blockersSection",
+			Other: "blockers",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	lines := []string{text}
+	for i, section := range template {
+		line := fmt.Sprintf("%v. %v: %v", i+1, section.Name, sectionKeywords(section))
+		notes := []string{}
+		if !section.Required {
+			notes = append(notes, optional)
+		}
+		if section.Blockers {
+			notes = append(notes, blockers)
+		}
+		if len(notes) > 0 {
+			line += " (" + strings.Join(notes, ", ") + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (bot *Bot) templateUsage() string {
+	templateUsage, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "templateUsage",
+			Other: "Use /template add Done: done, finished to require a section, /template optional Blocked: /block(ed|er)/ to add an optional one, /template remove Done to remove it, /template blockers Blocked to track blockers from a section or /template reset to return to default sections",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return templateUsage
+}
+
+func (bot *Bot) templateNotChanged() string {
+	templateNotChanged, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "templateNotChanged",
+			Other: "Could not change standup sections",
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return templateNotChanged
+}
+
+func (bot *Bot) invalidTemplate(reason error) string {
+	invalidTemplate, err := bot.localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "invalidStandupTemplate",
+			Other: "Could not change standup sections: {{.Error}}",
+		},
+		TemplateData: map[string]interface{}{
+			"Error": reason.Error(),
+		},
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return invalidTemplate
+}
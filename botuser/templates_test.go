@@ -0,0 +1,118 @@
+package botuser
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManageTemplate(t *testing.T) {
+	bot, messenger := newTestBot()
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID: "testTeam",
+		ChannelID:   "CHAN1",
+		ChannelName: "general",
+		TZ:          "UTC",
+	})
+	require.NoError(t, err)
+
+	template := func(text string) string {
+		return bot.ImplementCommands(slack.SlashCommand{
+			Command:   "/template",
+			Text:      text,
+			TeamID:    "testTeam",
+			ChannelID: "CHAN1",
+			UserID:    "U1",
+		})
+	}
+	post := func(text string) []Message {
+		require.NoError(t, bot.HandleMessage(&slack.MessageEvent{Msg: slack.Msg{
+			Channel:   "CHAN1",
+			User:      "U1",
+			Text:      "<@BOT> " + text,
+			Timestamp: text,
+		}}))
+		return messenger.flush()
+	}
+
+	assert.Equal(t, "The channel uses default standup sections:\n1. yesterday: yesterday, friday, вчера, пятниц\n2. today: today, сегодня\n3. problems: issue, мешает (blockers)", template(""))
+
+	assert.Equal(t, "Standups of the channel should have these sections:\n1. Done: done, finished", template("add Done: done, finished"))
+	assert.Equal(t, "Standups of the channel should have these sections:\n1. Done: done, finished\n2. Doing: doing", template("add Doing: doing"))
+	assert.Equal(t, "Standups of the channel should have these sections:\n1. Done: done, finished\n2. Doing: doing\n3. Blocked: /block(ed|er)/ (optional)", template("optional Blocked: /block(ed|er)/"))
+	// a section with the same name is replaced
+	assert.Equal(t, "Standups of the channel should have these sections:\n1. done: done, finished, сделано\n2. Doing: doing\n3. Blocked: /block(ed|er)/ (optional)", template("add done: done, finished, сделано"))
+
+	assert.Equal(t, "Standups of the channel should have these sections:\n1. done: done, finished, сделано\n2. Doing: doing\n3. Blocked: /block(ed|er)/ (optional, blockers)", template("blockers blocked"))
+	// blockers are tracked from one section, replacing it keeps the flag
+	template("blockers 2")
+	assert.Equal(t, "Standups of the channel should have these sections:\n1. done: done, finished, сделано\n2. Doing: doing (blockers)\n3. Blocked: /block(ed|er)/ (optional)", template("add Doing: doing"))
+
+	assert.Equal(t, "Could not change standup sections: section \"Later\" has invalid pattern: error parsing regexp: missing closing ): `(?i)(`", template("add Later: /(/"))
+	assert.Equal(t, "Could not change standup sections: section \"Later\" needs keywords or a pattern", template("add Later:"))
+	usage := "Use /template add Done: done, finished to require a section, /template optional Blocked: /block(ed|er)/ to add an optional one, /template remove Done to remove it, /template blockers Blocked to track blockers from a section or /template reset to return to default sections"
+	assert.Equal(t, usage, template("add Later"))
+	assert.Equal(t, usage, template("remove Later"))
+	assert.Equal(t, usage, template("rename Done"))
+	assert.Equal(t, usage, template("blockers Later"))
+
+	// the validator speaks the wording of the project
+	messages := post("Done: fixed bugs")
+	require.Len(t, messages, 1)
+	assert.Equal(t, "- no 'Doing' section detected: doing", messages[0].Text)
+	assert.Empty(t, post("Done: fixed bugs\nDoing: tests"))
+	assert.Empty(t, post("Сделано: отчёт\ndoing: tests\nblockers: none"))
+
+	assert.Equal(t, "Standups of the channel should have these sections:\n1. done: done, finished, сделано\n2. Blocked: /block(ed|er)/ (optional)", template("remove 2"))
+	assert.Equal(t, "Standups of the channel should have these sections:\n1. done: done, finished, сделано", template("remove blocked"))
+
+	channel, err := bot.db.SelectProject("CHAN1")
+	require.NoError(t, err)
+	assert.Equal(t, model.StandupTemplate{{Name: "done", Keywords: []string{"done", "finished", "сделано"}, Required: true}}, channel.StandupTemplate)
+
+	reset := template("reset")
+	assert.Equal(t, template(""), reset)
+	messages = post("wrong standup")
+	require.Len(t, messages, 1)
+	assert.Equal(t, "- no 'yesterday' keywords detected: yesterday, friday, вчера, пятниц, - no 'today' keywords detected: today, сегодня, - no 'problems' keywords detected: issue, мешает", messages[0].Text)
+}
+
+func TestFreeTextStandupAnswers(t *testing.T) {
+	bot, _ := newTestBot()
+
+	_, err := bot.db.CreateProject(model.Project{WorkspaceID: "testTeam", ChannelID: "CHAN1", ChannelName: "general", TZ: "UTC"})
+	require.NoError(t, err)
+
+	require.NoError(t, bot.HandleMessage(&slack.MessageEvent{Msg: slack.Msg{
+		Channel:   "CHAN1",
+		User:      "U1",
+		Text:      "<@BOT> Yesterday: fixed bugs\nToday: write tests\nIssues: none",
+		Timestamp: "1.1",
+	}}))
+	standup, err := bot.db.SelectStandupByMessageTS("testTeam", "CHAN1", "1.1")
+	require.NoError(t, err)
+	answers := func() []string {
+		items, err := bot.db.ListStandupAnswers(standup.ID)
+		require.NoError(t, err)
+		texts := []string{}
+		for _, a := range items {
+			texts = append(texts, a.Question+": "+a.Answer)
+		}
+		return texts
+	}
+	assert.Equal(t, []string{"yesterday: fixed bugs", "today: write tests", "problems: none"}, answers())
+
+	require.NoError(t, bot.HandleMessage(&slack.MessageEvent{
+		Msg: slack.Msg{Channel: "CHAN1", User: "U1", SubType: typeEditMessage, Text: "<@BOT>"},
+		SubMessage: &slack.Msg{
+			User:      "U1",
+			Text:      "<@BOT> Yesterday: fixed bugs\nToday: release\nIssue: tests are slow",
+			Timestamp: "1.1",
+		},
+	}))
+	assert.Equal(t, []string{"yesterday: fixed bugs", "today: release", "problems: tests are slow"}, answers())
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/maddevsio/comedian/model"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	log "github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
@@ -76,3 +77,40 @@ func (bot *Bot) modifyTZ(command slack.SlashCommand) string {
 	}
 	return msg
 }
+
+// location returns the time zone, or UTC when it is not recognized
+func location(tz string) *time.Location {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		log.Errorf("could not recognize time zone %q: %v", tz, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// workspaceLocation returns the time zone workspace reports and reminders
+// are scheduled in
+func (bot *Bot) workspaceLocation() *time.Location {
+	return location(bot.workspace.TZ)
+}
+
+// standuperLocation returns the time zone days of the standuper are counted
+// in: their own one when it is set, or the one of the channel
+func standuperLocation(channel model.Project, standuper model.Standuper) *time.Location {
+	return location(standuperSchedule(channel, standuper).TZ)
+}
+
+// userLocation returns the time zone days of the user in the channel are
+// counted in
+func (bot *Bot) userLocation(userID, channelID string) *time.Location {
+	channel, err := bot.db.SelectProject(channelID)
+	if err != nil {
+		log.Error("userLocation SelectProject failed: ", err)
+		return time.UTC
+	}
+	standuper, err := bot.db.FindStansuperByUserID(userID, channelID)
+	if err != nil {
+		return location(channel.TZ)
+	}
+	return standuperLocation(channel, standuper)
+}
@@ -0,0 +1,87 @@
+package botuser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDateLine checks days of a team straddling the date line: the channel
+// is in Kiritimati (UTC+14) and one standuper works from Pago Pago (UTC-11),
+// so at the same moment they live in different days
+func TestDateLine(t *testing.T) {
+	bot, messenger := newTestBot(
+		&i18n.Message{ID: "noStandup", Other: "no standup"},
+		&i18n.Message{ID: "hasStandup", Other: "standup"},
+	)
+	// days do not depend on where the server runs
+	local := time.Local
+	time.Local = time.FixedZone("server", 6*60*60)
+	defer func() { time.Local = local }()
+
+	// Tuesday 19:00 in Kiritimati, Monday 18:00 in Pago Pago
+	now := time.Date(2019, 11, 5, 5, 0, 0, 0, time.UTC)
+	bot.SetClock(clock.NewMock(now))
+
+	_, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "Pacific/Kiritimati",
+		SubmissionDays: "daily",
+	})
+	require.NoError(t, err)
+
+	standupers := []model.Standuper{
+		{UserID: "U1"},
+		{UserID: "U2", TZ: "Pacific/Pago_Pago"},
+	}
+	for i, s := range standupers {
+		// chat profile time zone is not used
+		messenger.users[s.UserID] = User{ID: s.UserID, TZ: "America/Los_Angeles", TZOffset: -8 * 60 * 60}
+		s.WorkspaceID = "testTeam"
+		s.ChannelID = "CHAN1"
+		standupers[i], err = bot.db.CreateStanduper(s)
+		require.NoError(t, err)
+	}
+
+	// Tuesday 01:00 for U1, Sunday 23:00 for U2
+	for userID, at := range map[string]time.Time{
+		"U1": time.Date(2019, 11, 4, 11, 0, 0, 0, time.UTC),
+		"U2": time.Date(2019, 11, 4, 10, 0, 0, 0, time.UTC),
+	} {
+		_, err := bot.db.CreateStandup(model.Standup{
+			CreatedAt:   at.Unix(),
+			WorkspaceID: "testTeam",
+			ChannelID:   "CHAN1",
+			UserID:      userID,
+			MessageTS:   userID,
+		})
+		require.NoError(t, err)
+	}
+
+	assert.True(t, bot.submittedStandupToday("U1", "CHAN1"))
+	assert.False(t, bot.submittedStandupToday("U2", "CHAN1"))
+
+	// yesterday is Monday for U1 and Sunday for U2
+	text, points := bot.processStandup(standupers[0])
+	assert.Equal(t, "no standup", text)
+	assert.Equal(t, 0, points)
+	text, points = bot.processStandup(standupers[1])
+	assert.Equal(t, "standup", text)
+	assert.Equal(t, 1, points)
+
+	// Monday absence is over for U1 and goes on for U2
+	for _, userID := range []string{"U1", "U2"} {
+		_, err := bot.db.CreateAbsence(model.Absence{WorkspaceID: "testTeam", UserID: userID, StartDate: "2019-11-04", EndDate: "2019-11-04", Source: model.AbsenceManual})
+		require.NoError(t, err)
+	}
+	assert.False(t, bot.absentToday("U1", bot.userLocation("U1", "CHAN1")))
+	assert.True(t, bot.absentToday("U2", bot.userLocation("U2", "CHAN1")))
+}
@@ -0,0 +1,121 @@
+package botuser
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// userDirectory caches chat users of the workspace, so checks of every
+// standuper do not ask the chat about them one by one. It is filled in bulk
+// and refilled when it gets older than UserCacheTTL minutes, zero TTL turns
+// caching off. The lock guards the map only, the chat is asked without it,
+// so a slow request does not hold up readers of the directory
+type userDirectory struct {
+	mu       sync.Mutex
+	users    map[string]User
+	loadedAt time.Time
+}
+
+// refreshUsers refills the directory if it is stale. Users are loaded into
+// a new map which replaces the old one when it is complete
+func (bot *Bot) refreshUsers() error {
+	ttl := time.Duration(bot.conf.UserCacheTTL) * time.Minute
+	now := bot.clock.Now()
+
+	bot.users.mu.Lock()
+	fresh := bot.users.users != nil && now.Sub(bot.users.loadedAt) < ttl
+	bot.users.mu.Unlock()
+	if fresh {
+		return nil
+	}
+
+	users, err := bot.messenger.GetUsers()
+	if err != nil {
+		return err
+	}
+	loaded := make(map[string]User, len(users))
+	for _, u := range users {
+		loaded[u.ID] = u
+	}
+
+	bot.users.mu.Lock()
+	bot.users.users = loaded
+	bot.users.loadedAt = now
+	bot.users.mu.Unlock()
+	return nil
+}
+
+// user returns chat profile of the user. Users missing in the bulk list,
+// e.g. in Telegram which cannot list users, are asked for one by one and
+// kept until the next refill
+func (bot *Bot) user(userID string) (*User, error) {
+	if bot.conf.UserCacheTTL <= 0 {
+		return bot.messenger.GetUserInfo(userID)
+	}
+
+	err := bot.refreshUsers()
+	if err != nil {
+		log.Error("refreshUsers failed: ", err)
+	}
+	bot.users.mu.Lock()
+	u, ok := bot.users.users[userID]
+	bot.users.mu.Unlock()
+	if ok {
+		return &u, nil
+	}
+
+	info, err := bot.messenger.GetUserInfo(userID)
+	if err != nil {
+		return nil, err
+	}
+	bot.users.mu.Lock()
+	if bot.users.users != nil {
+		bot.users.users[userID] = *info
+	}
+	bot.users.mu.Unlock()
+	return info, nil
+}
+
+// listUsers returns all chat users of the workspace
+func (bot *Bot) listUsers() ([]User, error) {
+	if bot.conf.UserCacheTTL <= 0 {
+		return bot.messenger.GetUsers()
+	}
+
+	err := bot.refreshUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	bot.users.mu.Lock()
+	defer bot.users.mu.Unlock()
+	users := make([]User, 0, len(bot.users.users))
+	for _, u := range bot.users.users {
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+// HandleUserChange updates the user in the directory and renames them in
+// standup teams when their name changed. Slack sends the event to one
+// replica only, so the directories of other replicas keep the old profile
+// until their next refill, while names in standup teams are shared
+func (bot *Bot) HandleUserChange(event *slack.UserChangeEvent) error {
+	return bot.updateUser(slackUser(event.User))
+}
+
+func (bot *Bot) updateUser(u User) error {
+	bot.users.mu.Lock()
+	if bot.users.users != nil {
+		bot.users.users[u.ID] = u
+	}
+	bot.users.mu.Unlock()
+
+	if u.RealName == "" {
+		return nil
+	}
+	return bot.db.RenameStanduper(bot.workspace.WorkspaceID, u.ID, u.RealName)
+}
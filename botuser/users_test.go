@@ -0,0 +1,96 @@
+package botuser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/clock"
+	"github.com/maddevsio/comedian/model"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserDirectory(t *testing.T) {
+	bot, messenger := newTestBot()
+	bot.conf.UserCacheTTL = 60
+	bot.conf.AbsenceStatusEmojis = []string{"palm_tree"}
+	now := time.Date(2019, 11, 4, 9, 50, 0, 0, time.UTC)
+	clk := clock.NewMock(now)
+	bot.SetClock(clk)
+
+	channel, err := bot.db.CreateProject(model.Project{
+		WorkspaceID:    "testTeam",
+		ChannelID:      "CHAN1",
+		ChannelName:    "general",
+		Deadline:       "10:00",
+		TZ:             "UTC",
+		SubmissionDays: "monday",
+	})
+	require.NoError(t, err)
+	for _, id := range []string{"U1", "U2", "U3"} {
+		messenger.users[id] = User{ID: id, TeamID: "testTeam", RealName: "John " + id}
+		_, err := bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "CHAN1", UserID: id, RealName: "John " + id})
+		require.NoError(t, err)
+	}
+	_, err = bot.db.CreateStanduper(model.Standuper{WorkspaceID: "testTeam", ChannelID: "CHAN2", UserID: "U1", RealName: "John U1"})
+	require.NoError(t, err)
+
+	// checks of every standuper are served by one bulk request
+	for i := 0; i < 3; i++ {
+		require.NoError(t, bot.warn(channel, "", 0, now.Add(10*time.Minute)))
+	}
+	assert.Len(t, messenger.flush(), 3)
+	assert.Equal(t, 1, messenger.usersCalls)
+	assert.Equal(t, 0, messenger.userInfoCalls)
+
+	// users who joined after the fill are asked for once
+	messenger.users["U4"] = User{ID: "U4", TeamID: "testTeam", RealName: "Jane"}
+	for i := 0; i < 2; i++ {
+		u, err := bot.user("U4")
+		require.NoError(t, err)
+		assert.Equal(t, "Jane", u.RealName)
+	}
+	assert.Equal(t, 1, messenger.usersCalls)
+	assert.Equal(t, 1, messenger.userInfoCalls)
+
+	// user_change updates the directory and names in standup teams
+	require.NoError(t, bot.HandleUserChange(&slack.UserChangeEvent{
+		Type: "user_change",
+		User: slack.User{ID: "U1", TeamID: "testTeam", RealName: "John Smith", Profile: slack.UserProfile{StatusEmoji: ":palm_tree:"}},
+	}))
+	u, err := bot.user("U1")
+	require.NoError(t, err)
+	assert.Equal(t, "John Smith", u.RealName)
+	standupers, err := bot.db.FindStansupersByUserID("U1")
+	require.NoError(t, err)
+	require.Len(t, standupers, 2)
+	for _, s := range standupers {
+		assert.Equal(t, "John Smith", s.RealName)
+	}
+
+	require.NoError(t, bot.warn(channel, "", 0, now.Add(10*time.Minute)))
+	messages := messenger.flush()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "<@U2>, <@U3> you may miss the deadline in 10 minutes", messages[0].Text)
+	assert.Equal(t, 1, messenger.usersCalls)
+
+	// stale directory is refilled without locking it while the chat answers
+	clk.Add(time.Hour)
+	messenger.onGetUsers = func() {
+		done := make(chan struct{})
+		go func() {
+			assert.NoError(t, bot.updateUser(User{ID: "U5"}))
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("directory is locked while users are loaded")
+		}
+	}
+	users, err := bot.listUsers()
+	require.NoError(t, err)
+	assert.Len(t, users, 4)
+	assert.Equal(t, 2, messenger.usersCalls)
+}
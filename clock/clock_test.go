@@ -0,0 +1,29 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMock(t *testing.T) {
+	start := time.Date(2019, 11, 4, 9, 0, 0, 0, time.UTC)
+
+	c := NewMock(start)
+	assert.Equal(t, start, c.Now())
+	assert.Equal(t, start, c.Now(), "mock stands still")
+
+	c.Add(90 * time.Second)
+	assert.Equal(t, time.Date(2019, 11, 4, 9, 1, 30, 0, time.UTC), c.Now())
+
+	c.Set(start.AddDate(0, 0, 1))
+	assert.Equal(t, time.Date(2019, 11, 5, 9, 0, 0, 0, time.UTC), c.Now())
+}
+
+func TestReal(t *testing.T) {
+	before := time.Now()
+	now := New().Now()
+	assert.False(t, now.Before(before))
+	assert.False(t, now.After(time.Now()))
+}
@@ -1,25 +1,57 @@
 package config
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 // Config struct used for configuration of app with env variables
 type Config struct {
-	DatabaseURL            string `envconfig:"DATABASE" required:"false" default:"comedian:comedian@/comedian?parseTime=true"`
-	CollectorURL           string `envconfig:"COLLECTOR_URL" required:"false" default:""`
-	CollectorToken         string `envconfig:"COLLECTOR_TOKEN" required:"false" default:""`
-	HTTPBindAddr           string `envconfig:"HTTP_BIND_ADDR" required:"false" default:"0.0.0.0:8080"`
-	SlackClientID          string `envconfig:"SLACK_CLIENT_ID" required:"false"`
-	SlackClientSecret      string `envconfig:"SLACK_CLIENT_SECRET" required:"false"`
-	SlackVerificationToken string `envconfig:"SLACK_VERIFICATION_TOKEN" required:"false"`
-	UIurl                  string `envconfig:"UI_URL" required:"false"`
-	NotificationTime       int64  `envconfig:"NOTIFICATION_TIME" default:"1"`
+	DatabaseURL            string   `envconfig:"DATABASE" required:"false" default:"comedian:comedian@/comedian?parseTime=true"`
+	CollectorURL           string   `envconfig:"COLLECTOR_URL" required:"false" default:""`
+	CollectorToken         string   `envconfig:"COLLECTOR_TOKEN" required:"false" default:""`
+	HTTPBindAddr           string   `envconfig:"HTTP_BIND_ADDR" required:"false" default:"0.0.0.0:8080"`
+	SlackClientID          string   `envconfig:"SLACK_CLIENT_ID" required:"false"`
+	SlackClientSecret      string   `envconfig:"SLACK_CLIENT_SECRET" required:"false"`
+	SlackVerificationToken string   `envconfig:"SLACK_VERIFICATION_TOKEN" required:"false"`
+	SlackSigningSecret     string   `envconfig:"SLACK_SIGNING_SECRET" required:"false"`
+	MattermostTokens       []string `envconfig:"MATTERMOST_TOKENS" required:"false"`
+	MattermostServers      []string `envconfig:"MATTERMOST_SERVERS" required:"false"`
+	TelegramAPIURL         string   `envconfig:"TELEGRAM_API_URL" required:"false" default:"https://api.telegram.org"`
+	TelegramWebhookURL     string   `envconfig:"TELEGRAM_WEBHOOK_URL" required:"false"`
+	TelegramSecretToken    string   `envconfig:"TELEGRAM_SECRET_TOKEN" required:"false"`
+	UIurl                  string   `envconfig:"UI_URL" required:"false"`
+	NotificationTime       int64    `envconfig:"NOTIFICATION_TIME" default:"1"`
+	SchedulerGraceWindow   int64    `envconfig:"SCHEDULER_GRACE_WINDOW" default:"15"`
+	ReplicaID              string   `envconfig:"REPLICA_ID" required:"false"`
+	LeaseTTL               int64    `envconfig:"LEASE_TTL" default:"30"`
+	AdminToken             string   `envconfig:"ADMIN_TOKEN" required:"false"`
+	EventWorkers           int      `envconfig:"EVENT_WORKERS" default:"4"`
+	EventQueueSize         int      `envconfig:"EVENT_QUEUE_SIZE" default:"100"`
+	EventMaxAttempts       int      `envconfig:"EVENT_MAX_ATTEMPTS" default:"5"`
+	EventRetention         int64    `envconfig:"EVENT_RETENTION" default:"7"`
+	OutboxRate             float64  `envconfig:"OUTBOX_RATE" default:"1"`
+	OutboxBurst            int      `envconfig:"OUTBOX_BURST" default:"10"`
+	OutboxMaxAttempts      int      `envconfig:"OUTBOX_MAX_ATTEMPTS" default:"5"`
+	OutboxRetention        int64    `envconfig:"OUTBOX_RETENTION" default:"7"`
+	AbsenceStatusEmojis    []string `envconfig:"ABSENCE_STATUS_EMOJIS" required:"false"`
+	UserCacheTTL           int64    `envconfig:"USER_CACHE_TTL" default:"60"`
 }
 
 // Get method processes env variables and fills Config struct
 func Get() (*Config, error) {
 	c := &Config{}
 	err := envconfig.Process("", c)
-	return c, err
+	if err != nil {
+		return c, err
+	}
+
+	// replicas have to be told apart when they compete for leases
+	if c.ReplicaID == "" {
+		host, _ := os.Hostname()
+		c.ReplicaID = fmt.Sprintf("%v-%v", host, os.Getpid())
+	}
+	return c, nil
 }
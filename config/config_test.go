@@ -23,5 +23,10 @@ func TestConfig(t *testing.T) {
 	assert.Equal(t, conf.HTTPBindAddr, "0.0.0.0:8080")
 	assert.Equal(t, conf.SlackClientID, "ID")
 	assert.Equal(t, conf.SlackClientSecret, "SECRET")
+	assert.NotEmpty(t, conf.ReplicaID)
 
+	os.Setenv("REPLICA_ID", "comedian-0")
+	conf, err = Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "comedian-0", conf.ReplicaID)
 }
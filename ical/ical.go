@@ -0,0 +1,269 @@
+// Package ical reads events from iCalendar (RFC 5545) files. It knows just
+// enough of the format to import holiday calendars: days covered by events,
+// their summaries and yearly recurrence. Other recurrence rules are rejected
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxEventDays is the longest event accepted, longer ones are most likely
+// mistakes which would turn into a holiday for every day of them
+const MaxEventDays = 366
+
+// Event is a calendar event. Start and End are the first and the last day
+// of the first occurrence, End is inclusive
+type Event struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+	// Yearly event repeats every Interval years, Count times or until
+	// Until when they are set
+	Yearly   bool
+	Interval int
+	Count    int
+	Until    time.Time
+}
+
+// Days returns every day of the event occurrences which start not later
+// than until
+func (e Event) Days(until time.Time) []time.Time {
+	var days []time.Time
+	for _, start := range e.occurrences(until) {
+		end := start.Add(e.End.Sub(e.Start))
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// occurrences returns first days of the event occurrences which start not
+// later than until. A single event occurs once whenever it starts
+func (e Event) occurrences(until time.Time) []time.Time {
+	if !e.Yearly {
+		return []time.Time{e.Start}
+	}
+	if !e.Until.IsZero() && e.Until.Before(until) {
+		until = e.Until
+	}
+	interval := e.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var starts []time.Time
+	for i := 0; e.Count == 0 || i < e.Count; i++ {
+		start := e.Start.AddDate(i*interval, 0, 0)
+		if start.After(until) {
+			break
+		}
+		// February 29 does not occur in common years
+		if start.Day() != e.Start.Day() {
+			continue
+		}
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// Parse reads events of the calendar
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var event map[string]string
+	var calendar bool
+
+	for _, line := range lines {
+		name, value := property(line)
+		switch {
+		case name == "BEGIN" && value == "VCALENDAR":
+			calendar = true
+		case name == "BEGIN" && value == "VEVENT":
+			event = map[string]string{}
+		case name == "END" && value == "VEVENT":
+			if event == nil {
+				return nil, errors.New("END:VEVENT without BEGIN:VEVENT")
+			}
+			e, err := newEvent(event)
+			if err != nil {
+				return nil, fmt.Errorf("event %v: %v", len(events)+1, err)
+			}
+			events = append(events, e)
+			event = nil
+		case event != nil:
+			// raw value keeps parameters like VALUE=DATE which tell date from date-time
+			if _, ok := event[name]; !ok {
+				event[name] = line
+			}
+		}
+	}
+
+	if !calendar {
+		return nil, errors.New("not an iCalendar file")
+	}
+	return events, nil
+}
+
+func newEvent(props map[string]string) (Event, error) {
+	dtstart, ok := props["DTSTART"]
+	if !ok {
+		return Event{}, errors.New("no DTSTART")
+	}
+	start, _, err := parseDate(dtstart)
+	if err != nil {
+		return Event{}, err
+	}
+
+	end := start
+	if dtend, ok := props["DTEND"]; ok {
+		var midnight bool
+		end, midnight, err = parseDate(dtend)
+		if err != nil {
+			return Event{}, err
+		}
+		// DTEND is exclusive, so event ending at midnight is over the day before
+		if midnight && end.After(start) {
+			end = end.AddDate(0, 0, -1)
+		}
+	}
+	if end.Before(start) {
+		return Event{}, errors.New("ends before it starts")
+	}
+	if end.Sub(start) >= MaxEventDays*24*time.Hour {
+		return Event{}, fmt.Errorf("lasts longer than %v days", MaxEventDays)
+	}
+
+	_, summary := property(props["SUMMARY"])
+	event := Event{Summary: unescape(summary), Start: start, End: end}
+
+	if rrule, ok := props["RRULE"]; ok {
+		err = parseRule(&event, rrule)
+		if err != nil {
+			return Event{}, err
+		}
+	}
+	return event, nil
+}
+
+// parseRule reads yearly recurrence rule of the event. Rules which repeat
+// the event other than on its own date every year are not supported
+func parseRule(event *Event, line string) error {
+	_, rule := property(line)
+	for _, part := range strings.Split(rule, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("could not recognize RRULE %q", rule)
+		}
+		name, value := strings.ToUpper(kv[0]), kv[1]
+
+		var err error
+		switch name {
+		case "FREQ":
+			if strings.ToUpper(value) != "YEARLY" {
+				return fmt.Errorf("RRULE %q is not supported, only FREQ=YEARLY is", rule)
+			}
+			event.Yearly = true
+		case "INTERVAL":
+			event.Interval, err = positive(value)
+		case "COUNT":
+			event.Count, err = positive(value)
+		case "UNTIL":
+			event.Until, _, err = parseDate(":" + value)
+		case "BYMONTH":
+			if value != strconv.Itoa(int(event.Start.Month())) {
+				return fmt.Errorf("RRULE %q is not supported, only FREQ=YEARLY is", rule)
+			}
+		case "BYMONTHDAY":
+			if value != strconv.Itoa(event.Start.Day()) {
+				return fmt.Errorf("RRULE %q is not supported, only FREQ=YEARLY is", rule)
+			}
+		case "WKST":
+		default:
+			return fmt.Errorf("RRULE %q is not supported, only FREQ=YEARLY is", rule)
+		}
+		if err != nil {
+			return fmt.Errorf("could not recognize RRULE %q", rule)
+		}
+	}
+	if !event.Yearly {
+		return fmt.Errorf("could not recognize RRULE %q", rule)
+	}
+	return nil
+}
+
+func positive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err == nil && n < 1 {
+		err = errors.New("not positive")
+	}
+	return n, err
+}
+
+// parseDate returns the day of DTSTART or DTEND property, and whether the
+// value points to the beginning of the day (a date or midnight)
+func parseDate(line string) (time.Time, bool, error) {
+	_, value := property(line)
+	if len(value) < 8 {
+		return time.Time{}, false, fmt.Errorf("could not recognize date %q", value)
+	}
+	day, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("could not recognize date %q", value)
+	}
+	midnight := len(value) == 8 || strings.HasPrefix(value[8:], "T000000")
+	return day, midnight, nil
+}
+
+// unfold reads content lines joining the ones split by folding
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// property splits content line into upper case name without parameters
+// and value
+func property(line string) (string, string) {
+	quoted := false
+	for i, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == ':' && !quoted:
+			name := line[:i]
+			if j := strings.IndexByte(name, ';'); j >= 0 {
+				name = name[:j]
+			}
+			return strings.ToUpper(name), line[i+1:]
+		}
+	}
+	return strings.ToUpper(line), ""
+}
+
+var unescaper = strings.NewReplacer(`\\`, `\`, `\;`, `;`, `\,`, `,`, `\n`, "\n", `\N`, "\n")
+
+func unescape(text string) string {
+	return unescaper.Replace(text)
+}
@@ -0,0 +1,96 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const holidays = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Google Inc//Google Calendar 70.9054//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20200101\r\n" +
+	"DTEND;VALUE=DATE:20200103\r\n" +
+	"SUMMARY:New Year\\, holidays\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20200107\r\n" +
+	"SUMMARY:Orthodox Christmas\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=\"Asia/Bishkek\":20200308T000000\r\n" +
+	"DTEND;TZID=\"Asia/Bishkek\":20200309T000000\r\n" +
+	"SUMMARY:International Women's \r\n" +
+	" Day\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20200321T090000Z\r\n" +
+	"DTEND:20200321T180000Z\r\n" +
+	"SUMMARY:Nooruz\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func date(day int, month time.Month) time.Time {
+	return time.Date(2020, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	events, err := Parse(strings.NewReader(holidays))
+	require.NoError(t, err)
+	assert.Equal(t, []Event{
+		{Summary: "New Year, holidays", Start: date(1, time.January), End: date(2, time.January)},
+		{Summary: "Orthodox Christmas", Start: date(7, time.January), End: date(7, time.January)},
+		{Summary: "International Women's Day", Start: date(8, time.March), End: date(8, time.March)},
+		{Summary: "Nooruz", Start: date(21, time.March), End: date(21, time.March)},
+	}, events)
+
+	assert.Equal(t, []time.Time{date(1, time.January), date(2, time.January)}, events[0].Days(date(1, time.January)))
+}
+
+func TestParseYearly(t *testing.T) {
+	file := "BEGIN:VCALENDAR\n" +
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20200308\nRRULE:FREQ=YEARLY;BYMONTH=3;BYMONTHDAY=8\nSUMMARY:Women's Day\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20200101\nDTEND;VALUE=DATE:20200103\nRRULE:FREQ=YEARLY;COUNT=2\nSUMMARY:New Year\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20200229\nRRULE:FREQ=YEARLY;UNTIL=20281231\nSUMMARY:Leap day\nEND:VEVENT\n" +
+		"END:VCALENDAR\n"
+	events, err := Parse(strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	until := time.Date(2030, time.December, 31, 0, 0, 0, 0, time.UTC)
+	day := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	assert.Equal(t, []time.Time{day(2020, time.March, 8), day(2021, time.March, 8), day(2022, time.March, 8)},
+		events[0].Days(day(2022, time.March, 8)))
+	assert.Equal(t, []time.Time{day(2020, time.January, 1), day(2020, time.January, 2), day(2021, time.January, 1), day(2021, time.January, 2)},
+		events[1].Days(until))
+	assert.Equal(t, []time.Time{day(2020, time.February, 29), day(2024, time.February, 29), day(2028, time.February, 29)},
+		events[2].Days(until))
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		file string
+		err  string
+	}{
+		{"hello", "not an iCalendar file"},
+		{"BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Nothing\nEND:VEVENT\nEND:VCALENDAR", "event 1: no DTSTART"},
+		{"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:2020\nEND:VEVENT\nEND:VCALENDAR", `event 1: could not recognize date "2020"`},
+		{"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20200101\nEND:VEVENT\nBEGIN:VEVENT\nDTSTART:20200105\nDTEND:20200101\nEND:VEVENT\nEND:VCALENDAR", "event 2: ends before it starts"},
+		{"BEGIN:VCALENDAR\nEND:VEVENT\nEND:VCALENDAR", "END:VEVENT without BEGIN:VEVENT"},
+		{"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20200101\nDTEND:20500101\nEND:VEVENT\nEND:VCALENDAR", "event 1: lasts longer than 366 days"},
+		{"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20200101\nRRULE:FREQ=WEEKLY;BYDAY=SA\nEND:VEVENT\nEND:VCALENDAR", `event 1: RRULE "FREQ=WEEKLY;BYDAY=SA" is not supported, only FREQ=YEARLY is`},
+		{"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20200101\nRRULE:FREQ=YEARLY;BYMONTH=5\nEND:VEVENT\nEND:VCALENDAR", `event 1: RRULE "FREQ=YEARLY;BYMONTH=5" is not supported, only FREQ=YEARLY is`},
+		{"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20200101\nRRULE:FREQ=YEARLY;COUNT=0\nEND:VEVENT\nEND:VCALENDAR", `event 1: could not recognize RRULE "FREQ=YEARLY;COUNT=0"`},
+	}
+	for _, tc := range testCases {
+		_, err := Parse(strings.NewReader(tc.file))
+		assert.EqualError(t, err, tc.err)
+	}
+}
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `job_runs` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `job_id` INTEGER NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `job_name` VARCHAR(255) NOT NULL,
+    `scheduled_at` BIGINT NOT NULL,
+    `started_at` BIGINT NOT NULL,
+    `finished_at` BIGINT NOT NULL,
+    `status` VARCHAR(255) NOT NULL,
+    `error` TEXT NOT NULL,
+    KEY `job_runs_job_id` (`job_id`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `job_runs`;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `leases` (
+    `name` VARCHAR(255) NOT NULL PRIMARY KEY,
+    `holder` VARCHAR(255) NOT NULL,
+    `expires_at` BIGINT NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `leases`;
+-- +goose StatementEnd
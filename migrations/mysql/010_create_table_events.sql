@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `events` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `event_id` VARCHAR(255) NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `type` VARCHAR(255) NOT NULL,
+    `payload` MEDIUMTEXT NOT NULL,
+    `status` VARCHAR(255) NOT NULL,
+    `attempts` INTEGER NOT NULL,
+    `error` TEXT NOT NULL,
+    `created_at` BIGINT NOT NULL,
+    `next_attempt_at` BIGINT NOT NULL,
+    UNIQUE KEY `events_event_id` (`event_id`),
+    KEY `events_status_next_attempt_at` (`status`, `next_attempt_at`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `events`;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `outbox_messages` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `type` VARCHAR(255) NOT NULL,
+    `channel_id` VARCHAR(255) NOT NULL,
+    `user_id` VARCHAR(255) NOT NULL,
+    `text` TEXT NOT NULL,
+    `attachments` MEDIUMTEXT NOT NULL,
+    `status` VARCHAR(255) NOT NULL,
+    `attempts` INTEGER NOT NULL,
+    `error` TEXT NOT NULL,
+    `created_at` BIGINT NOT NULL,
+    `next_attempt_at` BIGINT NOT NULL,
+    `sent_at` BIGINT NOT NULL,
+    `message_ts` VARCHAR(255) NOT NULL,
+    KEY `outbox_messages_workspace_status` (`workspace_id`, `status`, `next_attempt_at`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `outbox_messages`;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `standupers` ADD `deadline` VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `standupers` ADD `tz` VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `standupers` ADD `submission_days` VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `jobs` ADD `user_id` VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `notification_threads` ADD `user_id` VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `standupers` DROP COLUMN `deadline`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `standupers` DROP COLUMN `tz`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `standupers` DROP COLUMN `submission_days`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `jobs` DROP COLUMN `user_id`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `notification_threads` DROP COLUMN `user_id`;
+-- +goose StatementEnd
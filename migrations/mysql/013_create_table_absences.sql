@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `absences` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` BIGINT NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `user_id` VARCHAR(255) NOT NULL,
+    `start_date` VARCHAR(10) NOT NULL,
+    `end_date` VARCHAR(10) NOT NULL,
+    `reason` TEXT NOT NULL,
+    `source` VARCHAR(255) NOT NULL,
+    KEY `absences_workspace_user` (`workspace_id`, `user_id`, `end_date`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `absences`;
+-- +goose StatementEnd
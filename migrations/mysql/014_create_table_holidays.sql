@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `holiday_calendars` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` BIGINT NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `channel_id` VARCHAR(255) NOT NULL,
+    `name` VARCHAR(255) NOT NULL,
+    KEY `holiday_calendars_workspace` (`workspace_id`)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE `holidays` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `calendar_id` INTEGER NOT NULL,
+    `date` VARCHAR(10) NOT NULL,
+    `name` TEXT NOT NULL,
+    UNIQUE KEY `holidays_calendar_date` (`calendar_id`, `date`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `holidays`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `holiday_calendars`;
+-- +goose StatementEnd
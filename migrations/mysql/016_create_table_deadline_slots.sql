@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `deadline_slots` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` BIGINT NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `channel_id` VARCHAR(255) NOT NULL,
+    `name` VARCHAR(255) NOT NULL,
+    `days` VARCHAR(255) NOT NULL,
+    `deadline` VARCHAR(5) NOT NULL,
+    KEY `deadline_slots_channel` (`channel_id`)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `jobs` ADD `slot_id` INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `notification_threads` ADD `slot_id` INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `deadline_slots`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `jobs` DROP COLUMN `slot_id`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `notification_threads` DROP COLUMN `slot_id`;
+-- +goose StatementEnd
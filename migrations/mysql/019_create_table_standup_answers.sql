@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `standup_answers` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `standup_id` INTEGER NOT NULL,
+    `position` INTEGER NOT NULL,
+    `question` VARCHAR(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+    `answer` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    UNIQUE KEY `standup_answers_question` (`standup_id`, `question`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `standup_answers`;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `standups` MODIFY `comment` TEXT COLLATE utf8mb4_unicode_ci NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `standups` MODIFY `comment` VARCHAR(255) COLLATE utf8mb4_unicode_ci NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `dm_standups` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` BIGINT NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `user_id` VARCHAR(255) NOT NULL,
+    `channel_id` VARCHAR(255) NOT NULL,
+    `questions` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    `position` INTEGER NOT NULL,
+    `answers` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    KEY `dm_standups_user` (`workspace_id`, `user_id`)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `standupers` ADD `dm_standup_offset` INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `dm_standups`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `standupers` DROP COLUMN `dm_standup_offset`;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `blockers` (
+    `id` INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `created_at` BIGINT NOT NULL,
+    `workspace_id` VARCHAR(255) NOT NULL,
+    `channel_id` VARCHAR(255) NOT NULL,
+    `user_id` VARCHAR(255) NOT NULL,
+    `standup_id` INTEGER NOT NULL,
+    `text` TEXT COLLATE utf8mb4_unicode_ci NOT NULL,
+    `status` VARCHAR(255) NOT NULL,
+    `resolved_at` BIGINT NOT NULL,
+    `resolved_by` VARCHAR(255) NOT NULL,
+    `escalated_at` BIGINT NOT NULL,
+    KEY `blockers_workspace` (`workspace_id`, `status`)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `workspaces` ADD `blocker_escalation_days` INTEGER NOT NULL DEFAULT 3;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `blockers`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `workspaces` DROP COLUMN `blocker_escalation_days`;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `outbox_limits` (
+    `workspace_id` VARCHAR(255) NOT NULL PRIMARY KEY,
+    `refilled_at` BIGINT NOT NULL,
+    `paused_until` BIGINT NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `outbox_limits`;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `projects` ADD `archived` TINYINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `projects` ADD `bot_removed` TINYINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+-- +goose StatementBegin
+UPDATE `projects` SET `bot_removed`=`inactive`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `projects` DROP COLUMN `bot_removed`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE `projects` DROP COLUMN `archived`;
+-- +goose StatementEnd
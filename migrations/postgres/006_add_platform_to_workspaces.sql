@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workspaces ADD COLUMN platform VARCHAR(255) NOT NULL DEFAULT 'slack';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE workspaces ADD COLUMN server_url VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE workspaces DROP COLUMN platform;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE workspaces DROP COLUMN server_url;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE jobs (
+    id SERIAL PRIMARY KEY,
+    workspace_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(255) NOT NULL,
+    channel_id VARCHAR(255) NOT NULL,
+    spec VARCHAR(1000) NOT NULL,
+    next_run_at BIGINT NOT NULL,
+    last_run_at BIGINT NOT NULL,
+    UNIQUE (workspace_id, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE jobs;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE job_runs (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL,
+    workspace_id VARCHAR(255) NOT NULL,
+    job_name VARCHAR(255) NOT NULL,
+    scheduled_at BIGINT NOT NULL,
+    started_at BIGINT NOT NULL,
+    finished_at BIGINT NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    error TEXT NOT NULL
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX job_runs_job_id ON job_runs (job_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE job_runs;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE holiday_calendars (
+    id SERIAL PRIMARY KEY,
+    created_at BIGINT NOT NULL,
+    workspace_id VARCHAR(255) NOT NULL,
+    channel_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX holiday_calendars_workspace ON holiday_calendars (workspace_id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE holidays (
+    id SERIAL PRIMARY KEY,
+    calendar_id INTEGER NOT NULL,
+    date VARCHAR(10) NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (calendar_id, date)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE holidays;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE holiday_calendars;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE standup_answers (
+    id SERIAL PRIMARY KEY,
+    standup_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    question VARCHAR(255) NOT NULL,
+    answer TEXT NOT NULL
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE UNIQUE INDEX standup_answers_question ON standup_answers (standup_id, question);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE standup_answers;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE standups ALTER COLUMN comment TYPE TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE standups ALTER COLUMN comment TYPE VARCHAR(255);
+-- +goose StatementEnd
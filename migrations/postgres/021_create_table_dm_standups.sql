@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE dm_standups (
+    id SERIAL PRIMARY KEY,
+    created_at BIGINT NOT NULL,
+    workspace_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    channel_id VARCHAR(255) NOT NULL,
+    questions TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    answers TEXT NOT NULL
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX dm_standups_user ON dm_standups (workspace_id, user_id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE standupers ADD COLUMN dm_standup_offset INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE dm_standups;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE standupers DROP COLUMN dm_standup_offset;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workspaces (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at INTEGER NOT NULL,
+    notifier_interval INTEGER NOT NULL,
+    max_reminders INTEGER NOT NULL,
+    reminder_offset INTEGER NOT NULL,
+    workspace_id TEXT NOT NULL,
+    workspace_name TEXT NOT NULL,
+    bot_access_token TEXT NOT NULL,
+    bot_user_id TEXT NOT NULL,
+    projects_reports_enabled BOOLEAN NOT NULL,
+    reporting_channel TEXT NOT NULL,
+    reporting_time TEXT NOT NULL,
+    language TEXT NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE workspaces;
+-- +goose StatementEnd
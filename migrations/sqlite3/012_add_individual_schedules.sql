@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE standupers ADD COLUMN deadline TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE standupers ADD COLUMN tz TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE standupers ADD COLUMN submission_days TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN user_id TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE notification_threads ADD COLUMN user_id TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE standupers DROP COLUMN deadline;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE standupers DROP COLUMN tz;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE standupers DROP COLUMN submission_days;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE jobs DROP COLUMN user_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE notification_threads DROP COLUMN user_id;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE deadline_slots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at INTEGER NOT NULL,
+    workspace_id TEXT NOT NULL,
+    channel_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    days TEXT NOT NULL,
+    deadline TEXT NOT NULL
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX deadline_slots_channel ON deadline_slots (channel_id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN slot_id INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE notification_threads ADD COLUMN slot_id INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE deadline_slots;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE jobs DROP COLUMN slot_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE notification_threads DROP COLUMN slot_id;
+-- +goose StatementEnd
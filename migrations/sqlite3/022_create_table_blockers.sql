@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE blockers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at BIGINT NOT NULL,
+    workspace_id TEXT NOT NULL,
+    channel_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    standup_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    status TEXT NOT NULL,
+    resolved_at BIGINT NOT NULL,
+    resolved_by TEXT NOT NULL,
+    escalated_at BIGINT NOT NULL
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX blockers_workspace ON blockers (workspace_id, status);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE workspaces ADD COLUMN blocker_escalation_days INTEGER NOT NULL DEFAULT 3;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE blockers;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE workspaces DROP COLUMN blocker_escalation_days;
+-- +goose StatementEnd
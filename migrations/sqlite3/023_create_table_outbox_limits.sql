@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE outbox_limits (
+    workspace_id TEXT NOT NULL PRIMARY KEY,
+    refilled_at INTEGER NOT NULL,
+    paused_until INTEGER NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE outbox_limits;
+-- +goose StatementEnd
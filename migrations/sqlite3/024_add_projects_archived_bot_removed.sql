@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE projects ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE projects ADD COLUMN bot_removed BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+-- +goose StatementBegin
+UPDATE projects SET bot_removed=inactive;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE projects DROP COLUMN bot_removed;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE projects DROP COLUMN archived;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workspaces ADD COLUMN tz TEXT NOT NULL DEFAULT 'Asia/Bishkek';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE workspaces DROP COLUMN tz;
+-- +goose StatementEnd
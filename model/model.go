@@ -30,6 +30,14 @@ type Project struct {
 	TZ               string `db:"tz" json:"tz"`
 	OnbordingMessage string `db:"onbording_message" json:"onbording_message,omitempty"`
 	SubmissionDays   string `db:"submission_days" json:"submission_days,omitempty"`
+	// Inactive is set while the channel is archived or the bot is not in it,
+	// nobody is notified there then. Archived and BotRemoved keep the two
+	// reasons apart, so the channel is active again only when both are gone
+	Inactive   bool `db:"inactive" json:"inactive"`
+	Archived   bool `db:"archived" json:"archived"`
+	BotRemoved bool `db:"bot_removed" json:"bot_removed"`
+	// StandupTemplate lists sections expected in standups of the channel
+	StandupTemplate StandupTemplate `db:"standup_template" json:"standup_template,omitempty"`
 }
 
 // Standuper model used for serialization/deserialization stored ChannelMembers
@@ -42,6 +50,14 @@ type Standuper struct {
 	Role        string `db:"role" json:"role"`
 	RealName    string `db:"real_name" json:"real_name"`
 	ChannelName string `db:"channel_name" json:"channel_name"`
+	// Deadline, TZ and SubmissionDays override the channel schedule for
+	// this standuper, empty ones are taken from the channel
+	Deadline       string `db:"deadline" json:"deadline"`
+	TZ             string `db:"tz" json:"tz"`
+	SubmissionDays string `db:"submission_days" json:"submission_days"`
+	// DMStandupOffset is how many minutes before the deadline bot asks
+	// standup questions in direct messages, zero means it does not
+	DMStandupOffset int64 `db:"dm_standup_offset" json:"dm_standup_offset"`
 }
 
 // Workspace is used for updating and storing different bot configuration parameters
@@ -59,8 +75,22 @@ type Workspace struct {
 	ReportingChannel       string `db:"reporting_channel" json:"reporting_channel"`
 	ReportingTime          string `db:"reporting_time" json:"reporting_time"`
 	ProjectsReportsEnabled bool   `db:"projects_reports_enabled" json:"projects_reports_enabled"`
+	Platform               string `db:"platform" json:"platform"`
+	ServerURL              string `db:"server_url" json:"server_url"`
+	// BlockerEscalationDays is how many days a blocker stays open before
+	// it is reported to ReportingChannel, zero means it is not
+	BlockerEscalationDays int `db:"blocker_escalation_days" json:"blocker_escalation_days"`
+	// TZ is the time zone of workspace reports and reminders
+	TZ string `db:"tz" json:"tz"`
 }
 
+// Chat platforms workspace can belong to
+const (
+	PlatformSlack      = "slack"
+	PlatformMattermost = "mattermost"
+	PlatformTelegram   = "telegram"
+)
+
 // ServiceEvent event coming from services
 type ServiceEvent struct {
 	TeamName    string             `json:"team_name"`
@@ -104,6 +134,240 @@ type NotificationThread struct {
 	UserIDs          string `db:"user_ids" json:"user_ids"`
 	NotificationTime int64  `db:"notification_time" json:"notification_time"`
 	ReminderCounter  int    `db:"reminder_counter" json:"reminder_counter"`
+	// UserID is set for threads of a standuper with individual schedule
+	UserID string `db:"user_id" json:"user_id"`
+	// SlotID is set for threads of a deadline slot of the channel
+	SlotID int64 `db:"slot_id" json:"slot_id"`
+}
+
+// Job is a scheduled piece of bot work. NextRunAt is persisted, so work
+// which was due while Comedian was down is caught up after restart
+type Job struct {
+	ID          int64  `db:"id" json:"id"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	Name        string `db:"name" json:"name"`
+	Kind        string `db:"kind" json:"kind"`
+	ChannelID   string `db:"channel_id" json:"channel_id"`
+	UserID      string `db:"user_id" json:"user_id"`
+	SlotID      int64  `db:"slot_id" json:"slot_id"`
+	Spec        string `db:"spec" json:"spec"`
+	NextRunAt   int64  `db:"next_run_at" json:"next_run_at"`
+	LastRunAt   int64  `db:"last_run_at" json:"last_run_at"`
+}
+
+// Kinds of jobs
+const (
+	JobWarning          = "warning"
+	JobAlarm            = "alarm"
+	JobReminder         = "reminder"
+	JobDailyReport      = "daily_report"
+	JobWeeklyReport     = "weekly_report"
+	JobWorklogsReminder = "worklogs_reminder"
+	JobDMStandup        = "dm_standup"
+	JobBlockersDigest   = "blockers_digest"
+)
+
+// JobRun is an outcome of a single run of the job
+type JobRun struct {
+	ID          int64  `db:"id" json:"id"`
+	JobID       int64  `db:"job_id" json:"job_id"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	JobName     string `db:"job_name" json:"job_name"`
+	ScheduledAt int64  `db:"scheduled_at" json:"scheduled_at"`
+	StartedAt   int64  `db:"started_at" json:"started_at"`
+	FinishedAt  int64  `db:"finished_at" json:"finished_at"`
+	Status      string `db:"status" json:"status"`
+	Error       string `db:"error" json:"error,omitempty"`
+}
+
+// Statuses of job runs
+const (
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+	JobRunMissed    = "missed"
+)
+
+// Lease gives exclusive right to do some work (e.g. run scheduler of a
+// workspace) to one Comedian replica until it expires
+type Lease struct {
+	Name      string `db:"name" json:"name"`
+	Holder    string `db:"holder" json:"holder"`
+	ExpiresAt int64  `db:"expires_at" json:"expires_at"`
+}
+
+// Event is an incoming Slack event. Events are stored by Slack event id, so
+// redelivered events are recognized and dropped
+type Event struct {
+	ID            int64  `db:"id" json:"id"`
+	EventID       string `db:"event_id" json:"event_id"`
+	WorkspaceID   string `db:"workspace_id" json:"workspace_id"`
+	Type          string `db:"type" json:"type"`
+	Payload       string `db:"payload" json:"-"`
+	Status        string `db:"status" json:"status"`
+	Attempts      int    `db:"attempts" json:"attempts"`
+	Error         string `db:"error" json:"error,omitempty"`
+	CreatedAt     int64  `db:"created_at" json:"created_at"`
+	NextAttemptAt int64  `db:"next_attempt_at" json:"next_attempt_at"`
+}
+
+// Statuses of events
+const (
+	EventPending    = "pending"
+	EventProcessing = "processing"
+	EventDone       = "done"
+	EventFailed     = "failed"
+)
+
+// OutboxMessage is a message bot sends to the chat. Every message is saved
+// before it is sent, so messages chat failed to accept are retried
+type OutboxMessage struct {
+	ID            int64  `db:"id" json:"id"`
+	WorkspaceID   string `db:"workspace_id" json:"workspace_id"`
+	Type          string `db:"type" json:"type"`
+	ChannelID     string `db:"channel_id" json:"channel_id"`
+	UserID        string `db:"user_id" json:"user_id"`
+	Text          string `db:"text" json:"text"`
+	Attachments   string `db:"attachments" json:"attachments,omitempty"`
+	Status        string `db:"status" json:"status"`
+	Attempts      int    `db:"attempts" json:"attempts"`
+	Error         string `db:"error" json:"error,omitempty"`
+	CreatedAt     int64  `db:"created_at" json:"created_at"`
+	NextAttemptAt int64  `db:"next_attempt_at" json:"next_attempt_at"`
+	SentAt        int64  `db:"sent_at" json:"sent_at"`
+	MessageTS     string `db:"message_ts" json:"message_ts,omitempty"`
+}
+
+// Statuses of outbox messages
+const (
+	OutboxPending = "pending"
+	OutboxSending = "sending"
+	OutboxSent    = "sent"
+	OutboxFailed  = "failed"
+)
+
+// OutboxLimit is the rate limit shared by all replicas posting to a
+// workspace. Times are in milliseconds: the token bucket is full again at
+// RefilledAt and posting stops until PausedUntil after Retry-After
+type OutboxLimit struct {
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	RefilledAt  int64  `db:"refilled_at" json:"refilled_at"`
+	PausedUntil int64  `db:"paused_until" json:"paused_until"`
+}
+
+// Absence is a period when user does not have to submit standups. Dates
+// are inclusive and formatted as DateLayout
+type Absence struct {
+	ID          int64  `db:"id" json:"id"`
+	CreatedAt   int64  `db:"created_at" json:"created_at"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	UserID      string `db:"user_id" json:"user_id"`
+	StartDate   string `db:"start_date" json:"start_date"`
+	EndDate     string `db:"end_date" json:"end_date"`
+	Reason      string `db:"reason" json:"reason"`
+	Source      string `db:"source" json:"source"`
+}
+
+// DateLayout is the format of absence and holiday dates
+const DateLayout = "2006-01-02"
+
+// Sources of absences
+const (
+	AbsenceManual     = "manual"
+	AbsenceChatStatus = "chat_status"
+)
+
+// DeadlineSlot is a standup deadline of the channel on some days of week.
+// Channel with slots is notified about every slot of the day instead of its
+// Deadline. Empty Days mean every submission day, Deadline looks like 15:04
+type DeadlineSlot struct {
+	ID          int64  `db:"id" json:"id"`
+	CreatedAt   int64  `db:"created_at" json:"created_at"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	ChannelID   string `db:"channel_id" json:"channel_id"`
+	Name        string `db:"name" json:"name"`
+	Days        string `db:"days" json:"days"`
+	Deadline    string `db:"deadline" json:"deadline"`
+}
+
+// SlotDeadlineLayout is the format of slot and standuper deadlines
+const SlotDeadlineLayout = "15:04"
+
+// StandupAnswer is the answer to one question of the standup template,
+// Position keeps the order of questions
+type StandupAnswer struct {
+	ID        int64  `db:"id" json:"id"`
+	StandupID int64  `db:"standup_id" json:"standup_id"`
+	Position  int    `db:"position" json:"position"`
+	Question  string `db:"question" json:"question"`
+	Answer    string `db:"answer" json:"answer"`
+}
+
+// DMStandup is a standup bot collects in direct messages asking Questions
+// one at a time. Position is the question asked now, ChannelID is empty
+// while the user picks the project
+type DMStandup struct {
+	ID          int64           `db:"id" json:"id"`
+	CreatedAt   int64           `db:"created_at" json:"created_at"`
+	WorkspaceID string          `db:"workspace_id" json:"workspace_id"`
+	UserID      string          `db:"user_id" json:"user_id"`
+	ChannelID   string          `db:"channel_id" json:"channel_id"`
+	Questions   StandupTemplate `db:"questions" json:"questions"`
+	Position    int             `db:"position" json:"position"`
+	Answers     StandupAnswers  `db:"answers" json:"answers"`
+}
+
+// Blocker is a problem the standuper mentioned in the standup. It stays
+// open until somebody resolves it. StandupID is the latest standup which
+// mentions the blocker, so the blocker goes away with that standup only
+type Blocker struct {
+	ID          int64  `db:"id" json:"id"`
+	CreatedAt   int64  `db:"created_at" json:"created_at"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	ChannelID   string `db:"channel_id" json:"channel_id"`
+	UserID      string `db:"user_id" json:"user_id"`
+	StandupID   int64  `db:"standup_id" json:"standup_id"`
+	Text        string `db:"text" json:"text"`
+	Status      string `db:"status" json:"status"`
+	ResolvedAt  int64  `db:"resolved_at" json:"resolved_at"`
+	ResolvedBy  string `db:"resolved_by" json:"resolved_by"`
+	EscalatedAt int64  `db:"escalated_at" json:"escalated_at"`
+}
+
+// Statuses of blockers
+const (
+	BlockerOpen     = "open"
+	BlockerResolved = "resolved"
+)
+
+// Age returns how many full days the blocker is open at now
+func (b Blocker) Age(now time.Time) int {
+	end := now.Unix()
+	if b.Status == BlockerResolved && b.ResolvedAt != 0 {
+		end = b.ResolvedAt
+	}
+	if end < b.CreatedAt {
+		return 0
+	}
+	return int((end - b.CreatedAt) / int64(24*time.Hour/time.Second))
+}
+
+// HolidayCalendar is a named set of days off. Calendar without ChannelID
+// applies to all channels of the workspace
+type HolidayCalendar struct {
+	ID          int64  `db:"id" json:"id"`
+	CreatedAt   int64  `db:"created_at" json:"created_at"`
+	WorkspaceID string `db:"workspace_id" json:"workspace_id"`
+	ChannelID   string `db:"channel_id" json:"channel_id"`
+	Name        string `db:"name" json:"name"`
+}
+
+// Holiday is a day of holiday calendar when no standups are expected. Date
+// is formatted as DateLayout
+type Holiday struct {
+	ID         int64  `db:"id" json:"id"`
+	CalendarID int64  `db:"calendar_id" json:"calendar_id"`
+	Date       string `db:"date" json:"date"`
+	Name       string `db:"name" json:"name"`
 }
 
 // Validate validates Standup struct
@@ -159,11 +423,35 @@ func (bs Workspace) Validate() error {
 		return err
 	}
 
+	if bs.BlockerEscalationDays < 0 {
+		err := errors.New("blocker escalation days cannot be negative")
+		return err
+	}
+
+	if bs.TZ != "" {
+		_, err := time.LoadLocation(bs.TZ)
+		if err != nil {
+			return errors.New("unknown time zone " + bs.TZ)
+		}
+	}
+
 	if bs.Language == "" {
 		err := errors.New("language cannot be empty")
 		return err
 	}
 
+	switch bs.Platform {
+	case "", PlatformSlack, PlatformTelegram:
+	case PlatformMattermost:
+		if bs.ServerURL == "" {
+			err := errors.New("server url cannot be empty for mattermost workspace")
+			return err
+		}
+	default:
+		err := errors.New("unsupported platform: " + bs.Platform)
+		return err
+	}
+
 	return nil
 }
 
@@ -184,7 +472,12 @@ func (ch Project) Validate() error {
 		return err
 	}
 
-	return nil
+	_, err := ParseWeekdays(ch.SubmissionDays)
+	if err != nil {
+		return err
+	}
+
+	return ch.StandupTemplate.Validate()
 }
 
 // Validate validates Standuper struct
@@ -204,6 +497,120 @@ func (s Standuper) Validate() error {
 		return err
 	}
 
+	if s.TZ != "" {
+		_, err := time.LoadLocation(s.TZ)
+		if err != nil {
+			return errors.New("unknown time zone " + s.TZ)
+		}
+	}
+
+	if s.Deadline != "" {
+		_, err := time.Parse(SlotDeadlineLayout, s.Deadline)
+		if err != nil {
+			return errors.New("deadline must look like " + SlotDeadlineLayout)
+		}
+	}
+
+	_, err := ParseWeekdays(s.SubmissionDays)
+	if err != nil {
+		return err
+	}
+
+	if s.DMStandupOffset < 0 || s.DMStandupOffset >= 24*60 {
+		return errors.New("DM standup offset should be less than a day")
+	}
+
+	return nil
+}
+
+// HasSchedule tells if standuper does not follow the channel schedule
+func (s Standuper) HasSchedule() bool {
+	return s.Deadline != "" || s.TZ != "" || s.SubmissionDays != ""
+}
+
+// Validate validates Event struct
+func (e Event) Validate() error {
+	if e.EventID == "" {
+		return errors.New("event ID cannot be empty")
+	}
+	if e.Status == "" {
+		return errors.New("event status cannot be empty")
+	}
+	return nil
+}
+
+// Validate validates OutboxMessage struct
+func (m OutboxMessage) Validate() error {
+	if m.WorkspaceID == "" {
+		return errors.New("workspace ID cannot be empty")
+	}
+	if m.ChannelID == "" && m.UserID == "" {
+		return errors.New("channel ID and user ID cannot be both empty")
+	}
+	if m.Status == "" {
+		return errors.New("message status cannot be empty")
+	}
+	return nil
+}
+
+// Validate validates Absence struct
+func (a Absence) Validate() error {
+	if a.WorkspaceID == "" {
+		return errors.New("workspace ID cannot be empty")
+	}
+	if a.UserID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	start, err := time.Parse(DateLayout, a.StartDate)
+	if err != nil {
+		return errors.New("start date must look like " + DateLayout)
+	}
+	end, err := time.Parse(DateLayout, a.EndDate)
+	if err != nil {
+		return errors.New("end date must look like " + DateLayout)
+	}
+	if end.Before(start) {
+		return errors.New("absence cannot end before it starts")
+	}
+	return nil
+}
+
+// Validate validates DeadlineSlot struct
+func (ds DeadlineSlot) Validate() error {
+	if ds.WorkspaceID == "" {
+		return errors.New("workspace ID cannot be empty")
+	}
+	if ds.ChannelID == "" {
+		return errors.New("channel ID cannot be empty")
+	}
+	_, err := time.Parse(SlotDeadlineLayout, ds.Deadline)
+	if err != nil {
+		return errors.New("deadline must look like " + SlotDeadlineLayout)
+	}
+	_, err = ParseWeekdays(ds.Days)
+	return err
+}
+
+// Validate validates HolidayCalendar struct
+func (hc HolidayCalendar) Validate() error {
+	if hc.WorkspaceID == "" {
+		return errors.New("workspace ID cannot be empty")
+	}
+	if strings.TrimSpace(hc.Name) == "" {
+		return errors.New("calendar name cannot be empty")
+	}
+	return nil
+}
+
+// Validate validates Holiday struct
+func (h Holiday) Validate() error {
+	if h.CalendarID == 0 {
+		return errors.New("calendar ID cannot be empty")
+	}
+	_, err := time.Parse(DateLayout, h.Date)
+	if err != nil {
+		return errors.New("date must look like " + DateLayout)
+	}
 	return nil
 }
 
@@ -223,3 +630,62 @@ func (nt NotificationThread) Validate() error {
 	}
 	return nil
 }
+
+// Validate validates Job struct
+func (j Job) Validate() error {
+	if j.WorkspaceID == "" {
+		return errors.New("workspace ID cannot be empty")
+	}
+	if j.Name == "" {
+		return errors.New("job name cannot be empty")
+	}
+	if j.Kind == "" {
+		return errors.New("job kind cannot be empty")
+	}
+	return nil
+}
+
+// Validate validates StandupAnswer struct
+func (a StandupAnswer) Validate() error {
+	if a.StandupID == 0 {
+		return errors.New("standup ID cannot be empty")
+	}
+	if a.Question == "" {
+		return errors.New("question cannot be empty")
+	}
+	return nil
+}
+
+// Validate validates DMStandup struct
+func (d DMStandup) Validate() error {
+	if d.WorkspaceID == "" {
+		return errors.New("workspace ID cannot be empty")
+	}
+	if d.UserID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	if d.Position < 0 || d.Position > len(d.Questions) {
+		return errors.New("position is out of questions")
+	}
+	return nil
+}
+
+// Validate validates Blocker struct
+func (b Blocker) Validate() error {
+	if b.WorkspaceID == "" {
+		return errors.New("workspace ID cannot be empty")
+	}
+	if b.ChannelID == "" {
+		return errors.New("channel ID cannot be empty")
+	}
+	if b.UserID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	if strings.TrimSpace(b.Text) == "" {
+		return errors.New("blocker text cannot be empty")
+	}
+	if b.Status != BlockerOpen && b.Status != BlockerResolved {
+		return errors.New("blocker status should be open or resolved")
+	}
+	return nil
+}
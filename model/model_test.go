@@ -3,6 +3,7 @@ package model
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -124,6 +125,33 @@ func TestStanduper(t *testing.T) {
 	}
 }
 
+func TestStanduperDeadline(t *testing.T) {
+	testCases := []struct {
+		deadline     string
+		errorMessage string
+	}{
+		{"", ""},
+		{"11:00", ""},
+		{"9:30", ""},
+		{"10am", "deadline must look like 15:04"},
+		{"25:00", "deadline must look like 15:04"},
+	}
+	for _, tt := range testCases {
+		bs := Standuper{
+			WorkspaceID: "workspaceID",
+			UserID:      "userID",
+			ChannelID:   "channelID",
+			Deadline:    tt.deadline,
+		}
+		err := bs.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, tt.errorMessage)
+		}
+	}
+}
+
 func TestNotificationThread(t *testing.T) {
 	testCases := []struct {
 		channelid        string
@@ -151,3 +179,47 @@ func TestNotificationThread(t *testing.T) {
 		}
 	}
 }
+
+func TestWorkspacePlatform(t *testing.T) {
+	testCases := []struct {
+		platform     string
+		serverURL    string
+		errorMessage string
+	}{
+		{"", "", ""},
+		{PlatformSlack, "", ""},
+		{PlatformMattermost, "", "server url cannot be empty for mattermost workspace"},
+		{PlatformMattermost, "https://chat.example.com", ""},
+		{PlatformTelegram, "", ""},
+		{"irc", "", "unsupported platform: irc"},
+	}
+	for _, tt := range testCases {
+		bs := Workspace{
+			WorkspaceID:    "tID",
+			WorkspaceName:  "tName",
+			BotAccessToken: "accToken",
+			ReminderOffset: 1,
+			ReportingTime:  "01:00",
+			Language:       "en_US",
+			Platform:       tt.platform,
+			ServerURL:      tt.serverURL,
+		}
+		err := bs.Validate()
+		if tt.errorMessage == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, tt.errorMessage)
+		}
+	}
+}
+
+func TestBlockerAge(t *testing.T) {
+	created := time.Date(2019, 11, 4, 9, 0, 0, 0, time.UTC)
+	b := Blocker{CreatedAt: created.Unix(), Status: BlockerOpen}
+	assert.Equal(t, 0, b.Age(created.Add(23*time.Hour)))
+	assert.Equal(t, 2, b.Age(created.Add(49*time.Hour)))
+
+	b.Status = BlockerResolved
+	b.ResolvedAt = created.Add(25 * time.Hour).Unix()
+	assert.Equal(t, 1, b.Age(created.Add(100*time.Hour)))
+}
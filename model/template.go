@@ -0,0 +1,221 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StandupSection is a part of standup the project expects, like "Done" or
+// "Blocked". It is found in the standup by any of its keywords or by its
+// regular expression, both case insensitive
+type StandupSection struct {
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords,omitempty"`
+	Pattern  string   `json:"pattern,omitempty"`
+	Required bool     `json:"required"`
+	// Blockers marks the section which lists problems of the standuper,
+	// they are tracked until resolved
+	Blockers bool `json:"blockers,omitempty"`
+}
+
+// StandupTemplate lists sections of standups of the project, empty one
+// means the default yesterday, today and problems sections
+type StandupTemplate []StandupSection
+
+// DefaultStandupTemplate is used by projects without their own template
+var DefaultStandupTemplate = StandupTemplate{
+	{Name: "yesterday", Keywords: []string{"yesterday", "friday", "вчера", "пятниц"}, Required: true},
+	{Name: "today", Keywords: []string{"today", "сегодня"}, Required: true},
+	{Name: "problems", Keywords: []string{"issue", "мешает"}, Required: true, Blockers: true},
+}
+
+// Validate checks that every section has a unique name, something to be
+// found by and a valid regular expression
+func (t StandupTemplate) Validate() error {
+	names := map[string]bool{}
+	for _, s := range t {
+		name := strings.ToLower(strings.TrimSpace(s.Name))
+		if name == "" {
+			return errors.New("section name cannot be empty")
+		}
+		if names[name] {
+			return fmt.Errorf("section %q is duplicated", s.Name)
+		}
+		names[name] = true
+
+		if len(s.Keywords) == 0 && s.Pattern == "" {
+			return fmt.Errorf("section %q needs keywords or a pattern", s.Name)
+		}
+		for _, k := range s.Keywords {
+			if strings.TrimSpace(k) == "" {
+				return fmt.Errorf("section %q has an empty keyword", s.Name)
+			}
+		}
+		if _, err := s.Regexp(); err != nil {
+			return fmt.Errorf("section %q has invalid pattern: %v", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// Regexp compiles pattern of the section, nil when there is no pattern
+func (s StandupSection) Regexp() (*regexp.Regexp, error) {
+	if s.Pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("(?i)" + s.Pattern)
+}
+
+// Matches tells if the standup contains the section
+func (s StandupSection) Matches(text string) bool {
+	return s.Find(text) != nil
+}
+
+// Find returns location of the earliest keyword or pattern match of the
+// section in the standup, nil when there is none
+func (s StandupSection) Find(text string) []int {
+	var found []int
+	for _, k := range s.Keywords {
+		re, err := regexp.Compile("(?i)" + regexp.QuoteMeta(strings.TrimSpace(k)))
+		if err != nil {
+			continue
+		}
+		if loc := re.FindStringIndex(text); loc != nil && (found == nil || loc[0] < found[0]) {
+			found = loc
+		}
+	}
+	re, err := s.Regexp()
+	if err == nil && re != nil {
+		if loc := re.FindStringIndex(text); loc != nil && loc[1] > loc[0] && (found == nil || loc[0] < found[0]) {
+			found = loc
+		}
+	}
+	return found
+}
+
+// Split cuts free text standup into answers to sections of the template.
+// Answer to a section is the text between its first keyword and the next
+// section, so "Done: bugs. Doing: tests" gives "bugs" and "tests". Text
+// before the first section is dropped
+func (t StandupTemplate) Split(text string) []StandupAnswer {
+	type mark struct {
+		position   int
+		start, end int
+	}
+	marks := []mark{}
+	for i, section := range t {
+		loc := section.Find(text)
+		if loc == nil {
+			continue
+		}
+		// keywords are often beginnings of words, like issue of issues
+		end := loc[1]
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsLetter(r) {
+				break
+			}
+			end += size
+		}
+		marks = append(marks, mark{position: i, start: loc[0], end: end})
+	}
+	sort.Slice(marks, func(i, j int) bool {
+		return marks[i].start < marks[j].start
+	})
+
+	answers := []StandupAnswer{}
+	for i, m := range marks {
+		end := len(text)
+		if i+1 < len(marks) {
+			end = marks[i+1].start
+		}
+		if m.end > end {
+			continue
+		}
+		answer := strings.Trim(text[m.end:end], answerTrimSet)
+		if answer == "" {
+			continue
+		}
+		answers = append(answers, StandupAnswer{Position: m.position, Question: t[m.position].Name, Answer: answer})
+	}
+	sort.Slice(answers, func(i, j int) bool {
+		return answers[i].Position < answers[j].Position
+	})
+	return answers
+}
+
+// answerTrimSet is cut around answers, it is punctuation between sections
+// and their keywords
+const answerTrimSet = " \t\r\n:;,.-–—*_"
+
+// Value stores the template as JSON text, empty for the default one
+func (t StandupTemplate) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the template stored as JSON text
+func (t *StandupTemplate) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into standup template", src)
+	}
+	if len(data) == 0 {
+		*t = nil
+		return nil
+	}
+	return json.Unmarshal(data, t)
+}
+
+// StandupAnswers are stored as JSON text
+type StandupAnswers []StandupAnswer
+
+// Value stores the answers as JSON text
+func (a StandupAnswers) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the answers stored as JSON text
+func (a *StandupAnswers) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into standup answers", src)
+	}
+	if len(data) == 0 {
+		*a = nil
+		return nil
+	}
+	return json.Unmarshal(data, a)
+}
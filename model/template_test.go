@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandupTemplateValidate(t *testing.T) {
+	testCases := []struct {
+		template StandupTemplate
+		err      string
+	}{
+		{nil, ""},
+		{StandupTemplate{{Name: "Done", Keywords: []string{"done"}}, {Name: "Blocked", Pattern: `block(ed|er)`}}, ""},
+		{StandupTemplate{{Keywords: []string{"done"}}}, "section name cannot be empty"},
+		{StandupTemplate{{Name: "Done", Keywords: []string{"done"}}, {Name: "done", Keywords: []string{"finished"}}}, `section "done" is duplicated`},
+		{StandupTemplate{{Name: "Done"}}, `section "Done" needs keywords or a pattern`},
+		{StandupTemplate{{Name: "Done", Keywords: []string{" "}}}, `section "Done" has an empty keyword`},
+		{StandupTemplate{{Name: "Done", Pattern: "("}}, `section "Done" has invalid pattern: error parsing regexp: missing closing ): ` + "`(?i)(`"},
+	}
+	for _, tc := range testCases {
+		err := tc.template.Validate()
+		if tc.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, tc.err)
+		}
+	}
+}
+
+func TestStandupSectionMatches(t *testing.T) {
+	done := StandupSection{Name: "Done", Keywords: []string{"Done", "сделал"}}
+	assert.True(t, done.Matches("DONE: fixed bugs"))
+	assert.True(t, done.Matches("Сделал отчёт"))
+	assert.False(t, done.Matches("Doing: tests"))
+
+	blocked := StandupSection{Name: "Blocked", Pattern: `^block(ed|er)s?:`}
+	assert.True(t, blocked.Matches("Blockers: none"))
+	assert.False(t, blocked.Matches("no blockers"))
+}
+
+func TestStandupTemplateSplit(t *testing.T) {
+	answer := func(position int, question, text string) StandupAnswer {
+		return StandupAnswer{Position: position, Question: question, Answer: text}
+	}
+	custom := StandupTemplate{
+		{Name: "Done", Keywords: []string{"done"}},
+		{Name: "Doing", Keywords: []string{"doing"}},
+		{Name: "Blocked", Pattern: `block(ed|ers?)`},
+	}
+
+	testCases := []struct {
+		template StandupTemplate
+		text     string
+		answers  []StandupAnswer
+	}{
+		{DefaultStandupTemplate, "<@BOT> Yesterday: fixed bugs\nToday: write tests\nIssues: none", []StandupAnswer{
+			answer(0, "yesterday", "fixed bugs"),
+			answer(1, "today", "write tests"),
+			answer(2, "problems", "none"),
+		}},
+		{DefaultStandupTemplate, "yesterday fixed bugs, today write tests, no issues", []StandupAnswer{
+			answer(0, "yesterday", "fixed bugs"),
+			answer(1, "today", "write tests, no"),
+		}},
+		{DefaultStandupTemplate, "Вчера: отчёт. Сегодня: тесты. Мешает: ничего", []StandupAnswer{
+			answer(0, "yesterday", "отчёт"),
+			answer(1, "today", "тесты"),
+			answer(2, "problems", "ничего"),
+		}},
+		// sections go in order of the template whatever order they are written in
+		{custom, "*Doing*\n- tests\n*Done*\n- bugs\n- docs\n*Blockers*: none", []StandupAnswer{
+			answer(0, "Done", "bugs\n- docs"),
+			answer(1, "Doing", "tests"),
+			answer(2, "Blocked", "none"),
+		}},
+		{custom, "nothing to see here", []StandupAnswer{}},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.answers, tc.template.Split(tc.text), tc.text)
+	}
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Weekdays is a set of days of week when standups are expected
+type Weekdays uint8
+
+// Workdays are days from Monday to Friday
+const Workdays Weekdays = 1<<time.Monday | 1<<time.Tuesday | 1<<time.Wednesday | 1<<time.Thursday | 1<<time.Friday
+
+// AllWeekdays are all days of week
+const AllWeekdays Weekdays = Workdays | 1<<time.Saturday | 1<<time.Sunday
+
+// weekOrder lists days starting from Monday as people usually do
+var weekOrder = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+// dayNames maps English and Russian names and abbreviations to days
+var dayNames = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday, "mo": time.Monday,
+	"понедельник": time.Monday, "пн": time.Monday, "пон": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday, "tu": time.Tuesday,
+	"вторник": time.Tuesday, "вт": time.Tuesday, "вто": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday, "we": time.Wednesday,
+	"среда": time.Wednesday, "среду": time.Wednesday, "ср": time.Wednesday, "сре": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "th": time.Thursday,
+	"четверг": time.Thursday, "чт": time.Thursday, "чет": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "fr": time.Friday,
+	"пятница": time.Friday, "пятницу": time.Friday, "пт": time.Friday, "пят": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday, "sa": time.Saturday,
+	"суббота": time.Saturday, "субботу": time.Saturday, "сб": time.Saturday, "суб": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday, "su": time.Sunday,
+	"воскресенье": time.Sunday, "вс": time.Sunday, "вос": time.Sunday, "вск": time.Sunday,
+}
+
+// dayGroups maps words which mean several days at once
+var dayGroups = map[string]Weekdays{
+	"weekdays": Workdays, "workdays": Workdays, "будни": Workdays,
+	"weekends": 1<<time.Saturday | 1<<time.Sunday, "weekend": 1<<time.Saturday | 1<<time.Sunday, "выходные": 1<<time.Saturday | 1<<time.Sunday,
+	"daily": AllWeekdays, "everyday": AllWeekdays, "ежедневно": AllWeekdays,
+}
+
+// rangeDash joins ends of ranges like "mon - fri" or "пн – пт"
+var rangeDash = regexp.MustCompile(`\s*(-|–|—|\.\.)\s*`)
+
+// ParseWeekdays recognizes days in text like "monday, wednesday", "mon-fri",
+// "пн, вт" or "weekdays". Empty text means no days
+func ParseWeekdays(text string) (Weekdays, error) {
+	var days Weekdays
+
+	text = rangeDash.ReplaceAllString(strings.ToLower(text), "-")
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\n'
+	})
+
+	for _, word := range words {
+		word = strings.Trim(word, ".")
+		if word == "and" || word == "и" || word == "" {
+			continue
+		}
+		if group, ok := dayGroups[word]; ok {
+			days |= group
+			continue
+		}
+
+		ends := strings.Split(word, "-")
+		if len(ends) > 2 {
+			return 0, fmt.Errorf("could not recognize days %q", word)
+		}
+		first, ok := dayNames[strings.Trim(ends[0], ".")]
+		if !ok {
+			return 0, fmt.Errorf("could not recognize day %q", ends[0])
+		}
+		last := first
+		if len(ends) == 2 {
+			last, ok = dayNames[strings.Trim(ends[1], ".")]
+			if !ok {
+				return 0, fmt.Errorf("could not recognize day %q", ends[1])
+			}
+		}
+
+		// ranges may go over the weekend, like fri-mon
+		for d := first; ; d = (d + 1) % 7 {
+			days |= 1 << d
+			if d == last {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
+
+// NormalizeWeekdays returns days of text in the form they are stored in
+func NormalizeWeekdays(text string) (string, error) {
+	days, err := ParseWeekdays(text)
+	if err != nil {
+		return text, err
+	}
+	return days.String(), nil
+}
+
+// Has tells if the day is in the set
+func (w Weekdays) Has(day time.Weekday) bool {
+	return w&(1<<day) != 0
+}
+
+// String returns lower case English names of the days starting from Monday,
+// like "monday, tuesday, friday"
+func (w Weekdays) String() string {
+	var names []string
+	for _, d := range weekOrder {
+		if w.Has(d) {
+			names = append(names, strings.ToLower(d.String()))
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWeekdays(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected string
+		err      string
+	}{
+		{"", "", ""},
+		{"monday, tuesday, wednesday, thursday, friday", "monday, tuesday, wednesday, thursday, friday", ""},
+		{"Friday, Monday", "monday, friday", ""},
+		{"mon-fri", "monday, tuesday, wednesday, thursday, friday", ""},
+		{"Mon - Wed and Fri", "monday, tuesday, wednesday, friday", ""},
+		{"fri-mon", "monday, friday, saturday, sunday", ""},
+		{"tue..thu", "tuesday, wednesday, thursday", ""},
+		{"пн, вт", "monday, tuesday", ""},
+		{"ПН–ПТ", "monday, tuesday, wednesday, thursday, friday", ""},
+		{"понедельник и среду", "monday, wednesday", ""},
+		{"weekdays", "monday, tuesday, wednesday, thursday, friday", ""},
+		{"daily", "monday, tuesday, wednesday, thursday, friday, saturday, sunday", ""},
+		{"mondays", "", `could not recognize day "mondays"`},
+		{"mon-", "", `could not recognize day ""`},
+		{"mon-wed-fri", "", `could not recognize days "mon-wed-fri"`},
+	}
+	for _, tc := range testCases {
+		days, err := ParseWeekdays(tc.text)
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err, tc.text)
+			continue
+		}
+		assert.NoError(t, err, tc.text)
+		assert.Equal(t, tc.expected, days.String(), tc.text)
+	}
+}
+
+func TestWeekdaysHas(t *testing.T) {
+	assert.True(t, Workdays.Has(time.Monday))
+	assert.False(t, Workdays.Has(time.Sunday))
+	assert.True(t, AllWeekdays.Has(time.Sunday))
+	assert.False(t, Weekdays(0).Has(time.Monday))
+
+	text, err := NormalizeWeekdays("вс, сб")
+	assert.NoError(t, err)
+	assert.Equal(t, "saturday, sunday", text)
+	text, err = NormalizeWeekdays("someday")
+	assert.Error(t, err)
+	assert.Equal(t, "someday", text)
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/maddevsio/comedian/model"
+	"github.com/pressly/goose"
+	log "github.com/sirupsen/logrus"
+)
+
+// Submission days used to be free text. The migration rewrites them in
+// normalized form, like "monday, tuesday", so every stored value passes
+// validation. Text which can not be parsed keeps the days it used to mean:
+// the ones whose names it contains. It is a Go migration because SQL cannot
+// parse them
+func init() {
+	goose.AddMigration(normalizeSubmissionDays, nil)
+}
+
+func normalizeSubmissionDays(tx *sql.Tx) error {
+	for _, table := range []string{"projects", "standupers"} {
+		err := normalizeTableSubmissionDays(tx, table)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeTableSubmissionDays(tx *sql.Tx, table string) error {
+	rows, err := tx.Query("SELECT id, submission_days FROM " + table)
+	if err != nil {
+		return err
+	}
+
+	updates := map[int64]string{}
+	for rows.Next() {
+		var id int64
+		var days string
+		err := rows.Scan(&id, &days)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		normalized, err := model.NormalizeWeekdays(days)
+		if err != nil {
+			normalized = legacyWeekdays(days).String()
+			log.Warningf("%v %v: submission days %q are replaced with %q: %v", table, id, days, normalized, err)
+		}
+		if normalized != days {
+			updates[id] = normalized
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	query := "UPDATE " + table + " SET submission_days=? WHERE id=?"
+	if _, ok := goose.GetDialect().(*goose.PostgresDialect); ok {
+		query = sqlx.Rebind(sqlx.DOLLAR, query)
+	}
+	for id, days := range updates {
+		_, err := tx.Exec(query, days, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legacyWeekdays returns days free text submission days used to mean:
+// a day was a submission day when the text contained its name
+func legacyWeekdays(text string) model.Weekdays {
+	var days model.Weekdays
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.Contains(text, strings.ToLower(d.String())) {
+			days |= 1 << d
+		}
+	}
+	return days
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSubmissionDays(t *testing.T) {
+	sqlDB, ok := db.(*DB)
+	if !ok {
+		t.Skip("migrations run only in SQL databases")
+	}
+
+	// free text days written before they were validated
+	for i, days := range []string{"пн-пт", "monday, tuesday", "someday", "monday, wensday and friday"} {
+		_, err := sqlDB.exec(
+			"INSERT INTO projects (created_at, workspace_id, channel_name, channel_id, deadline, tz, onbording_message, submission_days) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			0, "normalizeTeam", "general", fmt.Sprintf("CNORM%v", i), "", "UTC", "", days,
+		)
+		require.NoError(t, err)
+	}
+
+	tx, err := sqlDB.db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, normalizeSubmissionDays(tx))
+	require.NoError(t, tx.Commit())
+
+	var days []string
+	err = sqlDB.selectAll(&days, "SELECT submission_days FROM projects WHERE workspace_id=? ORDER BY id", "normalizeTeam")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"monday, tuesday, wednesday, thursday, friday", "monday, tuesday", "", "monday, friday"}, days)
+
+	// projects whose days could not be parsed are updated as others
+	project, err := db.SelectProject("CNORM3")
+	require.NoError(t, err)
+	project.Deadline = "10:00"
+	_, err = db.UpdateProject(project)
+	require.NoError(t, err)
+
+	_, err = sqlDB.exec("DELETE FROM projects WHERE workspace_id=?", "normalizeTeam")
+	require.NoError(t, err)
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateAbsence creates absence entry in database
+func (m *DB) CreateAbsence(a model.Absence) (model.Absence, error) {
+	err := a.Validate()
+	if err != nil {
+		return a, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO absences (
+			created_at,
+			workspace_id,
+			user_id,
+			start_date,
+			end_date,
+			reason,
+			source
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.CreatedAt,
+		a.WorkspaceID,
+		a.UserID,
+		a.StartDate,
+		a.EndDate,
+		a.Reason,
+		a.Source,
+	)
+	if err != nil {
+		return a, err
+	}
+	a.ID = id
+
+	return a, nil
+}
+
+// GetAbsence selects absence entry from database
+func (m *DB) GetAbsence(id int64) (model.Absence, error) {
+	var a model.Absence
+	err := m.get(&a, "SELECT * FROM absences WHERE id=?", id)
+	return a, err
+}
+
+// DeleteAbsence deletes absence entry from database
+func (m *DB) DeleteAbsence(id int64) error {
+	_, err := m.exec("DELETE FROM absences WHERE id=?", id)
+	return err
+}
+
+// ListWorkspaceAbsences returns absences of the workspace which end on or
+// after the given date, all absences if the date is empty
+func (m *DB) ListWorkspaceAbsences(workspaceID, from string) ([]model.Absence, error) {
+	items := []model.Absence{}
+	err := m.selectAll(&items, "SELECT * FROM absences WHERE workspace_id=? AND end_date>=? ORDER BY start_date, id", workspaceID, from)
+	return items, err
+}
+
+// ListUserAbsences returns absences of the user which overlap the dates
+func (m *DB) ListUserAbsences(workspaceID, userID, from, to string) ([]model.Absence, error) {
+	items := []model.Absence{}
+	err := m.selectAll(&items,
+		"SELECT * FROM absences WHERE workspace_id=? AND user_id=? AND start_date<=? AND end_date>=? ORDER BY start_date, id",
+		workspaceID, userID, to, from,
+	)
+	return items, err
+}
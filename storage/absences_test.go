@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbsences(t *testing.T) {
+	_, err := db.CreateAbsence(model.Absence{WorkspaceID: "absencesTeam", UserID: "U1", StartDate: "2019-12-23", EndDate: "2019-12-20"})
+	assert.Error(t, err, "absence ends before it starts")
+	_, err = db.CreateAbsence(model.Absence{WorkspaceID: "absencesTeam", UserID: "U1", StartDate: "23.12.2019", EndDate: "2019-12-20"})
+	assert.Error(t, err)
+
+	vacation, err := db.CreateAbsence(model.Absence{
+		WorkspaceID: "absencesTeam",
+		UserID:      "U1",
+		StartDate:   "2019-12-23",
+		EndDate:     "2020-01-03",
+		Reason:      "skiing",
+		Source:      model.AbsenceManual,
+	})
+	require.NoError(t, err)
+	sick, err := db.CreateAbsence(model.Absence{
+		WorkspaceID: "absencesTeam",
+		UserID:      "U2",
+		StartDate:   "2019-12-20",
+		EndDate:     "2019-12-20",
+		Source:      model.AbsenceChatStatus,
+	})
+	require.NoError(t, err)
+
+	a, err := db.GetAbsence(vacation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, vacation, a)
+
+	absences, err := db.ListUserAbsences("absencesTeam", "U1", "2019-12-16", "2019-12-22")
+	require.NoError(t, err)
+	assert.Empty(t, absences)
+
+	absences, err = db.ListUserAbsences("absencesTeam", "U1", "2020-01-03", "2020-01-03")
+	require.NoError(t, err)
+	require.Len(t, absences, 1)
+	assert.Equal(t, vacation.ID, absences[0].ID)
+
+	absences, err = db.ListUserAbsences("absencesTeam", "U1", "2019-12-16", "2019-12-31")
+	require.NoError(t, err)
+	assert.Len(t, absences, 1)
+
+	absences, err = db.ListWorkspaceAbsences("absencesTeam", "")
+	require.NoError(t, err)
+	require.Len(t, absences, 2)
+	assert.Equal(t, sick.ID, absences[0].ID)
+
+	absences, err = db.ListWorkspaceAbsences("absencesTeam", "2019-12-21")
+	require.NoError(t, err)
+	require.Len(t, absences, 1)
+	assert.Equal(t, vacation.ID, absences[0].ID)
+
+	require.NoError(t, db.DeleteAbsence(vacation.ID))
+	require.NoError(t, db.DeleteAbsence(sick.ID))
+
+	_, err = db.GetAbsence(vacation.ID)
+	assert.Error(t, err)
+}
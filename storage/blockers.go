@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateBlocker creates blocker entry in database
+func (m *DB) CreateBlocker(b model.Blocker) (model.Blocker, error) {
+	err := b.Validate()
+	if err != nil {
+		return b, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO blockers (
+			created_at,
+			workspace_id,
+			channel_id,
+			user_id,
+			standup_id,
+			text,
+			status,
+			resolved_at,
+			resolved_by,
+			escalated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		b.CreatedAt,
+		b.WorkspaceID,
+		b.ChannelID,
+		b.UserID,
+		b.StandupID,
+		b.Text,
+		b.Status,
+		b.ResolvedAt,
+		b.ResolvedBy,
+		b.EscalatedAt,
+	)
+	if err != nil {
+		return b, err
+	}
+	b.ID = id
+
+	return b, nil
+}
+
+// UpdateBlocker updates status, escalation and standup of the blocker
+func (m *DB) UpdateBlocker(b model.Blocker) (model.Blocker, error) {
+	err := b.Validate()
+	if err != nil {
+		return b, err
+	}
+
+	_, err = m.exec(
+		"UPDATE blockers SET standup_id=?, status=?, resolved_at=?, resolved_by=?, escalated_at=? WHERE id=?",
+		b.StandupID, b.Status, b.ResolvedAt, b.ResolvedBy, b.EscalatedAt, b.ID,
+	)
+	if err != nil {
+		return b, err
+	}
+	return m.GetBlocker(b.ID)
+}
+
+// GetBlocker returns blocker by its ID
+func (m *DB) GetBlocker(id int64) (model.Blocker, error) {
+	var b model.Blocker
+	err := m.get(&b, "SELECT * FROM blockers WHERE id=?", id)
+	return b, err
+}
+
+// ListWorkspaceBlockers returns blockers of the workspace with the status,
+// all of them when status is empty, the oldest first
+func (m *DB) ListWorkspaceBlockers(workspaceID, status string) ([]model.Blocker, error) {
+	items := []model.Blocker{}
+	if status == "" {
+		err := m.selectAll(&items, "SELECT * FROM blockers WHERE workspace_id=? ORDER BY id", workspaceID)
+		return items, err
+	}
+	err := m.selectAll(&items, "SELECT * FROM blockers WHERE workspace_id=? AND status=? ORDER BY id", workspaceID, status)
+	return items, err
+}
+
+// DeleteBlocker deletes blocker entry from database
+func (m *DB) DeleteBlocker(id int64) error {
+	_, err := m.exec("DELETE FROM blockers WHERE id=?", id)
+	return err
+}
+
+// DeleteStandupBlockers deletes blockers last mentioned in the standup
+func (m *DB) DeleteStandupBlockers(standupID int64) error {
+	_, err := m.exec("DELETE FROM blockers WHERE standup_id=?", standupID)
+	return err
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockers(t *testing.T) {
+	_, err := db.CreateBlocker(model.Blocker{WorkspaceID: "blockersTeam", ChannelID: "CBLOCK", UserID: "U1", Text: "no access"})
+	assert.Error(t, err, "status is required")
+
+	standup, err := db.CreateStandup(model.Standup{WorkspaceID: "blockersTeam", ChannelID: "CBLOCK", UserID: "U1", MessageTS: "blockers.1"})
+	require.NoError(t, err)
+
+	access, err := db.CreateBlocker(model.Blocker{CreatedAt: 1, WorkspaceID: "blockersTeam", ChannelID: "CBLOCK", UserID: "U1", StandupID: standup.ID, Text: "no access to staging", Status: model.BlockerOpen})
+	require.NoError(t, err)
+	review, err := db.CreateBlocker(model.Blocker{CreatedAt: 2, WorkspaceID: "blockersTeam", ChannelID: "CBLOCK", UserID: "U2", Text: "waiting for review", Status: model.BlockerOpen})
+	require.NoError(t, err)
+
+	later, err := db.CreateStandup(model.Standup{WorkspaceID: "blockersTeam", ChannelID: "CBLOCK", UserID: "U2", MessageTS: "blockers.2"})
+	require.NoError(t, err)
+	review.StandupID = later.ID
+	review.Status = model.BlockerResolved
+	review.ResolvedAt = 3
+	review.ResolvedBy = "PM"
+	updated, err := db.UpdateBlocker(review)
+	require.NoError(t, err)
+	assert.Equal(t, review, updated)
+
+	blockers, err := db.ListWorkspaceBlockers("blockersTeam", model.BlockerOpen)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Blocker{access}, blockers)
+	blockers, err = db.ListWorkspaceBlockers("blockersTeam", "")
+	require.NoError(t, err)
+	assert.Equal(t, []model.Blocker{access, review}, blockers)
+
+	require.NoError(t, db.DeleteStandup(standup.ID))
+	_, err = db.GetBlocker(access.ID)
+	assert.Error(t, err, "blockers of deleted standup are deleted")
+
+	require.NoError(t, db.DeleteStandup(later.ID))
+	blockers, err = db.ListWorkspaceBlockers("blockersTeam", "")
+	require.NoError(t, err)
+	assert.Empty(t, blockers)
+}
@@ -11,7 +11,7 @@ func (m *DB) CreateProject(ch model.Project) (model.Project, error) {
 		return ch, err
 	}
 
-	res, err := m.db.Exec(
+	id, err := m.insert(
 		`INSERT INTO projects (
 			created_at,
 			workspace_id, 
@@ -20,8 +20,9 @@ func (m *DB) CreateProject(ch model.Project) (model.Project, error) {
 			deadline,
 			tz,
 			onbording_message,
-			submission_days
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			submission_days,
+			standup_template
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		ch.CreatedAt,
 		ch.WorkspaceID,
 		ch.ChannelName,
@@ -30,14 +31,11 @@ func (m *DB) CreateProject(ch model.Project) (model.Project, error) {
 		ch.TZ,
 		ch.OnbordingMessage,
 		ch.SubmissionDays,
+		ch.StandupTemplate,
 	)
 	if err != nil {
 		return ch, err
 	}
-	id, err := res.LastInsertId()
-	if err != nil {
-		return ch, err
-	}
 	ch.ID = id
 
 	return ch, nil
@@ -49,17 +47,19 @@ func (m *DB) UpdateProject(ch model.Project) (model.Project, error) {
 	if err != nil {
 		return ch, err
 	}
-	_, err = m.db.Exec(
+	_, err = m.exec(
 		`UPDATE projects SET 
 		deadline=?,
 		tz=?,
 		onbording_message=?,
-		submission_days=? 
+		submission_days=?,
+		standup_template=?
 		WHERE id=?`,
 		ch.Deadline,
 		ch.TZ,
 		ch.OnbordingMessage,
 		ch.SubmissionDays,
+		ch.StandupTemplate,
 		ch.ID,
 	)
 	if err != nil {
@@ -68,24 +68,61 @@ func (m *DB) UpdateProject(ch model.Project) (model.Project, error) {
 	return ch, nil
 }
 
+// RenameProject sets channel name of the project and its standupers
+func (m *DB) RenameProject(channelID, name string) error {
+	_, err := m.exec("UPDATE projects SET channel_name=? WHERE channel_id=?", name, channelID)
+	if err != nil {
+		return err
+	}
+	_, err = m.exec("UPDATE standupers SET channel_name=? WHERE channel_id=?", name, channelID)
+	return err
+}
+
+// SetProjectArchived marks project as archived or unarchived. The project
+// stays inactive while the bot is removed from the channel
+func (m *DB) SetProjectArchived(channelID string, archived bool) error {
+	_, err := m.exec("UPDATE projects SET archived=? WHERE channel_id=?", archived, channelID)
+	if err != nil {
+		return err
+	}
+	return m.updateProjectInactive(channelID)
+}
+
+// SetProjectBotRemoved marks project as left by the bot or joined again.
+// The project stays inactive while the channel is archived
+func (m *DB) SetProjectBotRemoved(channelID string, removed bool) error {
+	_, err := m.exec("UPDATE projects SET bot_removed=? WHERE channel_id=?", removed, channelID)
+	if err != nil {
+		return err
+	}
+	return m.updateProjectInactive(channelID)
+}
+
+// updateProjectInactive derives inactive flag of the project from the
+// stored flags, so events handled at once do not overwrite each other
+func (m *DB) updateProjectInactive(channelID string) error {
+	_, err := m.exec("UPDATE projects SET inactive=(archived OR bot_removed) WHERE channel_id=?", channelID)
+	return err
+}
+
 //ListProjects returns list of projects
 func (m *DB) ListProjects() ([]model.Project, error) {
 	projects := []model.Project{}
-	err := m.db.Select(&projects, "SELECT * FROM `projects`")
+	err := m.selectAll(&projects, "SELECT * FROM projects")
 	return projects, err
 }
 
 //ListWorkspaceProjects returns list of projects
 func (m *DB) ListWorkspaceProjects(ws string) ([]model.Project, error) {
 	projects := []model.Project{}
-	err := m.db.Select(&projects, "SELECT * FROM `projects` where workspace_id=?", ws)
+	err := m.selectAll(&projects, "SELECT * FROM projects where workspace_id=?", ws)
 	return projects, err
 }
 
 // SelectProject selects Project entry from database
 func (m *DB) SelectProject(channelID string) (model.Project, error) {
 	var c model.Project
-	err := m.db.Get(&c, "SELECT * FROM `projects` WHERE channel_id=?", channelID)
+	err := m.get(&c, "SELECT * FROM projects WHERE channel_id=?", channelID)
 	if err != nil {
 		return c, err
 	}
@@ -95,7 +132,7 @@ func (m *DB) SelectProject(channelID string) (model.Project, error) {
 // GetProject selects Project entry from database with specific id
 func (m *DB) GetProject(id int64) (model.Project, error) {
 	var c model.Project
-	err := m.db.Get(&c, "SELECT * FROM `projects` where id=?", id)
+	err := m.get(&c, "SELECT * FROM projects where id=?", id)
 	if err != nil {
 		return c, err
 	}
@@ -104,6 +141,6 @@ func (m *DB) GetProject(id int64) (model.Project, error) {
 
 // DeleteProject deletes Project entry from database
 func (m *DB) DeleteProject(id int64) error {
-	_, err := m.db.Exec("DELETE FROM `projects` WHERE id=?", id)
+	_, err := m.exec("DELETE FROM projects WHERE id=?", id)
 	return err
 }
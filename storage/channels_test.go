@@ -66,5 +66,75 @@ func TestUpdateProjects(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "10:00", ch.Deadline)
 
+	ch.StandupTemplate = model.StandupTemplate{
+		{Name: "Done", Keywords: []string{"done", "finished"}, Required: true},
+		{Name: "Blocked", Pattern: `block(ed|er)`},
+	}
+	_, err = db.UpdateProject(ch)
+	assert.NoError(t, err)
+	ch, err = db.GetProject(ch.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.StandupTemplate{
+		{Name: "Done", Keywords: []string{"done", "finished"}, Required: true},
+		{Name: "Blocked", Pattern: `block(ed|er)`},
+	}, ch.StandupTemplate)
+
+	ch.StandupTemplate = model.StandupTemplate{{Name: "Done", Pattern: "("}}
+	_, err = db.UpdateProject(ch)
+	assert.Error(t, err)
+
+	ch.StandupTemplate = nil
+	_, err = db.UpdateProject(ch)
+	assert.NoError(t, err)
+	ch, err = db.GetProject(ch.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, ch.StandupTemplate)
+
+	assert.NoError(t, db.DeleteProject(ch.ID))
+}
+
+func TestRenameProject(t *testing.T) {
+	ch, err := db.CreateProject(model.Project{
+		WorkspaceID: "foo",
+		ChannelName: "bar",
+		ChannelID:   "bar12",
+	})
+	assert.NoError(t, err)
+	s, err := db.CreateStanduper(model.Standuper{
+		WorkspaceID: "foo",
+		UserID:      "baz",
+		ChannelID:   "bar12",
+		ChannelName: "bar",
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.RenameProject("bar12", "qux"))
+	ch, err = db.SelectProject("bar12")
+	assert.NoError(t, err)
+	assert.Equal(t, "qux", ch.ChannelName)
+	s, err = db.GetStanduper(s.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "qux", s.ChannelName)
+
+	assert.False(t, ch.Inactive)
+	assert.NoError(t, db.SetProjectArchived("bar12", true))
+	ch, err = db.SelectProject("bar12")
+	assert.NoError(t, err)
+	assert.True(t, ch.Inactive)
+	assert.True(t, ch.Archived)
+	// unarchived channel stays inactive while the bot is not in it
+	assert.NoError(t, db.SetProjectBotRemoved("bar12", true))
+	assert.NoError(t, db.SetProjectArchived("bar12", false))
+	ch, err = db.SelectProject("bar12")
+	assert.NoError(t, err)
+	assert.True(t, ch.Inactive)
+	assert.False(t, ch.Archived)
+	assert.True(t, ch.BotRemoved)
+	assert.NoError(t, db.SetProjectBotRemoved("bar12", false))
+	ch, err = db.SelectProject("bar12")
+	assert.NoError(t, err)
+	assert.False(t, ch.Inactive)
+
+	assert.NoError(t, db.DeleteStanduper(s.ID))
 	assert.NoError(t, db.DeleteProject(ch.ID))
 }
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pressly/goose"
+
+	// Drivers of supported databases
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Supported database dialects. Dialect name is used both as sql driver name
+// and as the name of migrations subdirectory
+const (
+	MySQL    = "mysql"
+	Postgres = "postgres"
+	SQLite   = "sqlite3"
+)
+
+// DB provides api for work with SQL database
+type DB struct {
+	db      *sqlx.DB
+	dialect string
+}
+
+// New creates a new instance of database API. Backend is selected by the scheme
+// of dbConn: postgres://, sqlite3:// (or sqlite://) and mysql://. Connection
+// string without scheme is treated as MySQL DSN
+func New(dbConn, migrationsPath string) (*DB, error) {
+	dialect, dsn, err := ParseDatabaseURL(dbConn)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sqlx.Connect(dialect, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if dialect == SQLite {
+		// sqlite does not support concurrent writes, besides every connection
+		// to :memory: database would open a new empty database
+		conn.SetMaxOpenConns(1)
+	}
+
+	db := &DB{db: conn, dialect: dialect}
+
+	err = goose.SetDialect(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := goose.EnsureDBVersion(conn.DB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to EnsureDBVersion: %v", err)
+	}
+
+	migrationsDir := filepath.Join(migrationsPath, dialect)
+
+	// besides SQL files of the dialect there are Go migrations registered
+	// in this package, like 015_normalize_submission_days.go
+	migrations, err := goose.CollectMigrations(migrationsDir, current, goose.MaxVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range migrations {
+		err := m.Up(conn.DB)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+// ParseDatabaseURL returns dialect and driver specific DSN for database url
+func ParseDatabaseURL(dbURL string) (string, string, error) {
+	parts := strings.SplitN(dbURL, "://", 2)
+	if len(parts) == 1 {
+		return MySQL, dbURL, nil
+	}
+
+	scheme, dsn := strings.ToLower(parts[0]), parts[1]
+
+	switch scheme {
+	case "mysql":
+		return MySQL, dsn, nil
+	case "postgres", "postgresql":
+		// lib/pq understands URLs, so it gets the whole thing
+		return Postgres, dbURL, nil
+	case "sqlite", "sqlite3":
+		if dsn == "" {
+			return "", "", fmt.Errorf("sqlite database path is empty")
+		}
+		return SQLite, dsn, nil
+	default:
+		return "", "", fmt.Errorf("unsupported database scheme: %v", scheme)
+	}
+}
+
+// exec executes query rebinding placeholders for current dialect
+func (m *DB) exec(query string, args ...interface{}) (sql.Result, error) {
+	return m.db.Exec(m.db.Rebind(query), args...)
+}
+
+// get selects single row into dest rebinding placeholders for current dialect
+func (m *DB) get(dest interface{}, query string, args ...interface{}) error {
+	return m.db.Get(dest, m.db.Rebind(query), args...)
+}
+
+// selectAll selects rows into dest rebinding placeholders for current dialect
+func (m *DB) selectAll(dest interface{}, query string, args ...interface{}) error {
+	return m.db.Select(dest, m.db.Rebind(query), args...)
+}
+
+// insert executes INSERT query and returns id of the created row. Postgres
+// driver does not support LastInsertId, so RETURNING clause is used instead
+func (m *DB) insert(query string, args ...interface{}) (int64, error) {
+	if m.dialect == Postgres {
+		var id int64
+		err := m.db.QueryRowx(m.db.Rebind(query+" RETURNING id"), args...).Scan(&id)
+		return id, err
+	}
+
+	res, err := m.exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateDeadlineSlot creates deadline slot entry in database
+func (m *DB) CreateDeadlineSlot(ds model.DeadlineSlot) (model.DeadlineSlot, error) {
+	err := ds.Validate()
+	if err != nil {
+		return ds, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO deadline_slots (
+			created_at,
+			workspace_id,
+			channel_id,
+			name,
+			days,
+			deadline
+		) VALUES (?, ?, ?, ?, ?, ?)`,
+		ds.CreatedAt,
+		ds.WorkspaceID,
+		ds.ChannelID,
+		ds.Name,
+		ds.Days,
+		ds.Deadline,
+	)
+	if err != nil {
+		return ds, err
+	}
+	ds.ID = id
+
+	return ds, nil
+}
+
+// UpdateDeadlineSlot updates name, days and deadline of the slot
+func (m *DB) UpdateDeadlineSlot(ds model.DeadlineSlot) (model.DeadlineSlot, error) {
+	err := ds.Validate()
+	if err != nil {
+		return ds, err
+	}
+
+	_, err = m.exec("UPDATE deadline_slots SET name=?, days=?, deadline=? WHERE id=?", ds.Name, ds.Days, ds.Deadline, ds.ID)
+	return ds, err
+}
+
+// GetDeadlineSlot selects deadline slot entry from database
+func (m *DB) GetDeadlineSlot(id int64) (model.DeadlineSlot, error) {
+	var ds model.DeadlineSlot
+	err := m.get(&ds, "SELECT * FROM deadline_slots WHERE id=?", id)
+	return ds, err
+}
+
+// DeleteDeadlineSlot deletes deadline slot entry from database
+func (m *DB) DeleteDeadlineSlot(id int64) error {
+	_, err := m.exec("DELETE FROM deadline_slots WHERE id=?", id)
+	return err
+}
+
+// ListChannelDeadlineSlots returns deadline slots of the channel ordered by deadline
+func (m *DB) ListChannelDeadlineSlots(channelID string) ([]model.DeadlineSlot, error) {
+	items := []model.DeadlineSlot{}
+	err := m.selectAll(&items, "SELECT * FROM deadline_slots WHERE channel_id=? ORDER BY deadline, id", channelID)
+	return items, err
+}
+
+// ListWorkspaceDeadlineSlots returns deadline slots of all channels of the workspace
+func (m *DB) ListWorkspaceDeadlineSlots(workspaceID string) ([]model.DeadlineSlot, error) {
+	items := []model.DeadlineSlot{}
+	err := m.selectAll(&items, "SELECT * FROM deadline_slots WHERE workspace_id=? ORDER BY channel_id, deadline, id", workspaceID)
+	return items, err
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadlineSlots(t *testing.T) {
+	_, err := db.CreateDeadlineSlot(model.DeadlineSlot{WorkspaceID: "slotsTeam", ChannelID: "CSLOTS", Deadline: "10am"})
+	assert.Error(t, err, "deadline is not normalized")
+	_, err = db.CreateDeadlineSlot(model.DeadlineSlot{WorkspaceID: "slotsTeam", ChannelID: "CSLOTS", Deadline: "10:00", Days: "someday"})
+	assert.Error(t, err)
+
+	evening, err := db.CreateDeadlineSlot(model.DeadlineSlot{
+		WorkspaceID: "slotsTeam",
+		ChannelID:   "CSLOTS",
+		Name:        "check-in",
+		Days:        "monday, tuesday, wednesday, thursday, friday",
+		Deadline:    "18:00",
+	})
+	require.NoError(t, err)
+	monday, err := db.CreateDeadlineSlot(model.DeadlineSlot{WorkspaceID: "slotsTeam", ChannelID: "CSLOTS", Days: "monday", Deadline: "12:00"})
+	require.NoError(t, err)
+	other, err := db.CreateDeadlineSlot(model.DeadlineSlot{WorkspaceID: "slotsTeam", ChannelID: "COTHER", Deadline: "09:00"})
+	require.NoError(t, err)
+
+	monday.Deadline = "11:30"
+	_, err = db.UpdateDeadlineSlot(monday)
+	require.NoError(t, err)
+	slot, err := db.GetDeadlineSlot(monday.ID)
+	require.NoError(t, err)
+	assert.Equal(t, monday, slot)
+
+	slots, err := db.ListChannelDeadlineSlots("CSLOTS")
+	require.NoError(t, err)
+	assert.Equal(t, []model.DeadlineSlot{monday, evening}, slots)
+
+	slots, err = db.ListWorkspaceDeadlineSlots("slotsTeam")
+	require.NoError(t, err)
+	assert.Equal(t, []model.DeadlineSlot{other, monday, evening}, slots)
+
+	for _, ds := range []model.DeadlineSlot{monday, evening, other} {
+		require.NoError(t, db.DeleteDeadlineSlot(ds.ID))
+	}
+	_, err = db.GetDeadlineSlot(monday.ID)
+	assert.Error(t, err)
+}
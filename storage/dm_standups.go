@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateDMStandup creates direct messages standup entry in database
+func (m *DB) CreateDMStandup(d model.DMStandup) (model.DMStandup, error) {
+	err := d.Validate()
+	if err != nil {
+		return d, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO dm_standups (
+			created_at,
+			workspace_id,
+			user_id,
+			channel_id,
+			questions,
+			position,
+			answers
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		d.CreatedAt,
+		d.WorkspaceID,
+		d.UserID,
+		d.ChannelID,
+		d.Questions,
+		d.Position,
+		d.Answers,
+	)
+	if err != nil {
+		return d, err
+	}
+	d.ID = id
+
+	return d, nil
+}
+
+// UpdateDMStandup saves the project, questions and answers of the standup
+// if it still has the project and position it was read with, so two
+// messages sent at once do not answer the same question. It reports
+// whether the standup is saved
+func (m *DB) UpdateDMStandup(d model.DMStandup, channelID string, position int) (bool, error) {
+	err := d.Validate()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := m.exec(
+		"UPDATE dm_standups SET channel_id=?, questions=?, position=?, answers=? WHERE id=? AND channel_id=? AND position=?",
+		d.ChannelID, d.Questions, d.Position, d.Answers, d.ID, channelID, position,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// ListUserDMStandups returns direct messages standups of the user, the one
+// started first goes first
+func (m *DB) ListUserDMStandups(workspaceID, userID string) ([]model.DMStandup, error) {
+	items := []model.DMStandup{}
+	err := m.selectAll(&items, "SELECT * FROM dm_standups WHERE workspace_id=? AND user_id=? ORDER BY id", workspaceID, userID)
+	return items, err
+}
+
+// DeleteDMStandup deletes direct messages standup entry from database
+func (m *DB) DeleteDMStandup(id int64) error {
+	_, err := m.exec("DELETE FROM dm_standups WHERE id=?", id)
+	return err
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDMStandups(t *testing.T) {
+	_, err := db.CreateDMStandup(model.DMStandup{WorkspaceID: "dmTeam"})
+	assert.Error(t, err)
+
+	picking, err := db.CreateDMStandup(model.DMStandup{CreatedAt: 1, WorkspaceID: "dmTeam", UserID: "U1"})
+	require.NoError(t, err)
+	queued, err := db.CreateDMStandup(model.DMStandup{
+		CreatedAt:   2,
+		WorkspaceID: "dmTeam",
+		UserID:      "U1",
+		ChannelID:   "CDM",
+		Questions:   model.StandupTemplate{{Name: "Done", Keywords: []string{"done"}, Required: true}, {Name: "Doing", Keywords: []string{"doing"}}},
+	})
+	require.NoError(t, err)
+	_, err = db.CreateDMStandup(model.DMStandup{WorkspaceID: "dmTeam", UserID: "U2"})
+	require.NoError(t, err)
+
+	items, err := db.ListUserDMStandups("dmTeam", "U1")
+	require.NoError(t, err)
+	assert.Equal(t, []model.DMStandup{picking, queued}, items)
+
+	queued.Position = 3
+	_, err = db.UpdateDMStandup(queued, "CDM", 0)
+	assert.Error(t, err, "position is out of questions")
+
+	queued.Position = 1
+	queued.Answers = model.StandupAnswers{{Position: 0, Question: "Done", Answer: "bugs"}}
+	saved, err := db.UpdateDMStandup(queued, "CDM", 0)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	// the question is answered already
+	answered := queued
+	answered.Answers = model.StandupAnswers{{Position: 0, Question: "Done", Answer: "tests"}}
+	saved, err = db.UpdateDMStandup(answered, "CDM", 0)
+	require.NoError(t, err)
+	assert.False(t, saved)
+
+	require.NoError(t, db.DeleteDMStandup(picking.ID))
+	items, err = db.ListUserDMStandups("dmTeam", "U1")
+	require.NoError(t, err)
+	assert.Equal(t, []model.DMStandup{queued}, items)
+
+	for _, user := range []string{"U1", "U2"} {
+		items, err = db.ListUserDMStandups("dmTeam", user)
+		require.NoError(t, err)
+		for _, d := range items {
+			require.NoError(t, db.DeleteDMStandup(d.ID))
+		}
+	}
+}
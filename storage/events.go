@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// RecordEvent saves event unless event with the same event id is already
+// saved. It returns the saved event and whether it was created now
+func (m *DB) RecordEvent(e model.Event) (model.Event, bool, error) {
+	err := e.Validate()
+	if err != nil {
+		return e, false, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO events (
+			event_id,
+			workspace_id,
+			type,
+			payload,
+			status,
+			attempts,
+			error,
+			created_at,
+			next_attempt_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.EventID,
+		e.WorkspaceID,
+		e.Type,
+		e.Payload,
+		e.Status,
+		e.Attempts,
+		e.Error,
+		e.CreatedAt,
+		e.NextAttemptAt,
+	)
+	if err != nil {
+		// unique event_id violated: the event is a redelivery
+		var existing model.Event
+		if m.get(&existing, "SELECT * FROM events WHERE event_id=?", e.EventID) == nil {
+			return existing, false, nil
+		}
+		return e, false, err
+	}
+	e.ID = id
+
+	return e, true, nil
+}
+
+// ClaimEvent marks event as being processed until the given time and
+// counts the attempt. It reports false if the event is done or another
+// worker holds it
+func (m *DB) ClaimEvent(id, now, until int64) (bool, error) {
+	res, err := m.exec(
+		`UPDATE events SET status=?, attempts=attempts+1, next_attempt_at=?
+		WHERE id=? AND status<>? AND next_attempt_at<=?`,
+		model.EventProcessing, until, id, model.EventDone, now,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// UpdateEvent saves outcome of event processing
+func (m *DB) UpdateEvent(e model.Event) (model.Event, error) {
+	err := e.Validate()
+	if err != nil {
+		return e, err
+	}
+
+	_, err = m.exec(
+		"UPDATE events SET status=?, error=?, next_attempt_at=? WHERE id=?",
+		e.Status, e.Error, e.NextAttemptAt, e.ID,
+	)
+	return e, err
+}
+
+// GetEvent selects event entry from database
+func (m *DB) GetEvent(id int64) (model.Event, error) {
+	var e model.Event
+	err := m.get(&e, "SELECT * FROM events WHERE id=?", id)
+	return e, err
+}
+
+// DeleteDoneEvents deletes done events created before the given time
+func (m *DB) DeleteDoneEvents(before int64) error {
+	_, err := m.exec("DELETE FROM events WHERE status=? AND created_at<?", model.EventDone, before)
+	return err
+}
+
+// ListDueEvents returns events which are not done and are due for an
+// attempt, unless they ran out of attempts
+func (m *DB) ListDueEvents(now int64, maxAttempts, limit int) ([]model.Event, error) {
+	items := []model.Event{}
+	err := m.selectAll(
+		&items,
+		`SELECT * FROM events WHERE status<>? AND next_attempt_at<=? AND attempts<?
+		ORDER BY next_attempt_at, id LIMIT ?`,
+		model.EventDone, now, maxAttempts, limit,
+	)
+	return items, err
+}
+
+// ListWorkspaceEvents returns latest events of the workspace, with the
+// given status only unless status is empty
+func (m *DB) ListWorkspaceEvents(workspaceID, status string, limit int) ([]model.Event, error) {
+	items := []model.Event{}
+	var err error
+	if status == "" {
+		err = m.selectAll(&items, "SELECT * FROM events WHERE workspace_id=? ORDER BY id DESC LIMIT ?", workspaceID, limit)
+	} else {
+		err = m.selectAll(&items, "SELECT * FROM events WHERE workspace_id=? AND status=? ORDER BY id DESC LIMIT ?", workspaceID, status, limit)
+	}
+	return items, err
+}
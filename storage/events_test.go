@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents(t *testing.T) {
+	_, _, err := db.RecordEvent(model.Event{})
+	assert.Error(t, err)
+
+	event := model.Event{
+		EventID:       "Ev1",
+		WorkspaceID:   "eventsTeam",
+		Type:          "message",
+		Payload:       `{"event_id":"Ev1"}`,
+		Status:        model.EventPending,
+		CreatedAt:     100,
+		NextAttemptAt: 100,
+	}
+
+	first, created, err := db.RecordEvent(event)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.NotEqual(t, int64(0), first.ID)
+
+	again, created, err := db.RecordEvent(event)
+	require.NoError(t, err)
+	assert.False(t, created, "redelivered event is not recorded twice")
+	assert.Equal(t, first.ID, again.ID)
+
+	due, err := db.ListDueEvents(100, 3, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, first.ID, due[0].ID)
+
+	ok, err := db.ClaimEvent(first.ID, 100, 400)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = db.ClaimEvent(first.ID, 101, 400)
+	require.NoError(t, err)
+	assert.False(t, ok, "event is held by another worker")
+
+	due, err = db.ListDueEvents(101, 3, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	claimed, err := db.GetEvent(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.EventProcessing, claimed.Status)
+	assert.Equal(t, 1, claimed.Attempts)
+
+	claimed.Status = model.EventFailed
+	claimed.Error = "boom"
+	claimed.NextAttemptAt = 200
+	_, err = db.UpdateEvent(claimed)
+	require.NoError(t, err)
+
+	due, err = db.ListDueEvents(200, 3, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	due, err = db.ListDueEvents(200, 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due, "event ran out of attempts")
+
+	ok, err = db.ClaimEvent(first.ID, 200, 500)
+	require.NoError(t, err)
+	require.True(t, ok)
+	claimed.Status = model.EventDone
+	claimed.Error = ""
+	_, err = db.UpdateEvent(claimed)
+	require.NoError(t, err)
+
+	ok, err = db.ClaimEvent(first.ID, 1000, 1300)
+	require.NoError(t, err)
+	assert.False(t, ok, "done event is not processed again")
+
+	second, _, err := db.RecordEvent(model.Event{EventID: "Ev2", WorkspaceID: "eventsTeam", Status: model.EventFailed, Error: "boom"})
+	require.NoError(t, err)
+
+	events, err := db.ListWorkspaceEvents("eventsTeam", "", 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, second.ID, events[0].ID)
+	assert.Equal(t, 2, events[1].Attempts)
+
+	events, err = db.ListWorkspaceEvents("eventsTeam", model.EventFailed, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "boom", events[0].Error)
+
+	events, err = db.ListWorkspaceEvents("eventsTeam", "", 1)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	require.NoError(t, db.DeleteDoneEvents(first.CreatedAt))
+	events, err = db.ListWorkspaceEvents("eventsTeam", "", 10)
+	require.NoError(t, err)
+	assert.Len(t, events, 2, "events created at the time are kept")
+	require.NoError(t, db.DeleteDoneEvents(first.CreatedAt+1))
+	events, err = db.ListWorkspaceEvents("eventsTeam", "", 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1, "failed events are kept")
+	assert.Equal(t, second.ID, events[0].ID)
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateHolidayCalendar creates holiday calendar entry in database
+func (m *DB) CreateHolidayCalendar(hc model.HolidayCalendar) (model.HolidayCalendar, error) {
+	err := hc.Validate()
+	if err != nil {
+		return hc, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO holiday_calendars (
+			created_at,
+			workspace_id,
+			channel_id,
+			name
+		) VALUES (?, ?, ?, ?)`,
+		hc.CreatedAt,
+		hc.WorkspaceID,
+		hc.ChannelID,
+		hc.Name,
+	)
+	if err != nil {
+		return hc, err
+	}
+	hc.ID = id
+
+	return hc, nil
+}
+
+// UpdateHolidayCalendar updates name of the calendar and the channel it is attached to
+func (m *DB) UpdateHolidayCalendar(hc model.HolidayCalendar) (model.HolidayCalendar, error) {
+	err := hc.Validate()
+	if err != nil {
+		return hc, err
+	}
+
+	_, err = m.exec("UPDATE holiday_calendars SET channel_id=?, name=? WHERE id=?", hc.ChannelID, hc.Name, hc.ID)
+	return hc, err
+}
+
+// GetHolidayCalendar selects holiday calendar entry from database
+func (m *DB) GetHolidayCalendar(id int64) (model.HolidayCalendar, error) {
+	var hc model.HolidayCalendar
+	err := m.get(&hc, "SELECT * FROM holiday_calendars WHERE id=?", id)
+	return hc, err
+}
+
+// ListWorkspaceHolidayCalendars returns holiday calendars of the workspace
+func (m *DB) ListWorkspaceHolidayCalendars(workspaceID string) ([]model.HolidayCalendar, error) {
+	items := []model.HolidayCalendar{}
+	err := m.selectAll(&items, "SELECT * FROM holiday_calendars WHERE workspace_id=? ORDER BY id", workspaceID)
+	return items, err
+}
+
+// DeleteHolidayCalendar deletes holiday calendar with its holidays
+func (m *DB) DeleteHolidayCalendar(id int64) error {
+	_, err := m.exec("DELETE FROM holidays WHERE calendar_id=?", id)
+	if err != nil {
+		return err
+	}
+	_, err = m.exec("DELETE FROM holiday_calendars WHERE id=?", id)
+	return err
+}
+
+// CreateHoliday creates holiday entry in database. Calendar has at most
+// one holiday a day
+func (m *DB) CreateHoliday(h model.Holiday) (model.Holiday, error) {
+	err := h.Validate()
+	if err != nil {
+		return h, err
+	}
+
+	id, err := m.insert(
+		"INSERT INTO holidays (calendar_id, date, name) VALUES (?, ?, ?)",
+		h.CalendarID,
+		h.Date,
+		h.Name,
+	)
+	if err != nil {
+		return h, err
+	}
+	h.ID = id
+
+	return h, nil
+}
+
+// GetHoliday selects holiday entry from database
+func (m *DB) GetHoliday(id int64) (model.Holiday, error) {
+	var h model.Holiday
+	err := m.get(&h, "SELECT * FROM holidays WHERE id=?", id)
+	return h, err
+}
+
+// DeleteHoliday deletes holiday entry from database
+func (m *DB) DeleteHoliday(id int64) error {
+	_, err := m.exec("DELETE FROM holidays WHERE id=?", id)
+	return err
+}
+
+// ListCalendarHolidays returns holidays of the calendar ordered by date
+func (m *DB) ListCalendarHolidays(calendarID int64) ([]model.Holiday, error) {
+	items := []model.Holiday{}
+	err := m.selectAll(&items, "SELECT * FROM holidays WHERE calendar_id=? ORDER BY date", calendarID)
+	return items, err
+}
+
+// ListChannelHolidays returns holidays between the dates from calendars of
+// the workspace which apply to the channel
+func (m *DB) ListChannelHolidays(workspaceID, channelID, from, to string) ([]model.Holiday, error) {
+	items := []model.Holiday{}
+	err := m.selectAll(&items,
+		`SELECT h.* FROM holidays h
+		JOIN holiday_calendars hc ON hc.id=h.calendar_id
+		WHERE hc.workspace_id=? AND (hc.channel_id='' OR hc.channel_id=?) AND h.date>=? AND h.date<=?
+		ORDER BY h.date, h.id`,
+		workspaceID, channelID, from, to,
+	)
+	return items, err
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHolidays(t *testing.T) {
+	_, err := db.CreateHolidayCalendar(model.HolidayCalendar{WorkspaceID: "holidaysTeam"})
+	assert.Error(t, err, "calendar without name")
+
+	national, err := db.CreateHolidayCalendar(model.HolidayCalendar{WorkspaceID: "holidaysTeam", Name: "Kyrgyzstan"})
+	require.NoError(t, err)
+	local, err := db.CreateHolidayCalendar(model.HolidayCalendar{WorkspaceID: "holidaysTeam", Name: "Berlin office"})
+	require.NoError(t, err)
+
+	local.ChannelID = "CBERLIN"
+	_, err = db.UpdateHolidayCalendar(local)
+	require.NoError(t, err)
+	hc, err := db.GetHolidayCalendar(local.ID)
+	require.NoError(t, err)
+	assert.Equal(t, local, hc)
+
+	calendars, err := db.ListWorkspaceHolidayCalendars("holidaysTeam")
+	require.NoError(t, err)
+	assert.Equal(t, []model.HolidayCalendar{national, local}, calendars)
+
+	_, err = db.CreateHoliday(model.Holiday{CalendarID: national.ID, Date: "1.1.2020"})
+	assert.Error(t, err)
+
+	newYear, err := db.CreateHoliday(model.Holiday{CalendarID: national.ID, Date: "2020-01-01", Name: "New Year"})
+	require.NoError(t, err)
+	_, err = db.CreateHoliday(model.Holiday{CalendarID: national.ID, Date: "2020-01-01", Name: "New Year"})
+	assert.Error(t, err, "calendar has one holiday a day")
+	christmas, err := db.CreateHoliday(model.Holiday{CalendarID: national.ID, Date: "2020-01-07", Name: "Christmas"})
+	require.NoError(t, err)
+	berlinChristmas, err := db.CreateHoliday(model.Holiday{CalendarID: local.ID, Date: "2019-12-25", Name: "Christmas"})
+	require.NoError(t, err)
+
+	h, err := db.GetHoliday(newYear.ID)
+	require.NoError(t, err)
+	assert.Equal(t, newYear, h)
+
+	holidays, err := db.ListCalendarHolidays(national.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Holiday{newYear, christmas}, holidays)
+
+	holidays, err = db.ListChannelHolidays("holidaysTeam", "CBISHKEK", "2019-12-01", "2020-01-01")
+	require.NoError(t, err)
+	assert.Equal(t, []model.Holiday{newYear}, holidays)
+
+	holidays, err = db.ListChannelHolidays("holidaysTeam", "CBERLIN", "2019-12-01", "2020-01-01")
+	require.NoError(t, err)
+	assert.Equal(t, []model.Holiday{berlinChristmas, newYear}, holidays)
+
+	holidays, err = db.ListChannelHolidays("anotherTeam", "CBERLIN", "2019-12-01", "2020-01-01")
+	require.NoError(t, err)
+	assert.Empty(t, holidays)
+
+	require.NoError(t, db.DeleteHoliday(christmas.ID))
+	_, err = db.GetHoliday(christmas.ID)
+	assert.Error(t, err)
+
+	require.NoError(t, db.DeleteHolidayCalendar(national.ID))
+	require.NoError(t, db.DeleteHolidayCalendar(local.ID))
+	_, err = db.GetHolidayCalendar(national.ID)
+	assert.Error(t, err)
+	_, err = db.GetHoliday(newYear.ID)
+	assert.Error(t, err)
+}
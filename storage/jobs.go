@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateJob creates job entry in database
+func (m *DB) CreateJob(j model.Job) (model.Job, error) {
+	err := j.Validate()
+	if err != nil {
+		return j, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO jobs (
+			workspace_id,
+			name,
+			kind,
+			channel_id,
+			user_id,
+			slot_id,
+			spec,
+			next_run_at,
+			last_run_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		j.WorkspaceID,
+		j.Name,
+		j.Kind,
+		j.ChannelID,
+		j.UserID,
+		j.SlotID,
+		j.Spec,
+		j.NextRunAt,
+		j.LastRunAt,
+	)
+	if err != nil {
+		return j, err
+	}
+	j.ID = id
+
+	return j, nil
+}
+
+// UpdateJob updates spec and run times of the job
+func (m *DB) UpdateJob(j model.Job) (model.Job, error) {
+	err := j.Validate()
+	if err != nil {
+		return j, err
+	}
+
+	_, err = m.exec(
+		"UPDATE jobs SET spec=?, next_run_at=?, last_run_at=? WHERE id=?",
+		j.Spec, j.NextRunAt, j.LastRunAt, j.ID,
+	)
+	return j, err
+}
+
+// ClaimJob moves the job due at scheduledAt to until, so that only one
+// replica runs it. It reports false if the job was claimed or rescheduled
+// by somebody else
+func (m *DB) ClaimJob(id, scheduledAt, until int64) (bool, error) {
+	res, err := m.exec(
+		"UPDATE jobs SET next_run_at=? WHERE id=? AND next_run_at=?",
+		until, id, scheduledAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// GetJob selects job entry from database
+func (m *DB) GetJob(id int64) (model.Job, error) {
+	var j model.Job
+	err := m.get(&j, "SELECT * FROM jobs WHERE id=?", id)
+	return j, err
+}
+
+// ListWorkspaceJobs returns jobs of the workspace ordered by next run
+func (m *DB) ListWorkspaceJobs(workspaceID string) ([]model.Job, error) {
+	items := []model.Job{}
+	err := m.selectAll(&items, "SELECT * FROM jobs WHERE workspace_id=? ORDER BY next_run_at, id", workspaceID)
+	return items, err
+}
+
+// DeleteJob deletes job entry from database
+func (m *DB) DeleteJob(id int64) error {
+	_, err := m.exec("DELETE FROM jobs WHERE id=?", id)
+	return err
+}
+
+// CreateJobRun records outcome of the job run
+func (m *DB) CreateJobRun(r model.JobRun) (model.JobRun, error) {
+	id, err := m.insert(
+		`INSERT INTO job_runs (
+			job_id,
+			workspace_id,
+			job_name,
+			scheduled_at,
+			started_at,
+			finished_at,
+			status,
+			error
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.JobID,
+		r.WorkspaceID,
+		r.JobName,
+		r.ScheduledAt,
+		r.StartedAt,
+		r.FinishedAt,
+		r.Status,
+		r.Error,
+	)
+	if err != nil {
+		return r, err
+	}
+	r.ID = id
+
+	return r, nil
+}
+
+// ListJobRuns returns runs of the job, latest first
+func (m *DB) ListJobRuns(jobID int64) ([]model.JobRun, error) {
+	items := []model.JobRun{}
+	err := m.selectAll(&items, "SELECT * FROM job_runs WHERE job_id=? ORDER BY id DESC", jobID)
+	return items, err
+}
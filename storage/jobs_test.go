@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobs(t *testing.T) {
+	_, err := db.CreateJob(model.Job{})
+	assert.Error(t, err)
+
+	alarm, err := db.CreateJob(model.Job{
+		WorkspaceID: "jobsTeam",
+		Name:        "alarm:C1",
+		Kind:        model.JobAlarm,
+		ChannelID:   "C1",
+		Spec:        "10:00",
+		NextRunAt:   200,
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, int64(0), alarm.ID)
+
+	_, err = db.CreateJob(model.Job{
+		WorkspaceID: "jobsTeam",
+		Name:        "alarm:C1",
+		Kind:        model.JobAlarm,
+	})
+	assert.Error(t, err, "job name is unique within workspace")
+
+	report, err := db.CreateJob(model.Job{
+		WorkspaceID: "jobsTeam",
+		Name:        model.JobDailyReport,
+		Kind:        model.JobDailyReport,
+		NextRunAt:   100,
+	})
+	require.NoError(t, err)
+
+	jobs, err := db.ListWorkspaceJobs("jobsTeam")
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, report.ID, jobs[0].ID)
+	assert.Equal(t, alarm.ID, jobs[1].ID)
+
+	alarm.NextRunAt = 300
+	alarm.LastRunAt = 200
+	alarm.Spec = "11:00"
+	_, err = db.UpdateJob(alarm)
+	require.NoError(t, err)
+
+	job, err := db.GetJob(alarm.ID)
+	require.NoError(t, err)
+	assert.Equal(t, alarm, job)
+
+	ok, err := db.ClaimJob(alarm.ID, 300, 400)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = db.ClaimJob(alarm.ID, 300, 400)
+	require.NoError(t, err)
+	assert.False(t, ok, "claimed job is not claimed again")
+	job, err = db.GetJob(alarm.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(400), job.NextRunAt)
+	_, err = db.UpdateJob(alarm)
+	require.NoError(t, err)
+
+	_, err = db.CreateJobRun(model.JobRun{
+		JobID:       alarm.ID,
+		WorkspaceID: "jobsTeam",
+		JobName:     alarm.Name,
+		ScheduledAt: 200,
+		StartedAt:   201,
+		FinishedAt:  202,
+		Status:      model.JobRunSucceeded,
+	})
+	require.NoError(t, err)
+	_, err = db.CreateJobRun(model.JobRun{
+		JobID:       alarm.ID,
+		WorkspaceID: "jobsTeam",
+		JobName:     alarm.Name,
+		ScheduledAt: 300,
+		StartedAt:   301,
+		FinishedAt:  302,
+		Status:      model.JobRunFailed,
+		Error:       "boom",
+	})
+	require.NoError(t, err)
+
+	runs, err := db.ListJobRuns(alarm.ID)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, model.JobRunFailed, runs[0].Status)
+	assert.Equal(t, "boom", runs[0].Error)
+	assert.Equal(t, int64(200), runs[1].ScheduledAt)
+
+	assert.NoError(t, db.DeleteJob(alarm.ID))
+	assert.NoError(t, db.DeleteJob(report.ID))
+
+	jobs, err = db.ListWorkspaceJobs("jobsTeam")
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// AcquireLease takes the lease for holder until expiresAt if the lease is
+// free, expired or already belongs to holder, and reports whether holder
+// owns the lease now. Update and insert are single statements, so two
+// replicas can not take the same lease at once
+func (m *DB) AcquireLease(name, holder string, now, expiresAt int64) (bool, error) {
+	_, err := m.exec(
+		"UPDATE leases SET holder=?, expires_at=? WHERE name=? AND (holder=? OR expires_at<?)",
+		holder, expiresAt, name, holder, now,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	var l model.Lease
+	err = m.get(&l, "SELECT * FROM leases WHERE name=?", name)
+	if err == nil {
+		return l.Holder == holder, nil
+	}
+	if err != sql.ErrNoRows {
+		return false, err
+	}
+
+	// insert fails with duplicate key if another replica has just created
+	// the lease, which is only an error if the lease is still missing
+	_, insertErr := m.exec("INSERT INTO leases (name, holder, expires_at) VALUES (?, ?, ?)", name, holder, expiresAt)
+	err = m.get(&l, "SELECT * FROM leases WHERE name=?", name)
+	if err != nil {
+		if insertErr != nil {
+			return false, insertErr
+		}
+		return false, err
+	}
+	return l.Holder == holder, nil
+}
+
+// ReleaseLease gives the lease up if it belongs to holder
+func (m *DB) ReleaseLease(name, holder string) error {
+	_, err := m.exec("DELETE FROM leases WHERE name=? AND holder=?", name, holder)
+	return err
+}
+
+// ListLeases returns all leases
+func (m *DB) ListLeases() ([]model.Lease, error) {
+	leases := []model.Lease{}
+	err := m.selectAll(&leases, "SELECT * FROM leases ORDER BY name")
+	return leases, err
+}
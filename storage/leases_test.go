@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeases(t *testing.T) {
+	name := "scheduler:leasesTeam"
+
+	ok, err := db.AcquireLease(name, "replica-1", 100, 130)
+	require.NoError(t, err)
+	assert.True(t, ok, "free lease is taken")
+
+	ok, err = db.AcquireLease(name, "replica-2", 110, 140)
+	require.NoError(t, err)
+	assert.False(t, ok, "lease of another replica is not expired")
+
+	ok, err = db.AcquireLease(name, "replica-1", 120, 150)
+	require.NoError(t, err)
+	assert.True(t, ok, "holder renews the lease")
+
+	ok, err = db.AcquireLease(name, "replica-2", 150, 180)
+	require.NoError(t, err)
+	assert.False(t, ok, "lease is valid until its expiration")
+
+	ok, err = db.AcquireLease(name, "replica-2", 151, 181)
+	require.NoError(t, err)
+	assert.True(t, ok, "expired lease is taken over")
+
+	ok, err = db.AcquireLease(name, "replica-1", 160, 190)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	leases, err := db.ListLeases()
+	require.NoError(t, err)
+	found := false
+	for _, l := range leases {
+		if l.Name == name {
+			found = true
+			assert.Equal(t, "replica-2", l.Holder)
+			assert.Equal(t, int64(181), l.ExpiresAt)
+		}
+	}
+	assert.True(t, found)
+
+	require.NoError(t, db.ReleaseLease(name, "replica-1"), "releasing lease of another replica does nothing")
+	ok, err = db.AcquireLease(name, "replica-1", 170, 200)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, db.ReleaseLease(name, "replica-2"))
+	ok, err = db.AcquireLease(name, "replica-1", 170, 200)
+	require.NoError(t, err)
+	assert.True(t, ok, "released lease is free")
+
+	require.NoError(t, db.ReleaseLease(name, "replica-1"))
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// MemoryDB is an in-memory implementation of Store. It mimics behaviour of
+// SQL storage (including sql.ErrNoRows for missing entries) and is meant to be
+// used in tests and local experiments where no database is available
+type MemoryDB struct {
+	mu sync.RWMutex
+
+	lastID map[string]int64
+
+	standups            []model.Standup
+	standupers          []model.Standuper
+	projects            []model.Project
+	workspaces          []model.Workspace
+	notificationThreads []model.NotificationThread
+	jobs                []model.Job
+	jobRuns             []model.JobRun
+	leases              []model.Lease
+	events              []model.Event
+	outbox              []model.OutboxMessage
+	outboxLimits        []model.OutboxLimit
+	absences            []model.Absence
+	holidayCalendars    []model.HolidayCalendar
+	holidays            []model.Holiday
+	deadlineSlots       []model.DeadlineSlot
+	standupAnswers      []model.StandupAnswer
+	dmStandups          []model.DMStandup
+	blockers            []model.Blocker
+}
+
+// NewMemoryDB creates empty in-memory storage
+func NewMemoryDB() *MemoryDB {
+	return &MemoryDB{
+		lastID: map[string]int64{},
+	}
+}
+
+// nextID works like AUTO_INCREMENT for the given table. Must be called with mu locked
+func (m *MemoryDB) nextID(table string) int64 {
+	m.lastID[table]++
+	return m.lastID[table]
+}
+
+// errDuplicate is returned where SQL storage would violate unique constraint
+func errDuplicate(table string) error {
+	return fmt.Errorf("duplicate entry in %v", table)
+}
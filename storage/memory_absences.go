@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateAbsence creates absence in memory
+func (m *MemoryDB) CreateAbsence(a model.Absence) (model.Absence, error) {
+	err := a.Validate()
+	if err != nil {
+		return a, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a.ID = m.nextID("absences")
+	m.absences = append(m.absences, a)
+	return a, nil
+}
+
+// GetAbsence returns absence by id
+func (m *MemoryDB) GetAbsence(id int64) (model.Absence, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, a := range m.absences {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return model.Absence{}, sql.ErrNoRows
+}
+
+// DeleteAbsence deletes absence
+func (m *MemoryDB) DeleteAbsence(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, a := range m.absences {
+		if a.ID == id {
+			m.absences = append(m.absences[:i], m.absences[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// ListWorkspaceAbsences returns absences of the workspace which end on or
+// after the given date, all absences if the date is empty
+func (m *MemoryDB) ListWorkspaceAbsences(workspaceID, from string) ([]model.Absence, error) {
+	return m.filterAbsences(func(a model.Absence) bool {
+		return a.WorkspaceID == workspaceID && a.EndDate >= from
+	}), nil
+}
+
+// ListUserAbsences returns absences of the user which overlap the dates
+func (m *MemoryDB) ListUserAbsences(workspaceID, userID, from, to string) ([]model.Absence, error) {
+	return m.filterAbsences(func(a model.Absence) bool {
+		return a.WorkspaceID == workspaceID && a.UserID == userID && a.StartDate <= to && a.EndDate >= from
+	}), nil
+}
+
+func (m *MemoryDB) filterAbsences(match func(model.Absence) bool) []model.Absence {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.Absence{}
+	for _, a := range m.absences {
+		if match(a) {
+			items = append(items, a)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].StartDate < items[j].StartDate
+	})
+	return items
+}
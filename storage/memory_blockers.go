@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateBlocker creates blocker in memory
+func (m *MemoryDB) CreateBlocker(b model.Blocker) (model.Blocker, error) {
+	err := b.Validate()
+	if err != nil {
+		return b, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b.ID = m.nextID("blockers")
+	m.blockers = append(m.blockers, b)
+	return b, nil
+}
+
+// UpdateBlocker updates status, escalation and standup of the blocker
+func (m *MemoryDB) UpdateBlocker(b model.Blocker) (model.Blocker, error) {
+	err := b.Validate()
+	if err != nil {
+		return b, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, item := range m.blockers {
+		if item.ID == b.ID {
+			m.blockers[i].StandupID = b.StandupID
+			m.blockers[i].Status = b.Status
+			m.blockers[i].ResolvedAt = b.ResolvedAt
+			m.blockers[i].ResolvedBy = b.ResolvedBy
+			m.blockers[i].EscalatedAt = b.EscalatedAt
+			return m.blockers[i], nil
+		}
+	}
+	return model.Blocker{}, sql.ErrNoRows
+}
+
+// GetBlocker returns blocker by its ID
+func (m *MemoryDB) GetBlocker(id int64) (model.Blocker, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, b := range m.blockers {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return model.Blocker{}, sql.ErrNoRows
+}
+
+// ListWorkspaceBlockers returns blockers of the workspace with the status,
+// all of them when status is empty, the oldest first
+func (m *MemoryDB) ListWorkspaceBlockers(workspaceID, status string) ([]model.Blocker, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.Blocker{}
+	for _, b := range m.blockers {
+		if b.WorkspaceID == workspaceID && (status == "" || b.Status == status) {
+			items = append(items, b)
+		}
+	}
+	return items, nil
+}
+
+// DeleteBlocker deletes blocker from memory
+func (m *MemoryDB) DeleteBlocker(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, b := range m.blockers {
+		if b.ID == id {
+			m.blockers = append(m.blockers[:i], m.blockers[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// DeleteStandupBlockers deletes blockers last mentioned in the standup
+func (m *MemoryDB) DeleteStandupBlockers(standupID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	items := m.blockers[:0]
+	for _, b := range m.blockers {
+		if b.StandupID != standupID {
+			items = append(items, b)
+		}
+	}
+	m.blockers = items
+	return nil
+}
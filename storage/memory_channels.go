@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateProject creates project entry in memory
+func (m *MemoryDB) CreateProject(ch model.Project) (model.Project, error) {
+	err := ch.Validate()
+	if err != nil {
+		return ch, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ch.ID = m.nextID("projects")
+	m.projects = append(m.projects, cloneProject(ch))
+	return ch, nil
+}
+
+// UpdateProject updates deadline, tz, onbording message, submission days and standup template of the project
+func (m *MemoryDB) UpdateProject(ch model.Project) (model.Project, error) {
+	err := ch.Validate()
+	if err != nil {
+		return ch, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, p := range m.projects {
+		if p.ID == ch.ID {
+			m.projects[i].Deadline = ch.Deadline
+			m.projects[i].TZ = ch.TZ
+			m.projects[i].OnbordingMessage = ch.OnbordingMessage
+			m.projects[i].SubmissionDays = ch.SubmissionDays
+			m.projects[i].StandupTemplate = cloneProject(ch).StandupTemplate
+		}
+	}
+	return ch, nil
+}
+
+// RenameProject sets channel name of the project and its standupers
+func (m *MemoryDB) RenameProject(channelID, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, p := range m.projects {
+		if p.ChannelID == channelID {
+			m.projects[i].ChannelName = name
+		}
+	}
+	for i, s := range m.standupers {
+		if s.ChannelID == channelID {
+			m.standupers[i].ChannelName = name
+		}
+	}
+	return nil
+}
+
+// SetProjectArchived marks project as archived or unarchived. The project
+// stays inactive while the bot is removed from the channel
+func (m *MemoryDB) SetProjectArchived(channelID string, archived bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, p := range m.projects {
+		if p.ChannelID == channelID {
+			m.projects[i].Archived = archived
+			m.projects[i].Inactive = archived || p.BotRemoved
+		}
+	}
+	return nil
+}
+
+// SetProjectBotRemoved marks project as left by the bot or joined again.
+// The project stays inactive while the channel is archived
+func (m *MemoryDB) SetProjectBotRemoved(channelID string, removed bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, p := range m.projects {
+		if p.ChannelID == channelID {
+			m.projects[i].BotRemoved = removed
+			m.projects[i].Inactive = p.Archived || removed
+		}
+	}
+	return nil
+}
+
+// ListProjects returns list of projects
+func (m *MemoryDB) ListProjects() ([]model.Project, error) {
+	return m.filterProjects(func(p model.Project) bool {
+		return true
+	}), nil
+}
+
+// ListWorkspaceProjects returns list of projects of the workspace
+func (m *MemoryDB) ListWorkspaceProjects(ws string) ([]model.Project, error) {
+	return m.filterProjects(func(p model.Project) bool {
+		return p.WorkspaceID == ws
+	}), nil
+}
+
+// SelectProject selects project by channel ID
+func (m *MemoryDB) SelectProject(channelID string) (model.Project, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, p := range m.projects {
+		if p.ChannelID == channelID {
+			return cloneProject(p), nil
+		}
+	}
+	return model.Project{}, sql.ErrNoRows
+}
+
+// GetProject selects project with specific id
+func (m *MemoryDB) GetProject(id int64) (model.Project, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, p := range m.projects {
+		if p.ID == id {
+			return cloneProject(p), nil
+		}
+	}
+	return model.Project{}, sql.ErrNoRows
+}
+
+// DeleteProject deletes project entry
+func (m *MemoryDB) DeleteProject(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, p := range m.projects {
+		if p.ID == id {
+			m.projects = append(m.projects[:i], m.projects[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MemoryDB) filterProjects(match func(model.Project) bool) []model.Project {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.Project{}
+	for _, p := range m.projects {
+		if match(p) {
+			items = append(items, cloneProject(p))
+		}
+	}
+	return items
+}
+
+// cloneProject copies standup template of the project, so callers do not
+// change stored projects through it
+func cloneProject(p model.Project) model.Project {
+	p.StandupTemplate = cloneTemplate(p.StandupTemplate)
+	return p
+}
+
+func cloneTemplate(t model.StandupTemplate) model.StandupTemplate {
+	if t == nil {
+		return nil
+	}
+	template := make(model.StandupTemplate, len(t))
+	for i, s := range t {
+		s.Keywords = append([]string(nil), s.Keywords...)
+		template[i] = s
+	}
+	return template
+}
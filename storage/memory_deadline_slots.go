@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateDeadlineSlot creates deadline slot in memory
+func (m *MemoryDB) CreateDeadlineSlot(ds model.DeadlineSlot) (model.DeadlineSlot, error) {
+	err := ds.Validate()
+	if err != nil {
+		return ds, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ds.ID = m.nextID("deadline_slots")
+	m.deadlineSlots = append(m.deadlineSlots, ds)
+	return ds, nil
+}
+
+// UpdateDeadlineSlot updates name, days and deadline of the slot
+func (m *MemoryDB) UpdateDeadlineSlot(ds model.DeadlineSlot) (model.DeadlineSlot, error) {
+	err := ds.Validate()
+	if err != nil {
+		return ds, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, slot := range m.deadlineSlots {
+		if slot.ID == ds.ID {
+			m.deadlineSlots[i].Name = ds.Name
+			m.deadlineSlots[i].Days = ds.Days
+			m.deadlineSlots[i].Deadline = ds.Deadline
+		}
+	}
+	return ds, nil
+}
+
+// GetDeadlineSlot returns deadline slot by id
+func (m *MemoryDB) GetDeadlineSlot(id int64) (model.DeadlineSlot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, ds := range m.deadlineSlots {
+		if ds.ID == id {
+			return ds, nil
+		}
+	}
+	return model.DeadlineSlot{}, sql.ErrNoRows
+}
+
+// DeleteDeadlineSlot deletes deadline slot
+func (m *MemoryDB) DeleteDeadlineSlot(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, ds := range m.deadlineSlots {
+		if ds.ID == id {
+			m.deadlineSlots = append(m.deadlineSlots[:i], m.deadlineSlots[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// ListChannelDeadlineSlots returns deadline slots of the channel ordered by deadline
+func (m *MemoryDB) ListChannelDeadlineSlots(channelID string) ([]model.DeadlineSlot, error) {
+	return m.filterDeadlineSlots(func(ds model.DeadlineSlot) bool {
+		return ds.ChannelID == channelID
+	}), nil
+}
+
+// ListWorkspaceDeadlineSlots returns deadline slots of all channels of the workspace
+func (m *MemoryDB) ListWorkspaceDeadlineSlots(workspaceID string) ([]model.DeadlineSlot, error) {
+	return m.filterDeadlineSlots(func(ds model.DeadlineSlot) bool {
+		return ds.WorkspaceID == workspaceID
+	}), nil
+}
+
+func (m *MemoryDB) filterDeadlineSlots(match func(model.DeadlineSlot) bool) []model.DeadlineSlot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.DeadlineSlot{}
+	for _, ds := range m.deadlineSlots {
+		if match(ds) {
+			items = append(items, ds)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].ChannelID != items[j].ChannelID {
+			return items[i].ChannelID < items[j].ChannelID
+		}
+		return items[i].Deadline < items[j].Deadline
+	})
+	return items
+}
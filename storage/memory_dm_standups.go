@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateDMStandup creates direct messages standup in memory
+func (m *MemoryDB) CreateDMStandup(d model.DMStandup) (model.DMStandup, error) {
+	err := d.Validate()
+	if err != nil {
+		return d, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d.ID = m.nextID("dm_standups")
+	m.dmStandups = append(m.dmStandups, cloneDMStandup(d))
+	return d, nil
+}
+
+// UpdateDMStandup saves the project, questions and answers of the standup
+// if it still has the project and position it was read with. It reports
+// whether the standup is saved
+func (m *MemoryDB) UpdateDMStandup(d model.DMStandup, channelID string, position int) (bool, error) {
+	err := d.Validate()
+	if err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, item := range m.dmStandups {
+		if item.ID != d.ID {
+			continue
+		}
+		if item.ChannelID != channelID || item.Position != position {
+			return false, nil
+		}
+		item.ChannelID = d.ChannelID
+		item.Questions = d.Questions
+		item.Position = d.Position
+		item.Answers = d.Answers
+		m.dmStandups[i] = cloneDMStandup(item)
+		return true, nil
+	}
+	return false, nil
+}
+
+// ListUserDMStandups returns direct messages standups of the user, the one
+// started first goes first
+func (m *MemoryDB) ListUserDMStandups(workspaceID, userID string) ([]model.DMStandup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.DMStandup{}
+	for _, d := range m.dmStandups {
+		if d.WorkspaceID == workspaceID && d.UserID == userID {
+			items = append(items, cloneDMStandup(d))
+		}
+	}
+	return items, nil
+}
+
+// DeleteDMStandup deletes direct messages standup from memory
+func (m *MemoryDB) DeleteDMStandup(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, d := range m.dmStandups {
+		if d.ID == id {
+			m.dmStandups = append(m.dmStandups[:i], m.dmStandups[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func cloneDMStandup(d model.DMStandup) model.DMStandup {
+	d.Questions = cloneTemplate(d.Questions)
+	if d.Answers != nil {
+		d.Answers = append(model.StandupAnswers(nil), d.Answers...)
+	}
+	return d
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// RecordEvent saves event unless event with the same event id is already
+// saved. It returns the saved event and whether it was created now
+func (m *MemoryDB) RecordEvent(e model.Event) (model.Event, bool, error) {
+	err := e.Validate()
+	if err != nil {
+		return e, false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, event := range m.events {
+		if event.EventID == e.EventID {
+			return event, false, nil
+		}
+	}
+
+	e.ID = m.nextID("events")
+	m.events = append(m.events, e)
+	return e, true, nil
+}
+
+// ClaimEvent marks event as being processed until the given time and
+// counts the attempt. It reports false if the event is done or another
+// worker holds it
+func (m *MemoryDB) ClaimEvent(id, now, until int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, event := range m.events {
+		if event.ID != id {
+			continue
+		}
+		if event.Status == model.EventDone || event.NextAttemptAt > now {
+			return false, nil
+		}
+		m.events[i].Status = model.EventProcessing
+		m.events[i].Attempts++
+		m.events[i].NextAttemptAt = until
+		return true, nil
+	}
+	return false, nil
+}
+
+// UpdateEvent saves outcome of event processing
+func (m *MemoryDB) UpdateEvent(e model.Event) (model.Event, error) {
+	err := e.Validate()
+	if err != nil {
+		return e, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, event := range m.events {
+		if event.ID == e.ID {
+			m.events[i].Status = e.Status
+			m.events[i].Error = e.Error
+			m.events[i].NextAttemptAt = e.NextAttemptAt
+		}
+	}
+	return e, nil
+}
+
+// GetEvent returns a particular event
+func (m *MemoryDB) GetEvent(id int64) (model.Event, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, event := range m.events {
+		if event.ID == id {
+			return event, nil
+		}
+	}
+	return model.Event{}, sql.ErrNoRows
+}
+
+// DeleteDoneEvents deletes done events created before the given time
+func (m *MemoryDB) DeleteDoneEvents(before int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events := m.events[:0]
+	for _, event := range m.events {
+		if event.Status != model.EventDone || event.CreatedAt >= before {
+			events = append(events, event)
+		}
+	}
+	m.events = events
+	return nil
+}
+
+// ListDueEvents returns events which are not done and are due for an
+// attempt, unless they ran out of attempts
+func (m *MemoryDB) ListDueEvents(now int64, maxAttempts, limit int) ([]model.Event, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.Event{}
+	for _, event := range m.events {
+		if event.Status != model.EventDone && event.NextAttemptAt <= now && event.Attempts < maxAttempts {
+			items = append(items, event)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].NextAttemptAt < items[j].NextAttemptAt
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// ListWorkspaceEvents returns latest events of the workspace, with the
+// given status only unless status is empty
+func (m *MemoryDB) ListWorkspaceEvents(workspaceID, status string, limit int) ([]model.Event, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.Event{}
+	for i := len(m.events) - 1; i >= 0 && len(items) < limit; i-- {
+		event := m.events[i]
+		if event.WorkspaceID == workspaceID && (status == "" || event.Status == status) {
+			items = append(items, event)
+		}
+	}
+	return items, nil
+}
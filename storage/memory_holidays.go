@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateHolidayCalendar creates holiday calendar in memory
+func (m *MemoryDB) CreateHolidayCalendar(hc model.HolidayCalendar) (model.HolidayCalendar, error) {
+	err := hc.Validate()
+	if err != nil {
+		return hc, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hc.ID = m.nextID("holiday_calendars")
+	m.holidayCalendars = append(m.holidayCalendars, hc)
+	return hc, nil
+}
+
+// UpdateHolidayCalendar updates name of the calendar and the channel it is attached to
+func (m *MemoryDB) UpdateHolidayCalendar(hc model.HolidayCalendar) (model.HolidayCalendar, error) {
+	err := hc.Validate()
+	if err != nil {
+		return hc, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, c := range m.holidayCalendars {
+		if c.ID == hc.ID {
+			m.holidayCalendars[i].ChannelID = hc.ChannelID
+			m.holidayCalendars[i].Name = hc.Name
+		}
+	}
+	return hc, nil
+}
+
+// GetHolidayCalendar returns holiday calendar by id
+func (m *MemoryDB) GetHolidayCalendar(id int64) (model.HolidayCalendar, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, hc := range m.holidayCalendars {
+		if hc.ID == id {
+			return hc, nil
+		}
+	}
+	return model.HolidayCalendar{}, sql.ErrNoRows
+}
+
+// ListWorkspaceHolidayCalendars returns holiday calendars of the workspace
+func (m *MemoryDB) ListWorkspaceHolidayCalendars(workspaceID string) ([]model.HolidayCalendar, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.HolidayCalendar{}
+	for _, hc := range m.holidayCalendars {
+		if hc.WorkspaceID == workspaceID {
+			items = append(items, hc)
+		}
+	}
+	return items, nil
+}
+
+// DeleteHolidayCalendar deletes holiday calendar with its holidays
+func (m *MemoryDB) DeleteHolidayCalendar(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	holidays := []model.Holiday{}
+	for _, h := range m.holidays {
+		if h.CalendarID != id {
+			holidays = append(holidays, h)
+		}
+	}
+	m.holidays = holidays
+
+	for i, hc := range m.holidayCalendars {
+		if hc.ID == id {
+			m.holidayCalendars = append(m.holidayCalendars[:i], m.holidayCalendars[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// CreateHoliday creates holiday in memory. Calendar has at most one holiday a day
+func (m *MemoryDB) CreateHoliday(h model.Holiday) (model.Holiday, error) {
+	err := h.Validate()
+	if err != nil {
+		return h, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, holiday := range m.holidays {
+		if holiday.CalendarID == h.CalendarID && holiday.Date == h.Date {
+			return h, errDuplicate("holidays")
+		}
+	}
+
+	h.ID = m.nextID("holidays")
+	m.holidays = append(m.holidays, h)
+	return h, nil
+}
+
+// GetHoliday returns holiday by id
+func (m *MemoryDB) GetHoliday(id int64) (model.Holiday, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, h := range m.holidays {
+		if h.ID == id {
+			return h, nil
+		}
+	}
+	return model.Holiday{}, sql.ErrNoRows
+}
+
+// DeleteHoliday deletes holiday
+func (m *MemoryDB) DeleteHoliday(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, h := range m.holidays {
+		if h.ID == id {
+			m.holidays = append(m.holidays[:i], m.holidays[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// ListCalendarHolidays returns holidays of the calendar ordered by date
+func (m *MemoryDB) ListCalendarHolidays(calendarID int64) ([]model.Holiday, error) {
+	return m.filterHolidays(func(h model.Holiday) bool {
+		return h.CalendarID == calendarID
+	}), nil
+}
+
+// ListChannelHolidays returns holidays between the dates from calendars of
+// the workspace which apply to the channel
+func (m *MemoryDB) ListChannelHolidays(workspaceID, channelID, from, to string) ([]model.Holiday, error) {
+	m.mu.RLock()
+	calendars := map[int64]bool{}
+	for _, hc := range m.holidayCalendars {
+		if hc.WorkspaceID == workspaceID && (hc.ChannelID == "" || hc.ChannelID == channelID) {
+			calendars[hc.ID] = true
+		}
+	}
+	m.mu.RUnlock()
+
+	return m.filterHolidays(func(h model.Holiday) bool {
+		return calendars[h.CalendarID] && h.Date >= from && h.Date <= to
+	}), nil
+}
+
+func (m *MemoryDB) filterHolidays(match func(model.Holiday) bool) []model.Holiday {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.Holiday{}
+	for _, h := range m.holidays {
+		if match(h) {
+			items = append(items, h)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Date < items[j].Date
+	})
+	return items
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateJob creates job entry in memory
+func (m *MemoryDB) CreateJob(j model.Job) (model.Job, error) {
+	err := j.Validate()
+	if err != nil {
+		return j, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, job := range m.jobs {
+		if job.WorkspaceID == j.WorkspaceID && job.Name == j.Name {
+			return j, errDuplicate("jobs")
+		}
+	}
+
+	j.ID = m.nextID("jobs")
+	m.jobs = append(m.jobs, j)
+	return j, nil
+}
+
+// UpdateJob updates spec and run times of the job
+func (m *MemoryDB) UpdateJob(j model.Job) (model.Job, error) {
+	err := j.Validate()
+	if err != nil {
+		return j, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, job := range m.jobs {
+		if job.ID == j.ID {
+			m.jobs[i].Spec = j.Spec
+			m.jobs[i].NextRunAt = j.NextRunAt
+			m.jobs[i].LastRunAt = j.LastRunAt
+		}
+	}
+	return j, nil
+}
+
+// ClaimJob moves the job due at scheduledAt to until, so that only one
+// replica runs it. It reports false if the job was claimed or rescheduled
+// by somebody else
+func (m *MemoryDB) ClaimJob(id, scheduledAt, until int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, job := range m.jobs {
+		if job.ID == id && job.NextRunAt == scheduledAt {
+			m.jobs[i].NextRunAt = until
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetJob returns a particular job
+func (m *MemoryDB) GetJob(id int64) (model.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, job := range m.jobs {
+		if job.ID == id {
+			return job, nil
+		}
+	}
+	return model.Job{}, sql.ErrNoRows
+}
+
+// ListWorkspaceJobs returns jobs of the workspace ordered by next run
+func (m *MemoryDB) ListWorkspaceJobs(workspaceID string) ([]model.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.Job{}
+	for _, job := range m.jobs {
+		if job.WorkspaceID == workspaceID {
+			items = append(items, job)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].NextRunAt < items[j].NextRunAt
+	})
+	return items, nil
+}
+
+// DeleteJob deletes job
+func (m *MemoryDB) DeleteJob(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, job := range m.jobs {
+		if job.ID == id {
+			m.jobs = append(m.jobs[:i], m.jobs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// CreateJobRun records outcome of the job run
+func (m *MemoryDB) CreateJobRun(r model.JobRun) (model.JobRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r.ID = m.nextID("job_runs")
+	m.jobRuns = append(m.jobRuns, r)
+	return r, nil
+}
+
+// ListJobRuns returns runs of the job, latest first
+func (m *MemoryDB) ListJobRuns(jobID int64) ([]model.JobRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.JobRun{}
+	for i := len(m.jobRuns) - 1; i >= 0; i-- {
+		if m.jobRuns[i].JobID == jobID {
+			items = append(items, m.jobRuns[i])
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"sort"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// AcquireLease takes the lease for holder until expiresAt if the lease is
+// free, expired or already belongs to holder, and reports whether holder
+// owns the lease now
+func (m *MemoryDB) AcquireLease(name, holder string, now, expiresAt int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, l := range m.leases {
+		if l.Name != name {
+			continue
+		}
+		if l.Holder != holder && l.ExpiresAt >= now {
+			return false, nil
+		}
+		m.leases[i].Holder = holder
+		m.leases[i].ExpiresAt = expiresAt
+		return true, nil
+	}
+
+	m.leases = append(m.leases, model.Lease{Name: name, Holder: holder, ExpiresAt: expiresAt})
+	return true, nil
+}
+
+// ReleaseLease gives the lease up if it belongs to holder
+func (m *MemoryDB) ReleaseLease(name, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, l := range m.leases {
+		if l.Name == name && l.Holder == holder {
+			m.leases = append(m.leases[:i], m.leases[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// ListLeases returns all leases
+func (m *MemoryDB) ListLeases() ([]model.Lease, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	leases := append([]model.Lease{}, m.leases...)
+	sort.Slice(leases, func(i, j int) bool {
+		return leases[i].Name < leases[j].Name
+	})
+	return leases, nil
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateNotificationThread creates notification thread in memory
+func (m *MemoryDB) CreateNotificationThread(s model.NotificationThread) (model.NotificationThread, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s.ID = m.nextID("notification_threads")
+	m.notificationThreads = append(m.notificationThreads, s)
+	return s, nil
+}
+
+// DeleteNotificationThread deletes notification thread
+func (m *MemoryDB) DeleteNotificationThread(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, nt := range m.notificationThreads {
+		if nt.ID == id {
+			m.notificationThreads = append(m.notificationThreads[:i], m.notificationThreads[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// SelectNotificationsThread returns notification thread of the channel
+func (m *MemoryDB) SelectNotificationsThread(channelID string) (model.NotificationThread, error) {
+	return m.SelectUserNotificationsThread(channelID, "")
+}
+
+// SelectUserNotificationsThread returns notification thread of the standuper
+// with individual schedule
+func (m *MemoryDB) SelectUserNotificationsThread(channelID, userID string) (model.NotificationThread, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, nt := range m.notificationThreads {
+		if nt.ChannelID == channelID && nt.UserID == userID && nt.SlotID == 0 {
+			return nt, nil
+		}
+	}
+	return model.NotificationThread{}, sql.ErrNoRows
+}
+
+// SelectSlotNotificationsThread returns notification thread of the deadline
+// slot of the channel
+func (m *MemoryDB) SelectSlotNotificationsThread(channelID string, slotID int64) (model.NotificationThread, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, nt := range m.notificationThreads {
+		if nt.ChannelID == channelID && nt.UserID == "" && nt.SlotID == slotID {
+			return nt, nil
+		}
+	}
+	return model.NotificationThread{}, sql.ErrNoRows
+}
+
+// UpdateNotificationThread updates non reporters and notification time and increments reminder counter
+func (m *MemoryDB) UpdateNotificationThread(id int64, notificationTime int64, nonReporters string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, nt := range m.notificationThreads {
+		if nt.ID == id {
+			m.notificationThreads[i].UserIDs = nonReporters
+			m.notificationThreads[i].ReminderCounter++
+			m.notificationThreads[i].NotificationTime = notificationTime
+		}
+	}
+	return nil
+}
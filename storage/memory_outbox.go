@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateOutboxMessage saves message to be sent
+func (m *MemoryDB) CreateOutboxMessage(o model.OutboxMessage) (model.OutboxMessage, error) {
+	err := o.Validate()
+	if err != nil {
+		return o, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o.ID = m.nextID("outbox_messages")
+	m.outbox = append(m.outbox, o)
+	return o, nil
+}
+
+// ClaimOutboxMessage marks pending message as being sent until the given
+// time and counts the attempt. It reports false if the message is not due
+// or another sender holds it
+func (m *MemoryDB) ClaimOutboxMessage(id, now, until int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, o := range m.outbox {
+		if o.ID != id {
+			continue
+		}
+		if !outboxDue(o, now) {
+			return false, nil
+		}
+		m.outbox[i].Status = model.OutboxSending
+		m.outbox[i].Attempts++
+		m.outbox[i].NextAttemptAt = until
+		return true, nil
+	}
+	return false, nil
+}
+
+// UpdateOutboxMessage saves outcome of the delivery attempt
+func (m *MemoryDB) UpdateOutboxMessage(o model.OutboxMessage) (model.OutboxMessage, error) {
+	err := o.Validate()
+	if err != nil {
+		return o, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, stored := range m.outbox {
+		if stored.ID == o.ID {
+			stored.ChannelID = o.ChannelID
+			stored.Status = o.Status
+			stored.Attempts = o.Attempts
+			stored.Error = o.Error
+			stored.NextAttemptAt = o.NextAttemptAt
+			stored.SentAt = o.SentAt
+			stored.MessageTS = o.MessageTS
+			m.outbox[i] = stored
+		}
+	}
+	return o, nil
+}
+
+// GetOutboxMessage returns a particular outbox message
+func (m *MemoryDB) GetOutboxMessage(id int64) (model.OutboxMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, o := range m.outbox {
+		if o.ID == id {
+			return o, nil
+		}
+	}
+	return model.OutboxMessage{}, sql.ErrNoRows
+}
+
+// ListDueOutboxMessages returns messages of the workspace due for delivery,
+// oldest first
+func (m *MemoryDB) ListDueOutboxMessages(workspaceID string, now int64, limit int) ([]model.OutboxMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.OutboxMessage{}
+	for _, o := range m.outbox {
+		if len(items) == limit {
+			break
+		}
+		if o.WorkspaceID == workspaceID && outboxDue(o, now) {
+			items = append(items, o)
+		}
+	}
+	return items, nil
+}
+
+// ListWorkspaceOutboxMessages returns latest messages of the workspace, with
+// the given status only unless status is empty
+func (m *MemoryDB) ListWorkspaceOutboxMessages(workspaceID, status string, limit int) ([]model.OutboxMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.OutboxMessage{}
+	for i := len(m.outbox) - 1; i >= 0 && len(items) < limit; i-- {
+		o := m.outbox[i]
+		if o.WorkspaceID == workspaceID && (status == "" || o.Status == status) {
+			items = append(items, o)
+		}
+	}
+	return items, nil
+}
+
+func outboxDue(o model.OutboxMessage, now int64) bool {
+	return (o.Status == model.OutboxPending || o.Status == model.OutboxSending) && o.NextAttemptAt <= now
+}
+
+// HasUnsentOutboxMessages reports whether the workspace has messages older
+// than beforeID which are not sent yet
+func (m *MemoryDB) HasUnsentOutboxMessages(workspaceID string, beforeID int64) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, o := range m.outbox {
+		if o.WorkspaceID == workspaceID && o.ID < beforeID && (o.Status == model.OutboxPending || o.Status == model.OutboxSending) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DeleteSentOutboxMessages deletes messages of the workspace sent before the
+// given time
+func (m *MemoryDB) DeleteSentOutboxMessages(workspaceID string, before int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.outbox[:0]
+	for _, o := range m.outbox {
+		if o.WorkspaceID != workspaceID || o.Status != model.OutboxSent || o.SentAt >= before {
+			kept = append(kept, o)
+		}
+	}
+	m.outbox = kept
+	return nil
+}
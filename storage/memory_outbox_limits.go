@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// TakeOutboxToken takes a token from the rate limit of the workspace, which
+// allows burst messages at once and then one message per interval. Times
+// are in milliseconds
+func (m *MemoryDB) TakeOutboxToken(workspaceID string, now, interval int64, burst int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.outboxLimit(workspaceID)
+	refilledAt, ok := takeOutboxToken(m.outboxLimits[i], now, interval, burst)
+	if ok {
+		m.outboxLimits[i].RefilledAt = refilledAt
+	}
+	return ok, nil
+}
+
+// PauseOutbox stops posting to the workspace until the given time in
+// milliseconds. A pause is never shortened
+func (m *MemoryDB) PauseOutbox(workspaceID string, until int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.outboxLimit(workspaceID)
+	if m.outboxLimits[i].PausedUntil < until {
+		m.outboxLimits[i].PausedUntil = until
+	}
+	return nil
+}
+
+// outboxLimit returns index of the workspace limit, creating it if needed.
+// Callers hold m.mu
+func (m *MemoryDB) outboxLimit(workspaceID string) int {
+	for i, l := range m.outboxLimits {
+		if l.WorkspaceID == workspaceID {
+			return i
+		}
+	}
+	m.outboxLimits = append(m.outboxLimits, model.OutboxLimit{WorkspaceID: workspaceID})
+	return len(m.outboxLimits) - 1
+}
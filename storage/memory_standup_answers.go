@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"sort"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateStandupAnswer creates standup answer in memory
+func (m *MemoryDB) CreateStandupAnswer(a model.StandupAnswer) (model.StandupAnswer, error) {
+	err := a.Validate()
+	if err != nil {
+		return a, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, answer := range m.standupAnswers {
+		if answer.StandupID == a.StandupID && answer.Question == a.Question {
+			return a, errDuplicate("standup_answers")
+		}
+	}
+
+	a.ID = m.nextID("standup_answers")
+	m.standupAnswers = append(m.standupAnswers, a)
+	return a, nil
+}
+
+// ListStandupAnswers returns answers of the standup in order of questions
+func (m *MemoryDB) ListStandupAnswers(standupID int64) ([]model.StandupAnswer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.StandupAnswer{}
+	for _, a := range m.standupAnswers {
+		if a.StandupID == standupID {
+			items = append(items, a)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Position < items[j].Position
+	})
+	return items, nil
+}
+
+// ListTeamStandupAnswers returns answers of all standups of the workspace
+func (m *MemoryDB) ListTeamStandupAnswers(teamID string) ([]model.StandupAnswer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	standups := map[int64]bool{}
+	for _, s := range m.standups {
+		if s.WorkspaceID == teamID {
+			standups[s.ID] = true
+		}
+	}
+	items := []model.StandupAnswer{}
+	for _, a := range m.standupAnswers {
+		if standups[a.StandupID] {
+			items = append(items, a)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].StandupID != items[j].StandupID {
+			return items[i].StandupID < items[j].StandupID
+		}
+		return items[i].Position < items[j].Position
+	})
+	return items, nil
+}
+
+// DeleteStandupAnswers deletes all answers of the standup
+func (m *MemoryDB) DeleteStandupAnswers(standupID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	items := m.standupAnswers[:0]
+	for _, a := range m.standupAnswers {
+		if a.StandupID != standupID {
+			items = append(items, a)
+		}
+	}
+	m.standupAnswers = items
+	return nil
+}
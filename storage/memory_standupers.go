@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateStanduper creates standuper entry in memory
+func (m *MemoryDB) CreateStanduper(s model.Standuper) (model.Standuper, error) {
+	err := s.Validate()
+	if err != nil {
+		return s, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s.ID = m.nextID("standupers")
+	m.standupers = append(m.standupers, s)
+	return s, nil
+}
+
+// UpdateStanduper updates role and schedule of the standuper
+func (m *MemoryDB) UpdateStanduper(st model.Standuper) (model.Standuper, error) {
+	err := st.Validate()
+	if err != nil {
+		return st, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, s := range m.standupers {
+		if s.ID == st.ID {
+			m.standupers[i].Role = st.Role
+			m.standupers[i].Deadline = st.Deadline
+			m.standupers[i].TZ = st.TZ
+			m.standupers[i].SubmissionDays = st.SubmissionDays
+			m.standupers[i].DMStandupOffset = st.DMStandupOffset
+			return m.standupers[i], nil
+		}
+	}
+	return model.Standuper{}, sql.ErrNoRows
+}
+
+// RenameStanduper sets real name of the user in every channel of the workspace
+func (m *MemoryDB) RenameStanduper(workspaceID, userID, realName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, s := range m.standupers {
+		if s.WorkspaceID == workspaceID && s.UserID == userID {
+			m.standupers[i].RealName = realName
+		}
+	}
+	return nil
+}
+
+// FindStansuperByUserID finds user in channel
+func (m *MemoryDB) FindStansuperByUserID(userID, channelID string) (model.Standuper, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, s := range m.standupers {
+		if s.UserID == userID && s.ChannelID == channelID {
+			return s, nil
+		}
+	}
+	return model.Standuper{}, sql.ErrNoRows
+}
+
+// FindStansupersByUserID finds user in all channels
+func (m *MemoryDB) FindStansupersByUserID(userID string) ([]model.Standuper, error) {
+	return m.filterStandupers(func(s model.Standuper) bool {
+		return s.UserID == userID
+	}, nil), nil
+}
+
+// ListStandupers returns all standupers
+func (m *MemoryDB) ListStandupers() ([]model.Standuper, error) {
+	return m.filterStandupers(func(s model.Standuper) bool {
+		return true
+	}, []model.Standuper{}), nil
+}
+
+// ListWorkspaceStandupers returns standupers of the workspace
+func (m *MemoryDB) ListWorkspaceStandupers(workspaceID string) ([]model.Standuper, error) {
+	return m.filterStandupers(func(s model.Standuper) bool {
+		return s.WorkspaceID == workspaceID
+	}, []model.Standuper{}), nil
+}
+
+// GetStanduper returns a standuper
+func (m *MemoryDB) GetStanduper(id int64) (model.Standuper, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, s := range m.standupers {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return model.Standuper{}, sql.ErrNoRows
+}
+
+// ListProjectStandupers returns standupers of the channel
+func (m *MemoryDB) ListProjectStandupers(channelID string) ([]model.Standuper, error) {
+	return m.filterStandupers(func(s model.Standuper) bool {
+		return s.ChannelID == channelID
+	}, []model.Standuper{}), nil
+}
+
+// ListStandupersByWorkspaceID returns array of standupers which belongs to one team
+func (m *MemoryDB) ListStandupersByWorkspaceID(wsID string) ([]model.Standuper, error) {
+	return m.ListWorkspaceStandupers(wsID)
+}
+
+// DeleteStanduper deletes standuper entry
+func (m *MemoryDB) DeleteStanduper(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, s := range m.standupers {
+		if s.ID == id {
+			m.standupers = append(m.standupers[:i], m.standupers[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// filterStandupers appends matching standupers to items. items is passed in to
+// reproduce nil vs empty slice results of sqlx Select
+func (m *MemoryDB) filterStandupers(match func(model.Standuper) bool, items []model.Standuper) []model.Standuper {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, s := range m.standupers {
+		if match(s) {
+			items = append(items, s)
+		}
+	}
+	return items
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateStandup creates standup entry in memory
+func (m *MemoryDB) CreateStandup(s model.Standup) (model.Standup, error) {
+	err := s.Validate()
+	if err != nil {
+		return s, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s.ID = m.nextID("standups")
+	m.standups = append(m.standups, s)
+	return s, nil
+}
+
+// UpdateStandup updates comment and message ts of the standup
+func (m *MemoryDB) UpdateStandup(s model.Standup) (model.Standup, error) {
+	err := s.Validate()
+	if err != nil {
+		return s, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, st := range m.standups {
+		if st.ID == s.ID {
+			m.standups[i].Comment = s.Comment
+			m.standups[i].MessageTS = s.MessageTS
+			return m.standups[i], nil
+		}
+	}
+	return model.Standup{}, sql.ErrNoRows
+}
+
+// ListStandups returns all standups, newest first
+func (m *MemoryDB) ListStandups() ([]model.Standup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.Standup{}
+	for i := len(m.standups) - 1; i >= 0; i-- {
+		items = append(items, m.standups[i])
+	}
+	return items, nil
+}
+
+// ListTeamStandups returns standups of the workspace, newest first
+func (m *MemoryDB) ListTeamStandups(teamID string) ([]model.Standup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.Standup{}
+	for i := len(m.standups) - 1; i >= 0; i-- {
+		if m.standups[i].WorkspaceID == teamID {
+			items = append(items, m.standups[i])
+		}
+	}
+	return items, nil
+}
+
+// GetStandup returns standup by its ID
+func (m *MemoryDB) GetStandup(id int64) (model.Standup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, s := range m.standups {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return model.Standup{}, sql.ErrNoRows
+}
+
+// SelectStandupByMessageTS selects standup of the channel filtered by MessageTS parameter
+func (m *MemoryDB) SelectStandupByMessageTS(workspaceID, channelID, messageTS string) (model.Standup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, s := range m.standups {
+		if s.WorkspaceID == workspaceID && s.ChannelID == channelID && s.MessageTS == messageTS {
+			return s, nil
+		}
+	}
+	return model.Standup{}, sql.ErrNoRows
+}
+
+// SelectLatestStandupByUser selects the latest standup of the user in the channel
+func (m *MemoryDB) SelectLatestStandupByUser(userID, channelID string) (model.Standup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := len(m.standups) - 1; i >= 0; i-- {
+		s := m.standups[i]
+		if s.UserID == userID && s.ChannelID == channelID {
+			return s, nil
+		}
+	}
+	return model.Standup{}, sql.ErrNoRows
+}
+
+// GetStandupForPeriod selects standup of the user submitted in the period (bounds included)
+func (m *MemoryDB) GetStandupForPeriod(userID, channelID string, timeFrom, timeTo int64) (*model.Standup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, s := range m.standups {
+		if s.UserID == userID && s.ChannelID == channelID && s.CreatedAt >= timeFrom && s.CreatedAt <= timeTo {
+			standup := s
+			return &standup, nil
+		}
+	}
+	return &model.Standup{}, sql.ErrNoRows
+}
+
+// DeleteStandup deletes standup entry, its answers and blockers
+func (m *MemoryDB) DeleteStandup(id int64) error {
+	m.mu.Lock()
+	for i, s := range m.standups {
+		if s.ID == id {
+			m.standups = append(m.standups[:i], m.standups[i+1:]...)
+			break
+		}
+	}
+	m.mu.Unlock()
+
+	err := m.DeleteStandupBlockers(id)
+	if err != nil {
+		return err
+	}
+	return m.DeleteStandupAnswers(id)
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStandups(t *testing.T) {
+	mem := NewMemoryDB()
+
+	_, err := mem.CreateStandup(model.Standup{})
+	assert.Error(t, err)
+
+	first, err := mem.CreateStandup(model.Standup{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: "foo",
+		UserID:      "bar",
+		ChannelID:   "bar12",
+		MessageTS:   "12345",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), first.ID)
+
+	second, err := mem.CreateStandup(model.Standup{
+		CreatedAt:   time.Now().Unix(),
+		WorkspaceID: "foo",
+		UserID:      "bar",
+		ChannelID:   "bar12",
+		MessageTS:   "123456",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), second.ID)
+
+	standups, err := mem.ListStandups()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, []int64{standups[0].ID, standups[1].ID})
+
+	latest, err := mem.SelectLatestStandupByUser("bar", "bar12")
+	require.NoError(t, err)
+	assert.Equal(t, "123456", latest.MessageTS)
+
+	_, err = mem.SelectLatestStandupByUser("foo", "bar12")
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	_, err = mem.GetStandupForPeriod("bar", "bar12", time.Now().Add(-10*time.Hour).Unix(), time.Now().Add(-10*time.Second).Unix())
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	first.Comment = "yesterday, today, problems"
+	updated, err := mem.UpdateStandup(first)
+	require.NoError(t, err)
+	assert.Equal(t, "yesterday, today, problems", updated.Comment)
+
+	assert.NoError(t, mem.DeleteStandup(first.ID))
+	_, err = mem.GetStandup(first.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestMemoryStandupers(t *testing.T) {
+	mem := NewMemoryDB()
+
+	s, err := mem.CreateStanduper(model.Standuper{
+		WorkspaceID: "foo",
+		UserID:      "bar",
+		ChannelID:   "bar12",
+	})
+	require.NoError(t, err)
+
+	res, err := mem.FindStansupersByUserID("noUser")
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+
+	res, err = mem.ListProjectStandupers("noChannel")
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+	assert.Equal(t, 0, len(res))
+
+	s.Role = "pm"
+	s.RealName = "ignored"
+	s, err = mem.UpdateStanduper(s)
+	require.NoError(t, err)
+	assert.Equal(t, "pm", s.Role)
+	assert.Equal(t, "", s.RealName)
+
+	_, err = mem.UpdateStanduper(model.Standuper{ID: 42, WorkspaceID: "foo", UserID: "bar", ChannelID: "bar12"})
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestMemoryWorkspacesAndProjects(t *testing.T) {
+	mem := NewMemoryDB()
+
+	ws, err := mem.CreateWorkspace(model.Workspace{
+		CreatedAt:      int64(100),
+		Language:       "en_US",
+		ReminderOffset: int64(10),
+		BotAccessToken: "token",
+		WorkspaceID:    "WorkspaceID",
+		WorkspaceName:  "foo",
+		ReportingTime:  "9:00",
+	})
+	require.NoError(t, err)
+
+	ws.CreatedAt = 0
+	ws.Language = "ru_RU"
+	_, err = mem.UpdateWorkspace(ws)
+	require.NoError(t, err)
+
+	ws, err = mem.GetWorkspaceByBotAccessToken("token")
+	require.NoError(t, err)
+	assert.Equal(t, "ru_RU", ws.Language)
+	assert.Equal(t, int64(100), ws.CreatedAt)
+
+	assert.NoError(t, mem.DeleteWorkspace("WorkspaceID"))
+	_, err = mem.GetWorkspace(ws.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	p, err := mem.CreateProject(model.Project{
+		WorkspaceID: "foo",
+		ChannelName: "bar",
+		ChannelID:   "bar12",
+	})
+	require.NoError(t, err)
+
+	p.Deadline = "10:00"
+	_, err = mem.UpdateProject(p)
+	require.NoError(t, err)
+
+	p, err = mem.SelectProject("bar12")
+	require.NoError(t, err)
+	assert.Equal(t, "10:00", p.Deadline)
+
+	projects, err := mem.ListWorkspaceProjects("foo")
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(projects))
+}
+
+func TestMemoryNotificationThreads(t *testing.T) {
+	mem := NewMemoryDB()
+
+	_, err := mem.SelectNotificationsThread("1")
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	nt, err := mem.CreateNotificationThread(model.NotificationThread{
+		ChannelID:        "1",
+		UserIDs:          "User1",
+		NotificationTime: int64(10),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, mem.UpdateNotificationThread(nt.ID, int64(20), "User1,User2"))
+
+	thread, err := mem.SelectNotificationsThread("1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, thread.ReminderCounter)
+	assert.Equal(t, "User1,User2", thread.UserIDs)
+	assert.Equal(t, int64(20), thread.NotificationTime)
+
+	require.NoError(t, mem.DeleteNotificationThread(nt.ID))
+	_, err = mem.SelectNotificationsThread("1")
+	assert.Equal(t, sql.ErrNoRows, err)
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateWorkspace creates workspace entry in memory
+func (m *MemoryDB) CreateWorkspace(bs model.Workspace) (model.Workspace, error) {
+	err := bs.Validate()
+	if err != nil {
+		return bs, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bs.ID = m.nextID("workspaces")
+	m.workspaces = append(m.workspaces, bs)
+	return bs, nil
+}
+
+// UpdateWorkspace updates workspace settings
+func (m *MemoryDB) UpdateWorkspace(settings model.Workspace) (model.Workspace, error) {
+	err := settings.Validate()
+	if err != nil {
+		return settings, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, ws := range m.workspaces {
+		if ws.ID == settings.ID {
+			// created_at is the only column UPDATE does not touch
+			stored := settings
+			stored.CreatedAt = ws.CreatedAt
+			m.workspaces[i] = stored
+		}
+	}
+	return settings, nil
+}
+
+// GetAllWorkspaces returns all workspaces
+func (m *MemoryDB) GetAllWorkspaces() ([]model.Workspace, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := []model.Workspace{}
+	items = append(items, m.workspaces...)
+	return items, nil
+}
+
+// GetWorkspaceByWorkspaceID returns a particular workspace
+func (m *MemoryDB) GetWorkspaceByWorkspaceID(workspaceID string) (model.Workspace, error) {
+	return m.findWorkspace(func(ws model.Workspace) bool {
+		return ws.WorkspaceID == workspaceID
+	})
+}
+
+// GetWorkspaceByBotAccessToken returns a particular workspace
+func (m *MemoryDB) GetWorkspaceByBotAccessToken(botAccessToken string) (model.Workspace, error) {
+	return m.findWorkspace(func(ws model.Workspace) bool {
+		return ws.BotAccessToken == botAccessToken
+	})
+}
+
+// GetWorkspace returns a particular workspace
+func (m *MemoryDB) GetWorkspace(id int64) (model.Workspace, error) {
+	return m.findWorkspace(func(ws model.Workspace) bool {
+		return ws.ID == id
+	})
+}
+
+// DeleteWorkspaceByID deletes workspace
+func (m *MemoryDB) DeleteWorkspaceByID(id int64) error {
+	m.deleteWorkspaces(func(ws model.Workspace) bool {
+		return ws.ID == id
+	})
+	return nil
+}
+
+// DeleteWorkspace deletes workspace
+func (m *MemoryDB) DeleteWorkspace(teamID string) error {
+	m.deleteWorkspaces(func(ws model.Workspace) bool {
+		return ws.WorkspaceID == teamID
+	})
+	return nil
+}
+
+func (m *MemoryDB) findWorkspace(match func(model.Workspace) bool) (model.Workspace, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, ws := range m.workspaces {
+		if match(ws) {
+			return ws, nil
+		}
+	}
+	return model.Workspace{}, sql.ErrNoRows
+}
+
+func (m *MemoryDB) deleteWorkspaces(match func(model.Workspace) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspaces := []model.Workspace{}
+	for _, ws := range m.workspaces {
+		if !match(ws) {
+			workspaces = append(workspaces, ws)
+		}
+	}
+	m.workspaces = workspaces
+}
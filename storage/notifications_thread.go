@@ -6,36 +6,46 @@ import (
 
 // CreateNotificationThread create notifications
 func (m *DB) CreateNotificationThread(s model.NotificationThread) (model.NotificationThread, error) {
-	res, err := m.db.Exec(
-		"INSERT INTO `notification_threads` (channel_id,user_ids, notification_time, reminder_counter) VALUES (?, ?, ?, ?)",
-		s.ChannelID, s.UserIDs, s.NotificationTime, s.ReminderCounter,
+	id, err := m.insert(
+		"INSERT INTO notification_threads (channel_id,user_ids, notification_time, reminder_counter, user_id, slot_id) VALUES (?, ?, ?, ?, ?, ?)",
+		s.ChannelID, s.UserIDs, s.NotificationTime, s.ReminderCounter, s.UserID, s.SlotID,
 	)
 	if err != nil {
 		return s, err
 	}
-	id, err := res.LastInsertId()
-	if err != nil {
-		return s, err
-	}
 	s.ID = id
 	return s, nil
 }
 
 // DeleteNotificationThread deletes notification entry from database
 func (m *DB) DeleteNotificationThread(id int64) error {
-	_, err := m.db.Exec("DELETE FROM `notification_threads` WHERE id=?", id)
+	_, err := m.exec("DELETE FROM notification_threads WHERE id=?", id)
 	return err
 }
 
 // SelectNotificationsThread returns array of notifications entries from database
 func (m *DB) SelectNotificationsThread(channelID string) (model.NotificationThread, error) {
+	return m.SelectUserNotificationsThread(channelID, "")
+}
+
+// SelectUserNotificationsThread returns notification thread of the standuper
+// with individual schedule
+func (m *DB) SelectUserNotificationsThread(channelID, userID string) (model.NotificationThread, error) {
+	var items model.NotificationThread
+	err := m.get(&items, "SELECT * FROM notification_threads WHERE channel_id=? AND user_id=? AND slot_id=0", channelID, userID)
+	return items, err
+}
+
+// SelectSlotNotificationsThread returns notification thread of the deadline
+// slot of the channel
+func (m *DB) SelectSlotNotificationsThread(channelID string, slotID int64) (model.NotificationThread, error) {
 	var items model.NotificationThread
-	err := m.db.Get(&items, "SELECT * FROM `notification_threads` WHERE channel_id=?", channelID)
+	err := m.get(&items, "SELECT * FROM notification_threads WHERE channel_id=? AND user_id='' AND slot_id=?", channelID, slotID)
 	return items, err
 }
 
 // UpdateNotificationThread update field reminder counter
 func (m *DB) UpdateNotificationThread(id int64, notificationTime int64, nonReporters string) error {
-	_, err := m.db.Exec("UPDATE `notification_threads` SET user_ids=?, reminder_counter=reminder_counter+1, notification_time=? WHERE id=?", nonReporters, notificationTime, id)
+	_, err := m.exec("UPDATE notification_threads SET user_ids=?, reminder_counter=reminder_counter+1, notification_time=? WHERE id=?", nonReporters, notificationTime, id)
 	return err
 }
@@ -57,6 +57,49 @@ func TestNotification(t *testing.T) {
 	assert.Equal(t, 1, thread.ReminderCounter)
 	assert.Equal(t, nt.UserIDs, thread.UserIDs)
 
+	// thread of a standuper with individual schedule is kept apart
+	ut, err := db.CreateNotificationThread(model.NotificationThread{
+		ChannelID:        "1",
+		UserIDs:          "User3",
+		NotificationTime: tt,
+		UserID:           "User3",
+	})
+	require.NoError(t, err)
+
+	thread, err = db.SelectNotificationsThread("1")
+	require.NoError(t, err)
+	assert.Equal(t, nt.ID, thread.ID)
+
+	thread, err = db.SelectUserNotificationsThread("1", "User3")
+	require.NoError(t, err)
+	assert.Equal(t, ut.ID, thread.ID)
+
+	// and so is thread of a deadline slot
+	st, err := db.CreateNotificationThread(model.NotificationThread{
+		ChannelID:        "1",
+		UserIDs:          "User4",
+		NotificationTime: tt,
+		SlotID:           7,
+	})
+	require.NoError(t, err)
+
+	thread, err = db.SelectNotificationsThread("1")
+	require.NoError(t, err)
+	assert.Equal(t, nt.ID, thread.ID)
+
+	thread, err = db.SelectSlotNotificationsThread("1", 7)
+	require.NoError(t, err)
+	assert.Equal(t, st.ID, thread.ID)
+
+	_, err = db.SelectSlotNotificationsThread("1", 8)
+	assert.Error(t, err)
+
+	err = db.DeleteNotificationThread(st.ID)
+	require.NoError(t, err)
+
+	err = db.DeleteNotificationThread(ut.ID)
+	require.NoError(t, err)
+
 	err = db.DeleteNotificationThread(nt.ID)
 	require.NoError(t, err)
 }
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateOutboxMessage saves message to be sent
+func (m *DB) CreateOutboxMessage(o model.OutboxMessage) (model.OutboxMessage, error) {
+	err := o.Validate()
+	if err != nil {
+		return o, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO outbox_messages (
+			workspace_id,
+			type,
+			channel_id,
+			user_id,
+			text,
+			attachments,
+			status,
+			attempts,
+			error,
+			created_at,
+			next_attempt_at,
+			sent_at,
+			message_ts
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		o.WorkspaceID,
+		o.Type,
+		o.ChannelID,
+		o.UserID,
+		o.Text,
+		o.Attachments,
+		o.Status,
+		o.Attempts,
+		o.Error,
+		o.CreatedAt,
+		o.NextAttemptAt,
+		o.SentAt,
+		o.MessageTS,
+	)
+	if err != nil {
+		return o, err
+	}
+	o.ID = id
+
+	return o, nil
+}
+
+// ClaimOutboxMessage marks pending message as being sent until the given
+// time and counts the attempt. It reports false if the message is not due
+// or another sender holds it
+func (m *DB) ClaimOutboxMessage(id, now, until int64) (bool, error) {
+	res, err := m.exec(
+		`UPDATE outbox_messages SET status=?, attempts=attempts+1, next_attempt_at=?
+		WHERE id=? AND status IN (?, ?) AND next_attempt_at<=?`,
+		model.OutboxSending, until, id, model.OutboxPending, model.OutboxSending, now,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// UpdateOutboxMessage saves outcome of the delivery attempt
+func (m *DB) UpdateOutboxMessage(o model.OutboxMessage) (model.OutboxMessage, error) {
+	err := o.Validate()
+	if err != nil {
+		return o, err
+	}
+
+	_, err = m.exec(
+		`UPDATE outbox_messages SET channel_id=?, status=?, attempts=?, error=?, next_attempt_at=?, sent_at=?, message_ts=?
+		WHERE id=?`,
+		o.ChannelID, o.Status, o.Attempts, o.Error, o.NextAttemptAt, o.SentAt, o.MessageTS, o.ID,
+	)
+	return o, err
+}
+
+// GetOutboxMessage selects outbox message from database
+func (m *DB) GetOutboxMessage(id int64) (model.OutboxMessage, error) {
+	var o model.OutboxMessage
+	err := m.get(&o, "SELECT * FROM outbox_messages WHERE id=?", id)
+	return o, err
+}
+
+// ListDueOutboxMessages returns messages of the workspace due for delivery,
+// oldest first
+func (m *DB) ListDueOutboxMessages(workspaceID string, now int64, limit int) ([]model.OutboxMessage, error) {
+	items := []model.OutboxMessage{}
+	err := m.selectAll(
+		&items,
+		`SELECT * FROM outbox_messages WHERE workspace_id=? AND status IN (?, ?) AND next_attempt_at<=?
+		ORDER BY id LIMIT ?`,
+		workspaceID, model.OutboxPending, model.OutboxSending, now, limit,
+	)
+	return items, err
+}
+
+// ListWorkspaceOutboxMessages returns latest messages of the workspace, with
+// the given status only unless status is empty
+func (m *DB) ListWorkspaceOutboxMessages(workspaceID, status string, limit int) ([]model.OutboxMessage, error) {
+	items := []model.OutboxMessage{}
+	var err error
+	if status == "" {
+		err = m.selectAll(&items, "SELECT * FROM outbox_messages WHERE workspace_id=? ORDER BY id DESC LIMIT ?", workspaceID, limit)
+	} else {
+		err = m.selectAll(&items, "SELECT * FROM outbox_messages WHERE workspace_id=? AND status=? ORDER BY id DESC LIMIT ?", workspaceID, status, limit)
+	}
+	return items, err
+}
+
+// HasUnsentOutboxMessages reports whether the workspace has messages older
+// than beforeID which are not sent yet
+func (m *DB) HasUnsentOutboxMessages(workspaceID string, beforeID int64) (bool, error) {
+	var id int64
+	err := m.get(
+		&id,
+		"SELECT id FROM outbox_messages WHERE workspace_id=? AND status IN (?, ?) AND id<? LIMIT 1",
+		workspaceID, model.OutboxPending, model.OutboxSending, beforeID,
+	)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DeleteSentOutboxMessages deletes messages of the workspace sent before the
+// given time
+func (m *DB) DeleteSentOutboxMessages(workspaceID string, before int64) error {
+	_, err := m.exec(
+		"DELETE FROM outbox_messages WHERE workspace_id=? AND status=? AND sent_at<?",
+		workspaceID, model.OutboxSent, before,
+	)
+	return err
+}
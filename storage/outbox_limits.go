@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/maddevsio/comedian/model"
+)
+
+// TakeOutboxToken takes a token from the rate limit of the workspace, which
+// allows burst messages at once and then one message per interval. Times
+// are in milliseconds. The limit is changed by a conditional update, so a
+// token taken by another replica at the same time is not given out twice:
+// the loser gets false and its message waits in the outbox
+func (m *DB) TakeOutboxToken(workspaceID string, now, interval int64, burst int) (bool, error) {
+	var l model.OutboxLimit
+	err := m.get(&l, "SELECT * FROM outbox_limits WHERE workspace_id=?", workspaceID)
+	if err == sql.ErrNoRows {
+		// insert fails with duplicate key if another replica has just
+		// created the limit, which is only an error if it is still missing
+		_, insertErr := m.exec("INSERT INTO outbox_limits (workspace_id, refilled_at, paused_until) VALUES (?, 0, 0)", workspaceID)
+		err = m.get(&l, "SELECT * FROM outbox_limits WHERE workspace_id=?", workspaceID)
+		if err != nil && insertErr != nil {
+			return false, insertErr
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+
+	refilledAt, ok := takeOutboxToken(l, now, interval, burst)
+	if !ok {
+		return false, nil
+	}
+	res, err := m.exec(
+		"UPDATE outbox_limits SET refilled_at=? WHERE workspace_id=? AND refilled_at=? AND paused_until<=?",
+		refilledAt, workspaceID, l.RefilledAt, now,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// PauseOutbox stops posting to the workspace until the given time in
+// milliseconds. A pause is never shortened
+func (m *DB) PauseOutbox(workspaceID string, until int64) error {
+	_, err := m.exec(
+		"UPDATE outbox_limits SET paused_until=? WHERE workspace_id=? AND paused_until<?",
+		until, workspaceID, until,
+	)
+	return err
+}
+
+// takeOutboxToken returns the new refill time of the limit if it has a
+// token at now
+func takeOutboxToken(l model.OutboxLimit, now, interval int64, burst int) (int64, bool) {
+	if now < l.PausedUntil {
+		return 0, false
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	refilledAt := l.RefilledAt
+	if refilledAt < now {
+		refilledAt = now
+	}
+	refilledAt += interval
+	if refilledAt-now > int64(burst)*interval {
+		return 0, false
+	}
+	return refilledAt, true
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox(t *testing.T) {
+	_, err := db.CreateOutboxMessage(model.OutboxMessage{WorkspaceID: "outboxTeam", Status: model.OutboxPending})
+	assert.Error(t, err, "message needs a recipient")
+
+	first, err := db.CreateOutboxMessage(model.OutboxMessage{
+		WorkspaceID:   "outboxTeam",
+		Type:          "direct",
+		UserID:        "U1",
+		Text:          "hello",
+		Status:        model.OutboxPending,
+		CreatedAt:     100,
+		NextAttemptAt: 100,
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, int64(0), first.ID)
+
+	second, err := db.CreateOutboxMessage(model.OutboxMessage{
+		WorkspaceID:   "outboxTeam",
+		Type:          "message",
+		ChannelID:     "C1",
+		Text:          "later",
+		Attachments:   `[{"text":"report"}]`,
+		Status:        model.OutboxPending,
+		CreatedAt:     100,
+		NextAttemptAt: 200,
+	})
+	require.NoError(t, err)
+
+	due, err := db.ListDueOutboxMessages("outboxTeam", 100, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, first.ID, due[0].ID)
+
+	ok, err := db.ClaimOutboxMessage(first.ID, 100, 130)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = db.ClaimOutboxMessage(first.ID, 110, 140)
+	require.NoError(t, err)
+	assert.False(t, ok, "message is held by another sender")
+
+	sending, err := db.GetOutboxMessage(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OutboxSending, sending.Status)
+	assert.Equal(t, 1, sending.Attempts)
+
+	sending.ChannelID = "D1"
+	sending.Status = model.OutboxSent
+	sending.SentAt = 101
+	sending.MessageTS = "1.1"
+	_, err = db.UpdateOutboxMessage(sending)
+	require.NoError(t, err)
+
+	ok, err = db.ClaimOutboxMessage(first.ID, 1000, 1030)
+	require.NoError(t, err)
+	assert.False(t, ok, "sent message is not sent again")
+
+	due, err = db.ListDueOutboxMessages("outboxTeam", 200, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, second.ID, due[0].ID)
+	assert.Equal(t, `[{"text":"report"}]`, due[0].Attachments)
+
+	messages, err := db.ListWorkspaceOutboxMessages("outboxTeam", "", 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, second.ID, messages[0].ID)
+	assert.Equal(t, "D1", messages[1].ChannelID)
+	assert.Equal(t, "1.1", messages[1].MessageTS)
+
+	messages, err = db.ListWorkspaceOutboxMessages("outboxTeam", model.OutboxSent, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, first.ID, messages[0].ID)
+}
+
+func TestOutboxLimit(t *testing.T) {
+	// burst of 2, then one message per second
+	ok, err := db.TakeOutboxToken("limitTeam", 1000, 1000, 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = db.TakeOutboxToken("limitTeam", 1000, 1000, 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = db.TakeOutboxToken("limitTeam", 1500, 1000, 2)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = db.TakeOutboxToken("limitTeam", 2000, 1000, 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// other workspaces have their own limit
+	ok, err = db.TakeOutboxToken("otherLimitTeam", 2000, 1000, 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, db.PauseOutbox("limitTeam", 60000))
+	// a shorter pause does not cut the longer one
+	require.NoError(t, db.PauseOutbox("limitTeam", 30000))
+	ok, err = db.TakeOutboxToken("limitTeam", 59999, 1000, 2)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = db.TakeOutboxToken("limitTeam", 60000, 1000, 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// zero interval means no limit besides the pause
+	for i := 0; i < 10; i++ {
+		ok, err = db.TakeOutboxToken("otherLimitTeam", 3000, 0, 1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+}
+
+func TestUnsentOutboxMessages(t *testing.T) {
+	create := func(status string, sentAt int64) model.OutboxMessage {
+		o, err := db.CreateOutboxMessage(model.OutboxMessage{WorkspaceID: "unsentTeam", Type: "message", ChannelID: "C1", Text: status, Status: status, SentAt: sentAt})
+		require.NoError(t, err)
+		return o
+	}
+	old := create(model.OutboxSent, 100)
+	failed := create(model.OutboxFailed, 0)
+	pending := create(model.OutboxPending, 0)
+	latest := create(model.OutboxSent, 300)
+
+	waiting, err := db.HasUnsentOutboxMessages("unsentTeam", pending.ID)
+	require.NoError(t, err)
+	assert.False(t, waiting)
+	waiting, err = db.HasUnsentOutboxMessages("unsentTeam", latest.ID)
+	require.NoError(t, err)
+	assert.True(t, waiting)
+	waiting, err = db.HasUnsentOutboxMessages("otherUnsentTeam", latest.ID)
+	require.NoError(t, err)
+	assert.False(t, waiting)
+
+	require.NoError(t, db.DeleteSentOutboxMessages("otherUnsentTeam", 200))
+	require.NoError(t, db.DeleteSentOutboxMessages("unsentTeam", 200))
+	items, err := db.ListWorkspaceOutboxMessages("unsentTeam", "", 10)
+	require.NoError(t, err)
+	ids := []int64{}
+	for _, o := range items {
+		ids = append(ids, o.ID)
+	}
+	assert.Equal(t, []int64{latest.ID, pending.ID, failed.ID}, ids)
+	assert.NotContains(t, ids, old.ID)
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// CreateStandupAnswer creates standup answer entry in database
+func (m *DB) CreateStandupAnswer(a model.StandupAnswer) (model.StandupAnswer, error) {
+	err := a.Validate()
+	if err != nil {
+		return a, err
+	}
+
+	id, err := m.insert(
+		`INSERT INTO standup_answers (
+			standup_id,
+			position,
+			question,
+			answer
+		) VALUES (?, ?, ?, ?)`,
+		a.StandupID,
+		a.Position,
+		a.Question,
+		a.Answer,
+	)
+	if err != nil {
+		return a, err
+	}
+	a.ID = id
+
+	return a, nil
+}
+
+// ListStandupAnswers returns answers of the standup in order of questions
+func (m *DB) ListStandupAnswers(standupID int64) ([]model.StandupAnswer, error) {
+	items := []model.StandupAnswer{}
+	err := m.selectAll(&items, "SELECT * FROM standup_answers WHERE standup_id=? ORDER BY position, id", standupID)
+	return items, err
+}
+
+// ListTeamStandupAnswers returns answers of all standups of the workspace
+func (m *DB) ListTeamStandupAnswers(teamID string) ([]model.StandupAnswer, error) {
+	items := []model.StandupAnswer{}
+	err := m.selectAll(&items, `SELECT a.* FROM standup_answers a
+		JOIN standups s ON s.id=a.standup_id
+		WHERE s.workspace_id=? ORDER BY a.standup_id, a.position, a.id`, teamID)
+	return items, err
+}
+
+// DeleteStandupAnswers deletes all answers of the standup
+func (m *DB) DeleteStandupAnswers(standupID int64) error {
+	_, err := m.exec("DELETE FROM standup_answers WHERE standup_id=?", standupID)
+	return err
+}
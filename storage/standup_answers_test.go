@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/maddevsio/comedian/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandupAnswers(t *testing.T) {
+	_, err := db.CreateStandupAnswer(model.StandupAnswer{Question: "Done"})
+	assert.Error(t, err)
+
+	standup, err := db.CreateStandup(model.Standup{WorkspaceID: "answersTeam", ChannelID: "CANSWERS", UserID: "U1", MessageTS: "answers.1"})
+	require.NoError(t, err)
+
+	doing, err := db.CreateStandupAnswer(model.StandupAnswer{StandupID: standup.ID, Position: 1, Question: "Doing", Answer: "tests"})
+	require.NoError(t, err)
+	done, err := db.CreateStandupAnswer(model.StandupAnswer{StandupID: standup.ID, Position: 0, Question: "Done", Answer: "bugs"})
+	require.NoError(t, err)
+	_, err = db.CreateStandupAnswer(model.StandupAnswer{StandupID: standup.ID, Position: 2, Question: "Done", Answer: "again"})
+	assert.Error(t, err, "one answer per question")
+
+	answers, err := db.ListStandupAnswers(standup.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []model.StandupAnswer{done, doing}, answers)
+
+	other, err := db.CreateStandup(model.Standup{WorkspaceID: "otherTeam", ChannelID: "COTHER", UserID: "U1", MessageTS: "answers.2"})
+	require.NoError(t, err)
+	_, err = db.CreateStandupAnswer(model.StandupAnswer{StandupID: other.ID, Question: "Done", Answer: "nothing"})
+	require.NoError(t, err)
+	answers, err = db.ListTeamStandupAnswers("answersTeam")
+	require.NoError(t, err)
+	assert.Equal(t, []model.StandupAnswer{done, doing}, answers)
+	require.NoError(t, db.DeleteStandup(other.ID))
+
+	require.NoError(t, db.DeleteStandup(standup.ID))
+	answers, err = db.ListStandupAnswers(standup.ID)
+	require.NoError(t, err)
+	assert.Empty(t, answers)
+}
@@ -10,7 +10,7 @@ func (m *DB) CreateStanduper(s model.Standuper) (model.Standuper, error) {
 	if err != nil {
 		return s, err
 	}
-	res, err := m.db.Exec(
+	id, err := m.insert(
 		`INSERT INTO standupers (
 			created_at,
 			workspace_id, 
@@ -18,8 +18,12 @@ func (m *DB) CreateStanduper(s model.Standuper) (model.Standuper, error) {
 			channel_id, 
 			role, 
 			real_name, 
-			channel_name
-		) VALUES (?,?,?,?,?,?,?)`,
+			channel_name,
+			deadline,
+			tz,
+			submission_days,
+			dm_standup_offset
+		) VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
 		s.CreatedAt,
 		s.WorkspaceID,
 		s.UserID,
@@ -27,88 +31,97 @@ func (m *DB) CreateStanduper(s model.Standuper) (model.Standuper, error) {
 		s.Role,
 		s.RealName,
 		s.ChannelName,
+		s.Deadline,
+		s.TZ,
+		s.SubmissionDays,
+		s.DMStandupOffset,
 	)
 	if err != nil {
 		return s, err
 	}
-	id, err := res.LastInsertId()
-	if err != nil {
-		return s, err
-	}
 	s.ID = id
 
 	return s, nil
 }
 
-// UpdateStanduper updates Standuper entry in database
+// UpdateStanduper updates role and schedule of the standuper
 func (m *DB) UpdateStanduper(st model.Standuper) (model.Standuper, error) {
 	err := st.Validate()
 	if err != nil {
 		return st, err
 	}
-	_, err = m.db.Exec(
-		"UPDATE `standupers` SET role=? WHERE id=?",
-		st.Role, st.ID,
+	_, err = m.exec(
+		"UPDATE standupers SET role=?, deadline=?, tz=?, submission_days=?, dm_standup_offset=? WHERE id=?",
+		st.Role, st.Deadline, st.TZ, st.SubmissionDays, st.DMStandupOffset, st.ID,
 	)
 	if err != nil {
 		return st, err
 	}
 	var i model.Standuper
-	err = m.db.Get(&i, "SELECT * FROM `standupers` WHERE id=?", st.ID)
+	err = m.get(&i, "SELECT * FROM standupers WHERE id=?", st.ID)
 	return i, err
 }
 
+// RenameStanduper sets real name of the user in every channel of the workspace
+func (m *DB) RenameStanduper(workspaceID, userID, realName string) error {
+	_, err := m.exec(
+		"UPDATE standupers SET real_name=? WHERE workspace_id=? AND user_id=?",
+		realName, workspaceID, userID,
+	)
+	return err
+}
+
 //FindStansuperByUserID finds user in channel
 func (m *DB) FindStansuperByUserID(userID, channelID string) (model.Standuper, error) {
 	var u model.Standuper
-	err := m.db.Get(&u, "SELECT * FROM `standupers` WHERE user_id=? AND channel_id=?", userID, channelID)
+	err := m.get(&u, "SELECT * FROM standupers WHERE user_id=? AND channel_id=?", userID, channelID)
 	return u, err
 }
 
 //FindStansupersByUserID finds user in channel
 func (m *DB) FindStansupersByUserID(userID string) ([]model.Standuper, error) {
 	var u []model.Standuper
-	err := m.db.Select(&u, "SELECT * FROM `standupers` WHERE user_id=?", userID)
+	err := m.selectAll(&u, "SELECT * FROM standupers WHERE user_id=?", userID)
 	return u, err
 }
 
 // ListStandupers returns array of standup entries from database
 func (m *DB) ListStandupers() ([]model.Standuper, error) {
 	items := []model.Standuper{}
-	err := m.db.Select(&items, "SELECT * FROM `standupers`")
+	err := m.selectAll(&items, "SELECT * FROM standupers")
 	return items, err
 }
 
 // ListWorkspaceStandupers returns array of standup entries from database
 func (m *DB) ListWorkspaceStandupers(workspaceID string) ([]model.Standuper, error) {
 	items := []model.Standuper{}
-	err := m.db.Select(&items, "SELECT * FROM `standupers` where workspace_id=?", workspaceID)
+	err := m.selectAll(&items, "SELECT * FROM standupers where workspace_id=?", workspaceID)
 	return items, err
 }
 
 //GetStanduper returns a standuper
 func (m *DB) GetStanduper(id int64) (model.Standuper, error) {
 	standuper := model.Standuper{}
-	err := m.db.Get(&standuper, "SELECT * FROM standupers where id=?", id)
+	err := m.get(&standuper, "SELECT * FROM standupers where id=?", id)
 	return standuper, err
 }
 
 // ListProjectStandupers returns array of standup entries from database
 func (m *DB) ListProjectStandupers(channelID string) ([]model.Standuper, error) {
 	items := []model.Standuper{}
-	err := m.db.Select(&items, "SELECT * FROM `standupers` WHERE channel_id=?", channelID)
+	err := m.selectAll(&items, "SELECT * FROM standupers WHERE channel_id=?", channelID)
 	return items, err
 }
 
 // ListStandupersByWorkspaceID returns array of standupers which belongs to one team
 func (m *DB) ListStandupersByWorkspaceID(wsID string) ([]model.Standuper, error) {
 	items := []model.Standuper{}
-	err := m.db.Select(&items, "SELECT * FROM `standupers` WHERE workspace_id=?", wsID)
+	err := m.selectAll(&items, "SELECT * FROM standupers WHERE workspace_id=?", wsID)
 	return items, err
 }
 
 // DeleteStanduper deletes standupers entry from database
 func (m *DB) DeleteStanduper(id int64) error {
-	_, err := m.db.Exec("DELETE FROM `standupers` WHERE id=?", id)
+	_, err := m.exec("DELETE FROM standupers WHERE id=?", id)
 	return err
 }
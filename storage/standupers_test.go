@@ -92,6 +92,44 @@ func TestUpdateStanduper(t *testing.T) {
 	s, err = db.UpdateStanduper(s)
 	assert.NoError(t, err)
 	assert.Equal(t, "developer", s.Role)
+	assert.False(t, s.HasSchedule())
+
+	s.Deadline = "11:00"
+	s.TZ = "Europe/Berlin"
+	s.SubmissionDays = "monday, wednesday"
+
+	s, err = db.UpdateStanduper(s)
+	assert.NoError(t, err)
+	assert.Equal(t, "11:00", s.Deadline)
+	assert.Equal(t, "Europe/Berlin", s.TZ)
+	assert.Equal(t, "monday, wednesday", s.SubmissionDays)
+	assert.True(t, s.HasSchedule())
+
+	s.TZ = "Mars/Olympus"
+	_, err = db.UpdateStanduper(s)
+	assert.Error(t, err)
 
 	assert.NoError(t, db.DeleteStanduper(s.ID))
 }
+
+func TestRenameStanduper(t *testing.T) {
+	var ids []int64
+	for _, s := range []model.Standuper{
+		{WorkspaceID: "foo", UserID: "bar", ChannelID: "bar12", RealName: "John"},
+		{WorkspaceID: "foo", UserID: "bar", ChannelID: "bar13", RealName: "John"},
+		{WorkspaceID: "baz", UserID: "bar", ChannelID: "baz12", RealName: "John"},
+	} {
+		s, err := db.CreateStanduper(s)
+		assert.NoError(t, err)
+		ids = append(ids, s.ID)
+	}
+
+	assert.NoError(t, db.RenameStanduper("foo", "bar", "John Smith"))
+
+	for i, name := range []string{"John Smith", "John Smith", "John"} {
+		s, err := db.GetStanduper(ids[i])
+		assert.NoError(t, err)
+		assert.Equal(t, name, s.RealName)
+		assert.NoError(t, db.DeleteStanduper(ids[i]))
+	}
+}
@@ -11,7 +11,7 @@ func (m *DB) CreateStandup(s model.Standup) (model.Standup, error) {
 		return s, err
 	}
 
-	res, err := m.db.Exec(
+	id, err := m.insert(
 		`INSERT INTO standups (
 			created_at,
 			workspace_id, 
@@ -30,10 +30,6 @@ func (m *DB) CreateStandup(s model.Standup) (model.Standup, error) {
 	if err != nil {
 		return s, err
 	}
-	id, err := res.LastInsertId()
-	if err != nil {
-		return s, err
-	}
 	s.ID = id
 
 	return s, nil
@@ -46,46 +42,48 @@ func (m *DB) UpdateStandup(s model.Standup) (model.Standup, error) {
 		return s, err
 	}
 
-	_, err = m.db.Exec(
-		"UPDATE `standups` SET comment=?, message_ts=? WHERE id=?",
+	_, err = m.exec(
+		"UPDATE standups SET comment=?, message_ts=? WHERE id=?",
 		s.Comment, s.MessageTS, s.ID,
 	)
 	if err != nil {
 		return s, err
 	}
 	var i model.Standup
-	err = m.db.Get(&i, "SELECT * FROM `standups` WHERE id=?", s.ID)
+	err = m.get(&i, "SELECT * FROM standups WHERE id=?", s.ID)
 	return i, err
 }
 
 // ListStandups returns array of standup entries from database
 func (m *DB) ListStandups() ([]model.Standup, error) {
 	items := []model.Standup{}
-	err := m.db.Select(&items, "SELECT * FROM `standups` order by id desc")
+	err := m.selectAll(&items, "SELECT * FROM standups order by id desc")
 	return items, err
 }
 
 // ListTeamStandups returns array of standup entries from database
 func (m *DB) ListTeamStandups(teamID string) ([]model.Standup, error) {
 	items := []model.Standup{}
-	err := m.db.Select(&items, "SELECT * FROM `standups` where workspace_id=? order by id desc", teamID)
+	err := m.selectAll(&items, "SELECT * FROM standups where workspace_id=? order by id desc", teamID)
 	return items, err
 }
 
 //GetStandup returns standup by its ID
 func (m *DB) GetStandup(id int64) (model.Standup, error) {
 	var s model.Standup
-	err := m.db.Get(&s, "SELECT * FROM `standups` WHERE id=?", id)
+	err := m.get(&s, "SELECT * FROM standups WHERE id=?", id)
 	if err != nil {
 		return s, err
 	}
 	return s, nil
 }
 
-// SelectStandupByMessageTS selects standup entry from database filtered by MessageTS parameter
-func (m *DB) SelectStandupByMessageTS(messageTS string) (model.Standup, error) {
+// SelectStandupByMessageTS selects standup entry from database filtered by MessageTS parameter.
+// Message timestamps are only unique inside one channel, so the lookup is
+// narrowed to the channel of the workspace
+func (m *DB) SelectStandupByMessageTS(workspaceID, channelID, messageTS string) (model.Standup, error) {
 	var s model.Standup
-	err := m.db.Get(&s, "SELECT * FROM `standups` WHERE message_ts=?", messageTS)
+	err := m.get(&s, "SELECT * FROM standups WHERE workspace_id=? AND channel_id=? AND message_ts=?", workspaceID, channelID, messageTS)
 	if err != nil {
 		return s, err
 	}
@@ -95,7 +93,7 @@ func (m *DB) SelectStandupByMessageTS(messageTS string) (model.Standup, error) {
 // SelectLatestStandupByUser selects standup entry from database filtered by user
 func (m *DB) SelectLatestStandupByUser(userID, channelID string) (model.Standup, error) {
 	var s model.Standup
-	err := m.db.Get(&s,
+	err := m.get(&s,
 		`select * from standups 
 		where user_id=? and channel_id=? 
 		order by id desc limit 1`,
@@ -110,7 +108,7 @@ func (m *DB) SelectLatestStandupByUser(userID, channelID string) (model.Standup,
 // GetStandupForPeriod selects standup entry from database filtered by user
 func (m *DB) GetStandupForPeriod(userID, channelID string, timeFrom, timeTo int64) (*model.Standup, error) {
 	s := &model.Standup{}
-	err := m.db.Get(s,
+	err := m.get(s,
 		`select * from standups 
 		where user_id=? and channel_id=? 
 		and created_at BETWEEN ? AND ? 
@@ -126,8 +124,15 @@ func (m *DB) GetStandupForPeriod(userID, channelID string, timeFrom, timeTo int6
 	return s, nil
 }
 
-// DeleteStandup deletes standup entry from database
+// DeleteStandup deletes standup entry, its answers and blockers from database
 func (m *DB) DeleteStandup(id int64) error {
-	_, err := m.db.Exec("DELETE FROM `standups` WHERE id=?", id)
-	return err
+	_, err := m.exec("DELETE FROM standups WHERE id=?", id)
+	if err != nil {
+		return err
+	}
+	err = m.DeleteStandupBlockers(id)
+	if err != nil {
+		return err
+	}
+	return m.DeleteStandupAnswers(id)
 }
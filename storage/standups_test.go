@@ -47,12 +47,18 @@ func TestGetStandups(t *testing.T) {
 	_, err = db.SelectLatestStandupByUser("foo", "bar12")
 	assert.Error(t, err)
 
-	_, err = db.SelectStandupByMessageTS("2345")
+	_, err = db.SelectStandupByMessageTS("foo", "bar12", "2345")
 	assert.Error(t, err)
 
-	_, err = db.SelectStandupByMessageTS("12345")
+	_, err = db.SelectStandupByMessageTS("foo", "bar12", "12345")
 	assert.NoError(t, err)
 
+	_, err = db.SelectStandupByMessageTS("foo", "bar13", "12345")
+	assert.Error(t, err)
+
+	_, err = db.SelectStandupByMessageTS("bar", "bar12", "12345")
+	assert.Error(t, err)
+
 	_, err = db.GetStandup(int64(0))
 	assert.Error(t, err)
 
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"github.com/maddevsio/comedian/model"
+)
+
+// Store describes everything Comedian needs from a storage backend.
+// DB implements it on top of SQL database, MemoryDB keeps data in memory
+type Store interface {
+	CreateStandup(model.Standup) (model.Standup, error)
+	UpdateStandup(model.Standup) (model.Standup, error)
+	ListStandups() ([]model.Standup, error)
+	ListTeamStandups(teamID string) ([]model.Standup, error)
+	GetStandup(id int64) (model.Standup, error)
+	SelectStandupByMessageTS(workspaceID, channelID, messageTS string) (model.Standup, error)
+	SelectLatestStandupByUser(userID, channelID string) (model.Standup, error)
+	GetStandupForPeriod(userID, channelID string, timeFrom, timeTo int64) (*model.Standup, error)
+	DeleteStandup(id int64) error
+
+	CreateStandupAnswer(model.StandupAnswer) (model.StandupAnswer, error)
+	ListStandupAnswers(standupID int64) ([]model.StandupAnswer, error)
+	ListTeamStandupAnswers(teamID string) ([]model.StandupAnswer, error)
+	DeleteStandupAnswers(standupID int64) error
+
+	CreateDMStandup(model.DMStandup) (model.DMStandup, error)
+	UpdateDMStandup(d model.DMStandup, channelID string, position int) (bool, error)
+	ListUserDMStandups(workspaceID, userID string) ([]model.DMStandup, error)
+	DeleteDMStandup(id int64) error
+
+	CreateBlocker(model.Blocker) (model.Blocker, error)
+	UpdateBlocker(model.Blocker) (model.Blocker, error)
+	GetBlocker(id int64) (model.Blocker, error)
+	ListWorkspaceBlockers(workspaceID, status string) ([]model.Blocker, error)
+	DeleteBlocker(id int64) error
+	DeleteStandupBlockers(standupID int64) error
+
+	CreateStanduper(model.Standuper) (model.Standuper, error)
+	UpdateStanduper(model.Standuper) (model.Standuper, error)
+	RenameStanduper(workspaceID, userID, realName string) error
+	FindStansuperByUserID(userID, channelID string) (model.Standuper, error)
+	FindStansupersByUserID(userID string) ([]model.Standuper, error)
+	ListStandupers() ([]model.Standuper, error)
+	ListWorkspaceStandupers(workspaceID string) ([]model.Standuper, error)
+	GetStanduper(id int64) (model.Standuper, error)
+	ListProjectStandupers(channelID string) ([]model.Standuper, error)
+	ListStandupersByWorkspaceID(wsID string) ([]model.Standuper, error)
+	DeleteStanduper(id int64) error
+
+	CreateProject(model.Project) (model.Project, error)
+	UpdateProject(model.Project) (model.Project, error)
+	RenameProject(channelID, name string) error
+	SetProjectArchived(channelID string, archived bool) error
+	SetProjectBotRemoved(channelID string, removed bool) error
+	ListProjects() ([]model.Project, error)
+	ListWorkspaceProjects(ws string) ([]model.Project, error)
+	SelectProject(channelID string) (model.Project, error)
+	GetProject(id int64) (model.Project, error)
+	DeleteProject(id int64) error
+
+	CreateWorkspace(model.Workspace) (model.Workspace, error)
+	UpdateWorkspace(model.Workspace) (model.Workspace, error)
+	GetAllWorkspaces() ([]model.Workspace, error)
+	GetWorkspaceByWorkspaceID(workspaceID string) (model.Workspace, error)
+	GetWorkspaceByBotAccessToken(botAccessToken string) (model.Workspace, error)
+	GetWorkspace(id int64) (model.Workspace, error)
+	DeleteWorkspaceByID(id int64) error
+	DeleteWorkspace(teamID string) error
+
+	CreateNotificationThread(model.NotificationThread) (model.NotificationThread, error)
+	DeleteNotificationThread(id int64) error
+	SelectNotificationsThread(channelID string) (model.NotificationThread, error)
+	SelectUserNotificationsThread(channelID, userID string) (model.NotificationThread, error)
+	SelectSlotNotificationsThread(channelID string, slotID int64) (model.NotificationThread, error)
+	UpdateNotificationThread(id int64, notificationTime int64, nonReporters string) error
+
+	CreateJob(model.Job) (model.Job, error)
+	UpdateJob(model.Job) (model.Job, error)
+	ClaimJob(id, scheduledAt, until int64) (bool, error)
+	GetJob(id int64) (model.Job, error)
+	ListWorkspaceJobs(workspaceID string) ([]model.Job, error)
+	DeleteJob(id int64) error
+	CreateJobRun(model.JobRun) (model.JobRun, error)
+	ListJobRuns(jobID int64) ([]model.JobRun, error)
+
+	AcquireLease(name, holder string, now, expiresAt int64) (bool, error)
+	ReleaseLease(name, holder string) error
+	ListLeases() ([]model.Lease, error)
+
+	RecordEvent(model.Event) (model.Event, bool, error)
+	ClaimEvent(id, now, until int64) (bool, error)
+	UpdateEvent(model.Event) (model.Event, error)
+	GetEvent(id int64) (model.Event, error)
+	ListDueEvents(now int64, maxAttempts, limit int) ([]model.Event, error)
+	ListWorkspaceEvents(workspaceID, status string, limit int) ([]model.Event, error)
+	DeleteDoneEvents(before int64) error
+
+	CreateOutboxMessage(model.OutboxMessage) (model.OutboxMessage, error)
+	ClaimOutboxMessage(id, now, until int64) (bool, error)
+	UpdateOutboxMessage(model.OutboxMessage) (model.OutboxMessage, error)
+	GetOutboxMessage(id int64) (model.OutboxMessage, error)
+	ListDueOutboxMessages(workspaceID string, now int64, limit int) ([]model.OutboxMessage, error)
+	ListWorkspaceOutboxMessages(workspaceID, status string, limit int) ([]model.OutboxMessage, error)
+	HasUnsentOutboxMessages(workspaceID string, beforeID int64) (bool, error)
+	DeleteSentOutboxMessages(workspaceID string, before int64) error
+	TakeOutboxToken(workspaceID string, now, interval int64, burst int) (bool, error)
+	PauseOutbox(workspaceID string, until int64) error
+
+	CreateAbsence(model.Absence) (model.Absence, error)
+	GetAbsence(id int64) (model.Absence, error)
+	DeleteAbsence(id int64) error
+	ListWorkspaceAbsences(workspaceID, from string) ([]model.Absence, error)
+	ListUserAbsences(workspaceID, userID, from, to string) ([]model.Absence, error)
+
+	CreateHolidayCalendar(model.HolidayCalendar) (model.HolidayCalendar, error)
+	UpdateHolidayCalendar(model.HolidayCalendar) (model.HolidayCalendar, error)
+	GetHolidayCalendar(id int64) (model.HolidayCalendar, error)
+	ListWorkspaceHolidayCalendars(workspaceID string) ([]model.HolidayCalendar, error)
+	DeleteHolidayCalendar(id int64) error
+	CreateHoliday(model.Holiday) (model.Holiday, error)
+	GetHoliday(id int64) (model.Holiday, error)
+	DeleteHoliday(id int64) error
+	ListCalendarHolidays(calendarID int64) ([]model.Holiday, error)
+	ListChannelHolidays(workspaceID, channelID, from, to string) ([]model.Holiday, error)
+
+	CreateDeadlineSlot(model.DeadlineSlot) (model.DeadlineSlot, error)
+	UpdateDeadlineSlot(model.DeadlineSlot) (model.DeadlineSlot, error)
+	GetDeadlineSlot(id int64) (model.DeadlineSlot, error)
+	DeleteDeadlineSlot(id int64) error
+	ListChannelDeadlineSlots(channelID string) ([]model.DeadlineSlot, error)
+	ListWorkspaceDeadlineSlots(workspaceID string) ([]model.DeadlineSlot, error)
+}
+
+var (
+	_ Store = &DB{}
+	_ Store = &MemoryDB{}
+)
@@ -11,7 +11,7 @@ func (m *DB) CreateWorkspace(bs model.Workspace) (model.Workspace, error) {
 		return bs, err
 	}
 
-	res, err := m.db.Exec(
+	id, err := m.insert(
 		`INSERT INTO workspaces (
 			created_at,
 			notifier_interval, 
@@ -24,8 +24,12 @@ func (m *DB) CreateWorkspace(bs model.Workspace) (model.Workspace, error) {
 			projects_reports_enabled, 
 			reporting_channel, 
 			reporting_time, 
-			language
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			language,
+			platform,
+			server_url,
+			blocker_escalation_days,
+			tz
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		bs.CreatedAt,
 		bs.NotifierInterval,
 		bs.MaxReminders,
@@ -38,16 +42,15 @@ func (m *DB) CreateWorkspace(bs model.Workspace) (model.Workspace, error) {
 		bs.ReportingChannel,
 		bs.ReportingTime,
 		bs.Language,
+		bs.Platform,
+		bs.ServerURL,
+		bs.BlockerEscalationDays,
+		bs.TZ,
 	)
 	if err != nil {
 		return bs, err
 	}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return bs, err
-	}
-
 	bs.ID = id
 
 	return bs, nil
@@ -60,7 +63,7 @@ func (m *DB) UpdateWorkspace(settings model.Workspace) (model.Workspace, error)
 		return settings, err
 	}
 
-	_, err = m.db.Exec(
+	_, err = m.exec(
 		`UPDATE workspaces set 
 			notifier_interval=?, 
 			max_reminders=?, 
@@ -72,7 +75,11 @@ func (m *DB) UpdateWorkspace(settings model.Workspace) (model.Workspace, error)
 			projects_reports_enabled=?, 
 			reporting_channel=?, 
 			reporting_time=?, 
-			language=?
+			language=?,
+			platform=?,
+			server_url=?,
+			blocker_escalation_days=?,
+			tz=?
 			where id=?`,
 		settings.NotifierInterval,
 		settings.MaxReminders,
@@ -85,6 +92,10 @@ func (m *DB) UpdateWorkspace(settings model.Workspace) (model.Workspace, error)
 		settings.ReportingChannel,
 		settings.ReportingTime,
 		settings.Language,
+		settings.Platform,
+		settings.ServerURL,
+		settings.BlockerEscalationDays,
+		settings.TZ,
 		settings.ID,
 	)
 	if err != nil {
@@ -96,7 +107,7 @@ func (m *DB) UpdateWorkspace(settings model.Workspace) (model.Workspace, error)
 //GetAllWorkspaces returns all workspaces stored in DB
 func (m *DB) GetAllWorkspaces() ([]model.Workspace, error) {
 	bs := []model.Workspace{}
-	err := m.db.Select(&bs, "SELECT * FROM `workspaces`")
+	err := m.selectAll(&bs, "SELECT * FROM workspaces")
 	if err != nil {
 		return bs, err
 	}
@@ -106,7 +117,7 @@ func (m *DB) GetAllWorkspaces() ([]model.Workspace, error) {
 //GetWorkspaceByWorkspaceID returns a particular bot
 func (m *DB) GetWorkspaceByWorkspaceID(workspaceID string) (model.Workspace, error) {
 	bs := model.Workspace{}
-	err := m.db.Get(&bs, "SELECT * FROM `workspaces` where workspace_id=?", workspaceID)
+	err := m.get(&bs, "SELECT * FROM workspaces where workspace_id=?", workspaceID)
 	if err != nil {
 		return bs, err
 	}
@@ -116,7 +127,7 @@ func (m *DB) GetWorkspaceByWorkspaceID(workspaceID string) (model.Workspace, err
 //GetWorkspaceByBotAccessToken returns a particular bot
 func (m *DB) GetWorkspaceByBotAccessToken(botAccessToken string) (model.Workspace, error) {
 	bs := model.Workspace{}
-	err := m.db.Get(&bs, "SELECT * FROM `workspaces` where bot_access_token=?", botAccessToken)
+	err := m.get(&bs, "SELECT * FROM workspaces where bot_access_token=?", botAccessToken)
 	if err != nil {
 		return bs, err
 	}
@@ -126,7 +137,7 @@ func (m *DB) GetWorkspaceByBotAccessToken(botAccessToken string) (model.Workspac
 //GetWorkspace returns a particular bot
 func (m *DB) GetWorkspace(id int64) (model.Workspace, error) {
 	bs := model.Workspace{}
-	err := m.db.Get(&bs, "SELECT * FROM `workspaces` where id=?", id)
+	err := m.get(&bs, "SELECT * FROM workspaces where id=?", id)
 	if err != nil {
 		return bs, err
 	}
@@ -135,12 +146,12 @@ func (m *DB) GetWorkspace(id int64) (model.Workspace, error) {
 
 //DeleteWorkspaceByID deletes bot
 func (m *DB) DeleteWorkspaceByID(id int64) error {
-	_, err := m.db.Exec("DELETE FROM `workspaces` WHERE id=?", id)
+	_, err := m.exec("DELETE FROM workspaces WHERE id=?", id)
 	return err
 }
 
 //DeleteWorkspace deletes bot
 func (m *DB) DeleteWorkspace(teamID string) error {
-	_, err := m.db.Exec("DELETE FROM `workspaces` WHERE workspace_id=?", teamID)
+	_, err := m.exec("DELETE FROM workspaces WHERE workspace_id=?", teamID)
 	return err
 }
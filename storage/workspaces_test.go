@@ -94,10 +94,49 @@ func TestUpdateAndDeleteWorkspace(t *testing.T) {
 	assert.Equal(t, "en_US", bot.Language)
 
 	bot.Language = "ru_RU"
+	bot.TZ = "Europe/Berlin"
 
 	bot, err = db.UpdateWorkspace(bot)
 	assert.NoError(t, err)
 	assert.Equal(t, "ru_RU", bot.Language)
 
+	stored, err := db.GetWorkspace(bot.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", stored.TZ)
+
+	bot.TZ = "Mars/Olympus"
+	_, err = db.UpdateWorkspace(bot)
+	assert.EqualError(t, err, "unknown time zone Mars/Olympus")
+
 	assert.NoError(t, db.DeleteWorkspace(bot.WorkspaceID))
 }
+
+func TestMattermostWorkspace(t *testing.T) {
+	ws, err := db.CreateWorkspace(model.Workspace{
+		Language:       "en",
+		ReminderOffset: int64(10),
+		BotAccessToken: "mmtoken",
+		BotUserID:      "botid",
+		WorkspaceID:    "mmteam",
+		WorkspaceName:  "devs",
+		ReportingTime:  "10am",
+		Platform:       model.PlatformMattermost,
+		ServerURL:      "https://chat.example.com",
+	})
+	assert.NoError(t, err)
+
+	ws, err = db.GetWorkspaceByWorkspaceID("mmteam")
+	assert.NoError(t, err)
+	assert.Equal(t, model.PlatformMattermost, ws.Platform)
+	assert.Equal(t, "https://chat.example.com", ws.ServerURL)
+
+	ws.ServerURL = "https://mattermost.example.com"
+	_, err = db.UpdateWorkspace(ws)
+	assert.NoError(t, err)
+
+	ws, err = db.GetWorkspace(ws.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://mattermost.example.com", ws.ServerURL)
+
+	assert.NoError(t, db.DeleteWorkspaceByID(ws.ID))
+}